@@ -0,0 +1,39 @@
+// =============================================================================
+// audio.go - Runtime Sound Toggle (.audio)
+// =============================================================================
+//
+// --silent only applies when the CLI launches a server. ".audio" mutes or
+// unmutes the emulator at any time without restarting it:
+//
+//	.audio off
+//	.audio on
+//
+// =============================================================================
+
+package main
+
+import (
+	"strings"
+
+	"github.com/attic/atticprotocol"
+)
+
+// runAudioCommand handles ".audio on|off".
+func runAudioCommand(client *atticprotocol.Client, args string, opts replOptions) {
+	var enabled bool
+	switch strings.ToLower(strings.TrimSpace(args)) {
+	case "on":
+		enabled = true
+	case "off":
+		enabled = false
+	default:
+		printError("usage: .audio on|off")
+		return
+	}
+
+	if !opts.dryRun && !client.IsConnected() {
+		printError("not connected (use .connect <socket>)")
+		return
+	}
+	sendCommand(client, atticprotocol.NewAudioCommand(enabled), opts)
+}
@@ -0,0 +1,46 @@
+// =============================================================================
+// audio_test.go - Tests for the Runtime Sound Toggle (audio.go)
+// =============================================================================
+
+package main
+
+import (
+	"strings"
+	"sync"
+	"testing"
+)
+
+// TestREPLAudio verifies that .audio sends the audio command to the server.
+func TestREPLAudio(t *testing.T) {
+	var mu sync.Mutex
+	var received []string
+	output := captureREPL(t, ".audio off\n.audio ON\n.quit\n", func(cmd string) string {
+		if cmd == "ping" {
+			return "OK:pong\n"
+		}
+		mu.Lock()
+		received = append(received, cmd)
+		mu.Unlock()
+		return "OK:" + cmd + "\n"
+	})
+
+	mu.Lock()
+	defer mu.Unlock()
+	if got := strings.Join(received, ","); got != "audio off,audio on" {
+		t.Errorf("server received %q, want %q", got, "audio off,audio on")
+	}
+	if !strings.Contains(output, "audio off") {
+		t.Errorf("expected response in output, got:\n%s", output)
+	}
+}
+
+// TestREPLAudioInvalid verifies that anything but on or off is rejected
+// without sending a command.
+func TestREPLAudioInvalid(t *testing.T) {
+	for _, input := range []string{".audio\n", ".audio loud\n"} {
+		output := captureREPLWithOptions(t, input+".quit\n", nil, replOptions{dryRun: true})
+		if strings.Contains(output, "CMD:audio") {
+			t.Errorf("%q: should not send a command, got:\n%s", strings.TrimSpace(input), output)
+		}
+	}
+}
@@ -0,0 +1,190 @@
+// =============================================================================
+// autopause.go - Pausing the Emulator Before Memory Writes (--auto-pause)
+// =============================================================================
+//
+// The server only writes memory while the emulator is paused, so a monitor
+// ">" or "f" typed while a program runs fails. With --auto-pause (on by
+// default) the REPL pauses the emulator first:
+//
+//	--auto-pause on       pause before a write and leave the emulator paused
+//	--auto-pause resume   pause before a write and resume after it
+//	--auto-pause off      send writes as typed
+//
+// This applies to write, fill and pattern commands in monitor mode. The
+// REPL follows whether the emulator is running from the commands it sends,
+// status responses and stop/breakpoint events, and asks for the status only
+// when a write is due and the state is not known.
+//
+// =============================================================================
+
+package main
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/attic/atticprotocol"
+)
+
+// autoPauseMode is the --auto-pause policy.
+type autoPauseMode int
+
+const (
+	// autoPauseOff sends writes as typed.
+	autoPauseOff autoPauseMode = iota
+	// autoPauseOn pauses a running emulator before a write.
+	autoPauseOn
+	// autoPauseResume pauses before a write and resumes after it.
+	autoPauseResume
+)
+
+// parseAutoPauseMode converts an --auto-pause argument to an autoPauseMode.
+func parseAutoPauseMode(s string) (autoPauseMode, bool) {
+	switch strings.ToLower(s) {
+	case "on":
+		return autoPauseOn, true
+	case "resume":
+		return autoPauseResume, true
+	case "off":
+		return autoPauseOff, true
+	default:
+		return autoPauseOff, false
+	}
+}
+
+// runState follows whether the emulator is running. Events arrive on the
+// client's reader goroutine, so it is guarded by a mutex.
+type runState struct {
+	mu      sync.Mutex
+	known   bool
+	running bool
+}
+
+// set records a known state.
+func (s *runState) set(running bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.known, s.running = true, running
+}
+
+// forget marks the state as unknown.
+func (s *runState) forget() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.known = false
+}
+
+// get returns the state and whether it is known.
+func (s *runState) get() (running, known bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.running, s.known
+}
+
+// noteEvent updates the state from an async event.
+func (s *runState) noteEvent(event atticprotocol.Event) {
+	switch event.Type {
+	case atticprotocol.EventBreakpoint, atticprotocol.EventStopped:
+		s.set(false)
+	}
+}
+
+// noteCommand updates the state after cmdType was sent and answered with
+// resp. Commands that may start or stop the emulator in ways the response
+// doesn't show make the state unknown.
+func (s *runState) noteCommand(cmdType atticprotocol.CommandType, resp atticprotocol.Response) {
+	if !resp.IsOK() {
+		return
+	}
+	switch cmdType {
+	case atticprotocol.CmdPause:
+		s.set(false)
+	case atticprotocol.CmdResume, atticprotocol.CmdRunUntil:
+		s.set(true)
+	case atticprotocol.CmdStatus:
+		if status, err := resp.AsStatus(); err == nil {
+			s.set(status.Running)
+		}
+	case atticprotocol.CmdStep, atticprotocol.CmdFrameStep, atticprotocol.CmdStepInstruction,
+		atticprotocol.CmdStepOver, atticprotocol.CmdStepOut, atticprotocol.CmdReset,
+		atticprotocol.CmdBoot, atticprotocol.CmdStateLoad, atticprotocol.CmdBasicRun,
+		atticprotocol.CmdBasicStop, atticprotocol.CmdBasicCont, atticprotocol.CmdBasicStep:
+		s.forget()
+	}
+}
+
+// isRunning returns the state, asking the server if it is not known.
+func (s *runState) isRunning(client *atticprotocol.Client) (bool, error) {
+	if running, known := s.get(); known {
+		return running, nil
+	}
+	resp, err := client.Send(atticprotocol.NewStatusCommand())
+	if err == nil {
+		err = resp.Err()
+	}
+	if err != nil {
+		return false, err
+	}
+	status, err := resp.AsStatus()
+	if err != nil {
+		return false, err
+	}
+	s.set(status.Running)
+	return status.Running, nil
+}
+
+// lineCommandTypes returns the types of the protocol commands line
+// translates to, skipping any that don't parse.
+func lineCommandTypes(line string, mode REPLMode, atascii bool) []atticprotocol.CommandType {
+	parser := atticprotocol.NewCommandParser()
+	var types []atticprotocol.CommandType
+	for _, text := range translateToProtocol(line, mode, atascii) {
+		if cmd, err := parser.Parse(text); err == nil {
+			types = append(types, cmd.Type)
+		}
+	}
+	return types
+}
+
+// writesMemory reports whether any of types stores into memory.
+func writesMemory(types []atticprotocol.CommandType) bool {
+	for _, t := range types {
+		switch t {
+		case atticprotocol.CmdWrite, atticprotocol.CmdMemoryFill, atticprotocol.CmdMemoryPattern:
+			return true
+		}
+	}
+	return false
+}
+
+// pauseForWrite pauses the emulator if it is running. It returns true if
+// it paused it, so the caller can resume after the write.
+func pauseForWrite(client *atticprotocol.Client, state *runState) (bool, error) {
+	running, err := state.isRunning(client)
+	if err != nil || !running {
+		return false, err
+	}
+	resp, err := client.Send(atticprotocol.NewPauseCommand())
+	if err == nil {
+		err = resp.Err()
+	}
+	if err != nil {
+		return false, fmt.Errorf("pause before write: %w", err)
+	}
+	state.set(false)
+	return true, nil
+}
+
+// resumeAfterWrite resumes an emulator that pauseForWrite paused.
+func resumeAfterWrite(client *atticprotocol.Client, state *runState) {
+	resp, err := client.Send(atticprotocol.NewResumeCommand())
+	if err == nil {
+		err = resp.Err()
+	}
+	if err != nil {
+		printError(fmt.Sprintf("resume after write: %v", err))
+		return
+	}
+	state.set(true)
+}
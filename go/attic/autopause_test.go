@@ -0,0 +1,163 @@
+// =============================================================================
+// autopause_test.go - Tests for Pausing Before Memory Writes (autopause.go)
+// =============================================================================
+
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/attic/atticprotocol"
+)
+
+// autoPauseHandler returns a mock server handler for an emulator that is
+// running until paused, and a pointer to the commands it received.
+func autoPauseHandler(running bool) (func(cmd string) string, *[]string) {
+	var received []string
+	return func(cmd string) string {
+		if cmd == "ping" {
+			return "OK:pong\n"
+		}
+		received = append(received, cmd)
+		switch {
+		case cmd == "status":
+			if running {
+				return "OK:status running PC=$E477\n"
+			}
+			return "OK:status paused PC=$0600\n"
+		case cmd == "pause":
+			running = false
+			return "OK:paused\n"
+		case cmd == "resume":
+			running = true
+			return "OK:resumed\n"
+		case strings.HasPrefix(cmd, ">"), strings.HasPrefix(cmd, "f "):
+			if running {
+				return "ERR:Emulator must be paused\n"
+			}
+			return "OK:written\n"
+		}
+		return "OK:\n"
+	}, &received
+}
+
+// TestAutoPauseBeforeWrite verifies that a pause is injected before a
+// write while the emulator runs, and left out when it is paused or the
+// option is off.
+func TestAutoPauseBeforeWrite(t *testing.T) {
+	tests := []struct {
+		name    string
+		running bool
+		mode    autoPauseMode
+		want    []string
+	}{
+		{"running", true, autoPauseOn, []string{"status", "pause", "write $0600 A9"}},
+		{"resume", true, autoPauseResume, []string{"status", "pause", "write $0600 A9", "resume"}},
+		{"paused", false, autoPauseOn, []string{"status", "write $0600 A9"}},
+		{"off", true, autoPauseOff, []string{"write $0600 A9"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler, received := autoPauseHandler(tt.running)
+			output := captureREPLWithOptions(t, ".monitor\n> $0600 A9\n.quit\n", handler, replOptions{autoPause: tt.mode})
+			if got := strings.Join(*received, "|"); got != strings.Join(tt.want, "|") {
+				t.Errorf("commands = %q, want %q", *received, tt.want)
+			}
+			if noted := strings.Contains(output, "Paused the emulator"); noted != (tt.mode == autoPauseOn && tt.running) {
+				t.Errorf("pause note shown = %v, output:\n%s", noted, output)
+			}
+		})
+	}
+}
+
+// TestAutoPauseTracksState verifies that a known state saves the status
+// query and that only memory writes pause.
+func TestAutoPauseTracksState(t *testing.T) {
+	handler, received := autoPauseHandler(true)
+	input := ".monitor\npause\nf $0600 $06FF 00\nresume\nm $0600 16\n> $0600 A9\n.quit\n"
+	captureREPLWithOptions(t, input, handler, replOptions{autoPause: autoPauseOn})
+
+	want := []string{"pause", "fill $0600 $06FF 00", "resume", "read $0600 16", "pause", "write $0600 A9"}
+	if got := strings.Join(*received, "|"); got != strings.Join(want, "|") {
+		t.Errorf("commands = %q, want %q", *received, want)
+	}
+}
+
+// TestAutoPauseAfterReset verifies that a reset makes the state unknown,
+// so the next write asks for the status again instead of trusting an
+// earlier pause.
+func TestAutoPauseAfterReset(t *testing.T) {
+	handler, received := autoPauseHandler(true)
+	input := ".monitor\npause\n.reset\n> $0600 A9\n.quit\n"
+	captureREPLWithOptions(t, input, handler, replOptions{autoPause: autoPauseOn})
+
+	want := []string{"pause", "reset cold", "status", "write $0600 A9"}
+	if got := strings.Join(*received, "|"); got != strings.Join(want, "|") {
+		t.Errorf("commands = %q, want %q", *received, want)
+	}
+}
+
+// TestAutoPauseLoadBin verifies that .loadbin pauses a running emulator
+// like a monitor write does.
+func TestAutoPauseLoadBin(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "code.bin")
+	if err := os.WriteFile(path, []byte{0xA9, 0x00}, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		name string
+		mode autoPauseMode
+		want []string
+	}{
+		{"on", autoPauseOn, []string{"status", "pause", "write $0600 A9,00"}},
+		{"resume", autoPauseResume, []string{"status", "pause", "write $0600 A9,00", "resume"}},
+		{"off", autoPauseOff, []string{"write $0600 A9,00"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler, received := autoPauseHandler(true)
+			captureREPLWithOptions(t, ".loadbin $0600 "+path+"\n.quit\n", handler, replOptions{autoPause: tt.mode})
+			if got := strings.Join(*received, "|"); got != strings.Join(tt.want, "|") {
+				t.Errorf("commands = %q, want %q", *received, tt.want)
+			}
+		})
+	}
+}
+
+// TestRunStateEvents verifies that stop events mark the emulator paused.
+func TestRunStateEvents(t *testing.T) {
+	var state runState
+	if _, known := state.get(); known {
+		t.Fatal("zero runState should be unknown")
+	}
+	state.set(true)
+	state.noteEvent(atticprotocol.Event{Type: atticprotocol.EventBreakpoint, Address: 0x0600})
+	if running, known := state.get(); running || !known {
+		t.Errorf("after breakpoint: running=%v known=%v, want paused", running, known)
+	}
+	state.noteCommand(atticprotocol.CmdReset, atticprotocol.Response{Type: atticprotocol.ResponseOK, Data: "reset"})
+	if _, known := state.get(); known {
+		t.Error("reset should make the state unknown")
+	}
+}
+
+func TestParseAutoPauseMode(t *testing.T) {
+	tests := []struct {
+		in   string
+		want autoPauseMode
+		ok   bool
+	}{
+		{"on", autoPauseOn, true},
+		{"RESUME", autoPauseResume, true},
+		{"off", autoPauseOff, true},
+		{"sometimes", autoPauseOff, false},
+	}
+	for _, tt := range tests {
+		if got, ok := parseAutoPauseMode(tt.in); got != tt.want || ok != tt.ok {
+			t.Errorf("parseAutoPauseMode(%q) = %v, %v; want %v, %v", tt.in, got, ok, tt.want, tt.ok)
+		}
+	}
+}
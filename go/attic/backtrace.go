@@ -0,0 +1,155 @@
+// =============================================================================
+// backtrace.go - Guessing the Call Stack (.bt)
+// =============================================================================
+//
+// The 6502 keeps no frame pointers, so there is no reliable call stack.
+// ".bt" makes a best guess from the stack page: JSR pushes its return
+// address minus one, so each pair of bytes on the live stack (above S) that
+// points just past a JSR is probably a caller:
+//
+//	.bt
+//	#0   $0642  LDA #$00          PC
+//	#1   $0615  JSR $0640         returns to $0618 (stack $01FC)
+//	#2?  $2A0F  LDA $3F20,X       returns to $2A12 (stack $01FA)
+//
+// Entries marked "?" are pairs that look like code addresses but don't
+// follow a JSR: saved registers or data pushed with PHA that happen to
+// resemble an address. They are shown so nothing is hidden, but should be
+// read with suspicion. Pairs that overlap a confirmed return address are
+// left out, since their bytes are already accounted for.
+//
+// =============================================================================
+
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/attic/atticprotocol"
+)
+
+// stackPage is the 6502 hardware stack.
+const stackPage = 0x0100
+
+// returnCandidate is a pair of stack bytes that may be a JSR return
+// address.
+type returnCandidate struct {
+	StackAddr  uint16 // Address of the low byte on the stack page
+	ReturnAddr uint16 // Where RTS would continue (pushed value + 1)
+}
+
+// callSite returns the address of the JSR that would have pushed c.
+func (c returnCandidate) callSite() uint16 {
+	return c.ReturnAddr - 3
+}
+
+// isPlausibleCodeAddress reports whether code could run at addr: not in
+// zero page, the stack page or the hardware registers.
+func isPlausibleCodeAddress(addr uint16) bool {
+	return addr >= 0x0200 && (addr < 0xD000 || addr >= 0xD800)
+}
+
+// findReturnCandidates scans the live part of the stack page, above the
+// stack pointer s, for byte pairs that look like return addresses, from
+// the top of the stack down. stack holds the whole page, $0100-$01FF.
+// Candidates may overlap; pickFrames sorts them out.
+func findReturnCandidates(s byte, stack []byte) []returnCandidate {
+	var out []returnCandidate
+	for i := int(s) + 1; i+1 < len(stack) && i+1 <= 0xFF; i++ {
+		ret := (uint16(stack[i]) | uint16(stack[i+1])<<8) + 1
+		if isPlausibleCodeAddress(ret) && isPlausibleCodeAddress(ret-3) {
+			out = append(out, returnCandidate{StackAddr: stackPage + uint16(i), ReturnAddr: ret})
+		}
+	}
+	return out
+}
+
+// stackFrame is a candidate shown by .bt.
+type stackFrame struct {
+	returnCandidate
+	Confirmed bool // The call site holds a JSR
+}
+
+// pickFrames turns candidates into frames, given which ones follow a JSR.
+// An unconfirmed candidate that shares a byte with a confirmed one is
+// dropped.
+func pickFrames(candidates []returnCandidate, confirmed []bool) []stackFrame {
+	isConfirmed := make(map[uint16]bool)
+	for i, c := range candidates {
+		if confirmed[i] {
+			isConfirmed[c.StackAddr] = true
+		}
+	}
+	var frames []stackFrame
+	for i, c := range candidates {
+		if !confirmed[i] && (isConfirmed[c.StackAddr-1] || isConfirmed[c.StackAddr+1]) {
+			continue
+		}
+		frames = append(frames, stackFrame{returnCandidate: c, Confirmed: confirmed[i]})
+	}
+	return frames
+}
+
+// runBacktraceCommand handles ".bt".
+func runBacktraceCommand(client *atticprotocol.Client, opts replOptions) {
+	if opts.dryRun {
+		fmt.Println("CMD:" + atticprotocol.NewRegistersCommand(nil).Format())
+		fmt.Println("CMD:" + atticprotocol.NewReadCommand(stackPage, 256).Format())
+		fmt.Println("CMD:disassemble $<CALLER> 1")
+		return
+	}
+	if !client.IsConnected() {
+		printError("not connected (use .connect <socket>)")
+		return
+	}
+
+	resp, err := client.Send(atticprotocol.NewRegistersCommand(nil))
+	if err != nil {
+		printError(err.Error())
+		return
+	}
+	regs, err := resp.AsRegisters()
+	if err != nil {
+		printError(err.Error())
+		return
+	}
+	stack, err := readMemory(client, stackPage, 256)
+	if err != nil {
+		printError(err.Error())
+		return
+	}
+
+	candidates := findReturnCandidates(regs.S, stack)
+	texts := make(map[uint16]string)
+	confirmed := make([]bool, len(candidates))
+	for i, c := range candidates {
+		texts[c.callSite()] = disassembleOne(client, c.callSite())
+		confirmed[i] = strings.HasPrefix(strings.ToUpper(texts[c.callSite()]), "JSR ")
+	}
+
+	fmt.Printf("#0   $%04X  %-17s PC\n", regs.PC, disassembleOne(client, regs.PC))
+	for n, f := range pickFrames(candidates, confirmed) {
+		label := fmt.Sprintf("#%d", n+1)
+		if !f.Confirmed {
+			label += "?"
+		}
+		fmt.Printf("%-4s $%04X  %-17s returns to $%04X (stack $%04X)\n",
+			label, f.callSite(), texts[f.callSite()], f.ReturnAddr, f.StackAddr)
+	}
+}
+
+// disassembleOne returns the instruction at addr, or "???" if it can't be
+// disassembled.
+func disassembleOne(client *atticprotocol.Client, addr uint16) string {
+	lines := 1
+	resp, err := client.Send(atticprotocol.NewDisassembleCommand(&addr, &lines))
+	if err != nil {
+		return "???"
+	}
+	listing, err := resp.AsDisassembly()
+	if err != nil || len(listing) == 0 {
+		return "???"
+	}
+	return listing[0].Instruction
+}
@@ -0,0 +1,99 @@
+// =============================================================================
+// backtrace_test.go - Tests for Guessing the Call Stack (backtrace.go)
+// =============================================================================
+
+package main
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+// testStackImage returns a stack page with S=$F8: a saved register ($33)
+// below two JSR return addresses, $0617 (to $0618) and $2A11 (to $2A12).
+func testStackImage() []byte {
+	stack := make([]byte, 256)
+	copy(stack[0xF9:], []byte{0x33, 0x17, 0x06, 0x11, 0x2A, 0x00, 0xD2})
+	// Stale bytes below S must be ignored.
+	copy(stack[0xF0:], []byte{0x00, 0x30})
+	return stack
+}
+
+// TestFindReturnCandidates verifies that every plausible pair above S is
+// found, including ones straddling two real entries.
+func TestFindReturnCandidates(t *testing.T) {
+	got := findReturnCandidates(0xF8, testStackImage())
+	want := []returnCandidate{
+		{StackAddr: 0x01F9, ReturnAddr: 0x1734},
+		{StackAddr: 0x01FA, ReturnAddr: 0x0618},
+		{StackAddr: 0x01FB, ReturnAddr: 0x1107},
+		{StackAddr: 0x01FC, ReturnAddr: 0x2A12},
+	}
+	if fmt.Sprint(got) != fmt.Sprint(want) {
+		t.Errorf("findReturnCandidates = %+v, want %+v", got, want)
+	}
+	if site := got[1].callSite(); site != 0x0615 {
+		t.Errorf("callSite() = $%04X, want $0615", site)
+	}
+}
+
+// TestPickFrames verifies that pairs overlapping a confirmed return
+// address are dropped and other unconfirmed ones kept.
+func TestPickFrames(t *testing.T) {
+	candidates := findReturnCandidates(0xF8, testStackImage())
+	frames := pickFrames(candidates, []bool{false, true, false, false})
+	want := []stackFrame{
+		{returnCandidate{StackAddr: 0x01FA, ReturnAddr: 0x0618}, true},
+		{returnCandidate{StackAddr: 0x01FC, ReturnAddr: 0x2A12}, false},
+	}
+	if fmt.Sprint(frames) != fmt.Sprint(want) {
+		t.Errorf("pickFrames = %+v, want %+v", frames, want)
+	}
+}
+
+// TestFindReturnCandidatesEmptyStack verifies that a full-height stack
+// (S=$FF) has no candidates.
+func TestFindReturnCandidatesEmptyStack(t *testing.T) {
+	if got := findReturnCandidates(0xFF, testStackImage()); len(got) != 0 {
+		t.Errorf("findReturnCandidates with S=$FF = %+v, want none", got)
+	}
+}
+
+// TestREPLBacktrace verifies that entries not following a JSR are marked.
+func TestREPLBacktrace(t *testing.T) {
+	stack := testStackImage()
+	output := captureREPL(t, ".bt\n.quit\n", func(cmd string) string {
+		switch {
+		case cmd == "ping":
+			return "OK:pong\n"
+		case cmd == "registers":
+			return "OK:A=$00 X=$00 Y=$00 S=$F8 P=$30 PC=$0642\n"
+		case cmd == "read $0100 256":
+			hex := make([]string, len(stack))
+			for i, b := range stack {
+				hex[i] = fmt.Sprintf("%02X", b)
+			}
+			return "OK:data " + strings.Join(hex, ",") + "\n"
+		case cmd == "disassemble $0642 1":
+			return "OK:$0642  A9 00     LDA #$00\n"
+		case cmd == "disassemble $0615 1":
+			return "OK:$0615  20 40 06  JSR $0640\n"
+		case cmd == "disassemble $2A0F 1":
+			return "OK:$2A0F  BD 20 3F  LDA $3F20,X\n"
+		case strings.HasPrefix(cmd, "disassemble "):
+			return "OK:$0000  EA        NOP\n"
+		}
+		return "ERR:unexpected " + cmd + "\n"
+	})
+
+	for _, want := range []string{
+		"#0   $0642  LDA #$00          PC\n",
+		"#1   $0615  JSR $0640         returns to $0618 (stack $01FA)\n",
+		"#2?  $2A0F  LDA $3F20,X       returns to $2A12 (stack $01FC)\n",
+	} {
+		if !strings.Contains(output, want) {
+			t.Errorf("expected %q in output, got:\n%s", want, output)
+		}
+	}
+}
@@ -0,0 +1,78 @@
+// =============================================================================
+// basickeywords.go - Atari BASIC Keywords and BASIC Mode Commands
+// =============================================================================
+//
+// The words BASIC mode knows about, in one place for tab completion
+// (completion.go) and ".help basic":
+//
+//   - basicKeywords: Atari BASIC statements, functions and operators, typed
+//     into program lines and direct-mode statements.
+//   - basicModeCommands: the REPL's BASIC mode commands, which are sent to
+//     the server's "basic" command instead of being typed (see
+//     translateBASICCommand).
+//
+// =============================================================================
+
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// basicKeywords are the Atari BASIC reserved words.
+var basicKeywords = []string{
+	// Statements
+	"BYE", "CLOAD", "CLOSE", "CLR", "COLOR", "COM", "CONT", "CSAVE", "DATA",
+	"DEG", "DIM", "DOS", "DRAWTO", "END", "ENTER", "FOR", "GET", "GOSUB",
+	"GOTO", "GRAPHICS", "IF", "INPUT", "LET", "LIST", "LOAD", "LOCATE",
+	"LPRINT", "NEW", "NEXT", "NOTE", "ON", "OPEN", "PLOT", "POINT", "POKE",
+	"POP", "POSITION", "PRINT", "PUT", "RAD", "READ", "REM", "RESTORE",
+	"RETURN", "RUN", "SAVE", "SETCOLOR", "SOUND", "STATUS", "STOP", "TRAP",
+	"XIO",
+	// Functions
+	"ABS", "ADR", "ASC", "ATN", "CHR$", "COS", "EXP", "FRE", "INT", "LEN",
+	"LOG", "PADDLE", "PEEK", "PTRIG", "RND", "SGN", "SIN", "SQR", "STICK",
+	"STRIG", "STR$", "USR", "VAL",
+	// Operators and clauses
+	"AND", "NOT", "OR", "STEP", "THEN", "TO",
+}
+
+// basicModeCommands are the REPL commands BASIC mode sends to the server.
+var basicModeCommands = []string{
+	"list", "del", "delete", "new", "run", "stop", "cont", "vars", "var",
+	"info", "fre", "step", "trace", "refs", "find", "export", "import",
+	"dir", "renum", "renumber", "save", "load",
+}
+
+// basicKeywordCandidates returns the keywords starting with prefix,
+// compared case-insensitively, in upper case and sorted. At the start of a
+// line the BASIC mode commands are offered too.
+func basicKeywordCandidates(prefix string, lineStart bool) []string {
+	upper := strings.ToUpper(prefix)
+	seen := make(map[string]bool)
+	var matches []string
+	add := func(words []string) {
+		for _, word := range words {
+			word = strings.ToUpper(word)
+			if strings.HasPrefix(word, upper) && !seen[word] {
+				seen[word] = true
+				matches = append(matches, word)
+			}
+		}
+	}
+	add(basicKeywords)
+	if lineStart {
+		add(basicModeCommands)
+	}
+	sort.Strings(matches)
+	return matches
+}
+
+// printBASICHelp handles ".help basic".
+func printBASICHelp() {
+	fmt.Println("BASIC mode commands: " + strings.Join(basicModeCommands, " "))
+	fmt.Println("Atari BASIC keywords: " + strings.Join(basicKeywords, " "))
+	fmt.Println("Other lines, such as 10 PRINT \"HI\", are typed into the emulator.")
+}
@@ -0,0 +1,35 @@
+// =============================================================================
+// basickeywords_test.go - Tests for BASIC Keywords (basickeywords.go)
+// =============================================================================
+
+package main
+
+import (
+	"slices"
+	"strings"
+	"testing"
+)
+
+// TestBASICKeywordCandidates verifies prefix matching and that a word
+// that is both a keyword and a mode command is offered once.
+func TestBASICKeywordCandidates(t *testing.T) {
+	if got, want := basicKeywordCandidates("li", true), []string{"LIST"}; !slices.Equal(got, want) {
+		t.Errorf("basicKeywordCandidates(li) = %q, want %q", got, want)
+	}
+	if got, want := basicKeywordCandidates("RE", false), []string{"READ", "REM", "RESTORE", "RETURN"}; !slices.Equal(got, want) {
+		t.Errorf("basicKeywordCandidates(RE) = %q, want %q", got, want)
+	}
+	if got := basicKeywordCandidates("XYZ", true); got != nil {
+		t.Errorf("basicKeywordCandidates(XYZ) = %q, want none", got)
+	}
+}
+
+// TestREPLHelpBASIC verifies that .help basic lists keywords and commands.
+func TestREPLHelpBASIC(t *testing.T) {
+	output := captureREPLWithOptions(t, ".help basic\n.quit\n", nil, replOptions{dryRun: true})
+	for _, want := range []string{"renum", "GOSUB", "CHR$"} {
+		if !strings.Contains(output, want) {
+			t.Errorf("expected %q in output, got:\n%s", want, output)
+		}
+	}
+}
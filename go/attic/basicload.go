@@ -0,0 +1,127 @@
+// =============================================================================
+// basicload.go - Loading a BASIC Program from a Host File (.basic load-host)
+// =============================================================================
+//
+// ".basic load-host" puts a BASIC program stored on the host into the
+// emulator, whichever form the file is in:
+//
+//	.basic load-host <path>
+//
+// A tokenized program (as written by SAVE) is sent whole with
+// "inject basic". A plain-text listing (LIST "D:..." output, or a file
+// typed on the host) is entered line by line with "basic <line>" after a
+// NEW, as if typed at the READY prompt. Files named .lst or .txt are
+// always listings; anything else is recognized by its content, since both
+// forms are commonly named .bas.
+//
+// =============================================================================
+
+package main
+
+import (
+	"bytes"
+	"encoding/base64"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/attic/atticprotocol"
+)
+
+// isTokenizedBasic reports whether data looks like a SAVEd Atari BASIC
+// program. Its header starts with LOMEM, which is always zero, followed by
+// six pointers; a listing starts with a line number digit instead.
+func isTokenizedBasic(data []byte) bool {
+	return len(data) >= 14 && data[0] == 0 && data[1] == 0
+}
+
+// splitBasicListing splits a listing into its non-empty lines. Lines may
+// end with ATASCII EOL or with a host newline.
+func splitBasicListing(data []byte) []string {
+	text := string(bytes.ReplaceAll(data, []byte{atticprotocol.ATASCIIEOL}, []byte{'\n'}))
+	var lines []string
+	for _, line := range strings.Split(text, "\n") {
+		if line = strings.TrimRight(line, "\r \t"); line != "" {
+			lines = append(lines, line)
+		}
+	}
+	return lines
+}
+
+// basicLoadCommands returns the commands that load the program in data,
+// read from path.
+func basicLoadCommands(path string, data []byte) ([]atticprotocol.Command, error) {
+	ext := strings.ToLower(filepath.Ext(path))
+	if ext != ".lst" && ext != ".txt" && isTokenizedBasic(data) {
+		cmd := atticprotocol.NewInjectBasicCommand(base64.StdEncoding.EncodeToString(data))
+		if len(atticprotocol.CommandPrefix)+len(cmd.Format())+1 > atticprotocol.MaxLineLength {
+			return nil, fmt.Errorf("%s: program is too large to inject (%d bytes)", path, len(data))
+		}
+		return []atticprotocol.Command{cmd}, nil
+	}
+
+	lines := splitBasicListing(data)
+	if len(lines) == 0 {
+		return nil, fmt.Errorf("%s: no BASIC lines", path)
+	}
+	cmds := []atticprotocol.Command{atticprotocol.NewBasicNewCommand()}
+	for i, line := range lines {
+		for _, r := range line {
+			if r < 0x20 || r > 0x7E {
+				return nil, fmt.Errorf("%s: line %d has characters that can't be typed as text", path, i+1)
+			}
+		}
+		cmds = append(cmds, atticprotocol.NewBasicLineCommand(line))
+	}
+	return cmds, nil
+}
+
+// runBasicLoadHostCommand handles ".basic load-host <path>".
+func runBasicLoadHostCommand(client *atticprotocol.Client, args string, opts replOptions) {
+	path := expandPath(strings.TrimSpace(args))
+	if path == "" {
+		printError("usage: .basic load-host <path>")
+		return
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		printError(err.Error())
+		return
+	}
+	cmds, err := basicLoadCommands(path, data)
+	if err != nil {
+		printError(err.Error())
+		return
+	}
+
+	lines := make([]string, len(cmds))
+	for i, cmd := range cmds {
+		lines[i] = cmd.Format()
+	}
+	if opts.dryRun {
+		for _, line := range lines {
+			fmt.Println("CMD:" + line)
+		}
+		return
+	}
+	if !client.IsConnected() {
+		printError("not connected (use .connect <socket>)")
+		return
+	}
+
+	responses, err := client.SendLines(lines, false)
+	if err != nil {
+		if n := len(responses); n > 0 && responses[n-1].IsError() {
+			printError(fmt.Sprintf("%s: %v", lines[n-1], err))
+		} else {
+			printError(err.Error())
+		}
+		return
+	}
+	if cmds[0].Type == atticprotocol.CmdInjectBasic {
+		fmt.Printf("Loaded tokenized program from %s (%d bytes)\n", path, len(data))
+	} else {
+		fmt.Printf("Loaded %d lines from %s\n", len(cmds)-1, path)
+	}
+}
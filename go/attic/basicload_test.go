@@ -0,0 +1,92 @@
+// =============================================================================
+// basicload_test.go - Tests for Loading BASIC from a Host File (basicload.go)
+// =============================================================================
+
+package main
+
+import (
+	"encoding/base64"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// TestREPLBasicLoadHostListing verifies that a text listing is entered line
+// by line after NEW, whatever its line endings.
+func TestREPLBasicLoadHostListing(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "hello.bas")
+	listing := "10 PRINT \"HELLO\"\r\n20 GOTO 10\n\n"
+	if err := os.WriteFile(path, []byte(listing), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	output := captureREPLWithOptions(t, ".basic load-host "+path+"\n.quit\n", nil, replOptions{dryRun: true})
+	want := "CMD:basic NEW\nCMD:basic 10 PRINT \"HELLO\"\nCMD:basic 20 GOTO 10\n"
+	if !strings.Contains(output, want) {
+		t.Errorf("expected %q in output, got:\n%s", want, output)
+	}
+	if strings.Contains(output, "inject basic") {
+		t.Errorf("a listing should not be injected, got:\n%s", output)
+	}
+}
+
+// TestREPLBasicLoadHostTokenized verifies that a SAVEd program is sent
+// whole with inject basic.
+func TestREPLBasicLoadHostTokenized(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "game.bas")
+	program := []byte{0x00, 0x00, 0x01, 0x00, 0x02, 0x00, 0x05, 0x00, 0x10, 0x00, 0x14, 0x00, 0x20, 0x00, 0x80, 0x9B}
+	if err := os.WriteFile(path, program, 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	output := captureREPLWithOptions(t, ".basic load-host "+path+"\n.quit\n", nil, replOptions{dryRun: true})
+	want := "CMD:inject basic " + base64.StdEncoding.EncodeToString(program) + "\n"
+	if !strings.Contains(output, want) {
+		t.Errorf("expected %q in output, got:\n%s", want, output)
+	}
+	if strings.Contains(output, "basic NEW") {
+		t.Errorf("a tokenized program should not be typed in, got:\n%s", output)
+	}
+}
+
+func TestBasicLoadCommands(t *testing.T) {
+	// A .lst file is a listing even if it starts like a tokenized program.
+	data := append([]byte{0x00, 0x00}, []byte("10 REM\x9b20 END\x9b")...)
+	data = append(data, make([]byte, 12)...)
+	if _, err := basicLoadCommands("odd.lst", data); err == nil {
+		t.Error("a .lst file with binary content should fail as a listing")
+	}
+
+	cmds, err := basicLoadCommands("eol.lst", []byte("10 REM\x9b20 END\x9b"))
+	if err != nil {
+		t.Fatalf("basicLoadCommands() error = %v", err)
+	}
+	if len(cmds) != 3 || cmds[2].Format() != "basic 20 END" {
+		t.Errorf("ATASCII EOL listing gave %v", cmds)
+	}
+
+	if _, err := basicLoadCommands("empty.bas", []byte("\n\n")); err == nil {
+		t.Error("an empty listing should fail")
+	}
+}
+
+// TestREPLBasicLoadHostSends verifies the live path sends every line.
+func TestREPLBasicLoadHostSends(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "hello.lst")
+	if err := os.WriteFile(path, []byte("10 PRINT 1\n20 END\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	output := captureREPL(t, ".basic load-host "+path+"\n.quit\n", func(cmd string) string {
+		switch cmd {
+		case "ping":
+			return "OK:pong\n"
+		case "basic NEW", "basic 10 PRINT 1", "basic 20 END":
+			return "OK:ok\n"
+		}
+		return "ERR:unexpected " + cmd + "\n"
+	})
+	if !strings.Contains(output, "Loaded 2 lines from "+path) {
+		t.Errorf("expected load summary, got:\n%s", output)
+	}
+}
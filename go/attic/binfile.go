@@ -0,0 +1,284 @@
+// =============================================================================
+// binfile.go - Memory Transfer Between Emulator and Host Files
+// =============================================================================
+//
+// This file implements ".savebin", which copies a region of emulator memory
+// into a raw binary file on the host, ".loadbin", which does the reverse,
+// and ".verify", which checks that memory holds a host file's bytes, e.g.
+// after loading code:
+//
+//	.savebin <addr> <len> <path>
+//	.loadbin <addr> <path>
+//	.verify <addr> <path>
+//
+// Memory is fetched with the protocol's "read" command and stored with
+// "write". A single protocol line is limited to MaxLineLength bytes, and
+// each byte takes three characters ("A9,"), so large regions are
+// transferred in chunks.
+//
+// =============================================================================
+
+package main
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/attic/atticprotocol"
+)
+
+// maxReadChunk is the largest number of bytes requested in one "read"
+// command. At three characters per byte plus the "OK:data " prefix, this
+// keeps every response comfortably under atticprotocol.MaxLineLength.
+const maxReadChunk = 1024
+
+// maxWriteChunk is the largest number of bytes sent in one "write" command:
+// as many as fit in MaxLineLength after "CMD:write $XXXX " and the newline,
+// at three characters per byte with no comma after the last.
+const maxWriteChunk = (atticprotocol.MaxLineLength - len("CMD:write $FFFF \n") + 1) / 3
+
+// writeChunkCommands returns the "write" commands that together store data
+// starting at addr, each at most maxWriteChunk bytes.
+func writeChunkCommands(addr uint16, data []byte) []atticprotocol.Command {
+	var cmds []atticprotocol.Command
+	for offset := 0; offset < len(data); offset += maxWriteChunk {
+		end := min(offset+maxWriteChunk, len(data))
+		cmds = append(cmds, atticprotocol.NewWriteCommand(uint16(int(addr)+offset), data[offset:end]))
+	}
+	return cmds
+}
+
+// readChunkCommands returns the "read" commands that together cover
+// length bytes starting at addr, each at most maxReadChunk bytes.
+func readChunkCommands(addr uint16, length int) []atticprotocol.Command {
+	var cmds []atticprotocol.Command
+	for offset := 0; offset < length; offset += maxReadChunk {
+		count := length - offset
+		if count > maxReadChunk {
+			count = maxReadChunk
+		}
+		cmds = append(cmds, atticprotocol.NewReadCommand(uint16(int(addr)+offset), uint16(count)))
+	}
+	return cmds
+}
+
+// readMemory reads length bytes starting at addr, splitting the transfer
+// into chunks of at most maxReadChunk bytes. The chunks are sent as one
+// sequence with SendLines, which stops at the first failed read.
+func readMemory(client *atticprotocol.Client, addr uint16, length int) ([]byte, error) {
+	cmds := readChunkCommands(addr, length)
+	lines := make([]string, len(cmds))
+	for i, cmd := range cmds {
+		lines[i] = cmd.Format()
+	}
+
+	responses, err := client.SendLines(lines, false)
+	if err != nil {
+		if n := len(responses); n > 0 && responses[n-1].IsError() {
+			return nil, fmt.Errorf("read $%04X: %w", cmds[n-1].Address, err)
+		}
+		return nil, err
+	}
+
+	data := make([]byte, 0, length)
+	for i, resp := range responses {
+		chunk, err := resp.AsBytes()
+		if err != nil {
+			return nil, fmt.Errorf("read $%04X: %w", cmds[i].Address, err)
+		}
+		if len(chunk) != cmds[i].Count {
+			return nil, fmt.Errorf("read $%04X: expected %d bytes, got %d", cmds[i].Address, cmds[i].Count, len(chunk))
+		}
+		data = append(data, chunk...)
+	}
+	return data, nil
+}
+
+// parseMemoryRange parses "<addr> <len>" arguments. The length is decimal
+// (or $hex) and the range must not extend past $FFFF.
+func parseMemoryRange(addrArg, lenArg string, symbols *symbolTable) (uint16, int, error) {
+	addr, ok := symbols.resolveAddress(addrArg)
+	if !ok {
+		return 0, 0, fmt.Errorf("invalid address %q", addrArg)
+	}
+
+	var length uint64
+	var err error
+	if strings.HasPrefix(lenArg, "$") {
+		length, err = strconv.ParseUint(lenArg[1:], 16, 32)
+	} else {
+		length, err = strconv.ParseUint(lenArg, 10, 32)
+	}
+	if err != nil || length == 0 {
+		return 0, 0, fmt.Errorf("invalid length %q", lenArg)
+	}
+	if int(addr)+int(length) > 0x10000 {
+		return 0, 0, fmt.Errorf("range $%04X+%d extends past $FFFF", addr, length)
+	}
+	return addr, int(length), nil
+}
+
+// runSaveBinCommand handles ".savebin <addr> <len> <path>".
+func runSaveBinCommand(client *atticprotocol.Client, args string, symbols *symbolTable, opts replOptions) {
+	fields := strings.Fields(args)
+	if len(fields) != 3 {
+		printError("usage: .savebin <addr> <len> <path>")
+		return
+	}
+	addr, length, err := parseMemoryRange(fields[0], fields[1], symbols)
+	if err != nil {
+		printError(err.Error())
+		return
+	}
+	path := expandPath(fields[2])
+
+	if opts.dryRun {
+		for _, cmd := range readChunkCommands(addr, length) {
+			fmt.Println("CMD:" + cmd.Format())
+		}
+		return
+	}
+
+	data, err := readMemory(client, addr, length)
+	if err != nil {
+		printError(err.Error())
+		return
+	}
+	if err := os.WriteFile(path, data, 0o644); err != nil {
+		printError(fmt.Sprintf("cannot write %s: %v", path, err))
+		return
+	}
+	fmt.Printf("Saved %d bytes from $%04X to %s\n", len(data), addr, path)
+}
+
+// firstDifference returns the offset of the first byte where a and b
+// differ, or -1 if they are equal.
+func firstDifference(a, b []byte) int {
+	for i := range min(len(a), len(b)) {
+		if a[i] != b[i] {
+			return i
+		}
+	}
+	if len(a) != len(b) {
+		return min(len(a), len(b))
+	}
+	return -1
+}
+
+// runVerifyCommand handles ".verify <addr> <path>".
+func runVerifyCommand(client *atticprotocol.Client, args string, symbols *symbolTable, opts replOptions) {
+	fields := strings.Fields(args)
+	if len(fields) != 2 {
+		printError("usage: .verify <addr> <path>")
+		return
+	}
+	addr, ok := symbols.resolveAddress(fields[0])
+	if !ok {
+		printError(fmt.Sprintf("invalid address %q", fields[0]))
+		return
+	}
+	path := expandPath(fields[1])
+	want, err := os.ReadFile(path)
+	if err != nil {
+		printError(err.Error())
+		return
+	}
+	if len(want) == 0 {
+		printError(fmt.Sprintf("%s is empty", path))
+		return
+	}
+	if int(addr)+len(want) > 0x10000 {
+		printError(fmt.Sprintf("%s (%d bytes) at $%04X extends past $FFFF", path, len(want), addr))
+		return
+	}
+
+	if opts.dryRun {
+		for _, cmd := range readChunkCommands(addr, len(want)) {
+			fmt.Println("CMD:" + cmd.Format())
+		}
+		return
+	}
+
+	got, err := readMemory(client, addr, len(want))
+	if err != nil {
+		printError(err.Error())
+		return
+	}
+	if i := firstDifference(want, got); i >= 0 {
+		fmt.Printf("Mismatch at $%04X (offset %d): file $%02X, memory $%02X\n", int(addr)+i, i, want[i], got[i])
+		return
+	}
+	fmt.Printf("Match: %d bytes at $%04X equal %s\n", len(want), addr, path)
+}
+
+// runLoadBinCommand handles ".loadbin <addr> <path>". Like a monitor
+// write, it pauses a running emulator first unless --auto-pause is off.
+func runLoadBinCommand(client *atticprotocol.Client, args string, symbols *symbolTable, state *runState, opts replOptions) {
+	fields := strings.Fields(args)
+	if len(fields) != 2 {
+		printError("usage: .loadbin <addr> <path>")
+		return
+	}
+	addr, ok := symbols.resolveAddress(fields[0])
+	if !ok {
+		printError(fmt.Sprintf("invalid address %q", fields[0]))
+		return
+	}
+	path := expandPath(fields[1])
+	data, err := os.ReadFile(path)
+	if err != nil {
+		printError(err.Error())
+		return
+	}
+	if len(data) == 0 {
+		printError(fmt.Sprintf("%s is empty", path))
+		return
+	}
+	if int(addr)+len(data) > 0x10000 {
+		printError(fmt.Sprintf("%s (%d bytes) at $%04X extends past $FFFF", path, len(data), addr))
+		return
+	}
+
+	cmds := writeChunkCommands(addr, data)
+	lines := make([]string, len(cmds))
+	for i, cmd := range cmds {
+		lines[i] = cmd.Format()
+	}
+	if opts.dryRun {
+		for _, line := range lines {
+			fmt.Println("CMD:" + line)
+		}
+		return
+	}
+	if !client.IsConnected() {
+		printError("not connected (use .connect <socket>)")
+		return
+	}
+	autoPaused := false
+	if opts.autoPause != autoPauseOff {
+		var err error
+		if autoPaused, err = pauseForWrite(client, state); err != nil {
+			printError(err.Error())
+			return
+		}
+		if autoPaused && opts.autoPause == autoPauseOn {
+			fmt.Println("Paused the emulator for the write")
+		}
+		if autoPaused && opts.autoPause == autoPauseResume {
+			defer resumeAfterWrite(client, state)
+		}
+	}
+
+	responses, err := client.SendLines(lines, false)
+	if err != nil {
+		if n := len(responses); n > 0 && responses[n-1].IsError() {
+			printError(fmt.Sprintf("write $%04X: %v", cmds[n-1].Address, err))
+		} else {
+			printError(err.Error())
+		}
+		return
+	}
+	fmt.Printf("Loaded %d bytes from %s to $%04X-$%04X\n", len(data), path, addr, int(addr)+len(data)-1)
+}
@@ -0,0 +1,258 @@
+// =============================================================================
+// binfile_test.go - Tests for Memory Transfer to Host Files (binfile.go)
+// =============================================================================
+
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/attic/atticprotocol"
+)
+
+// mockMemoryHandler returns a mock server handler that answers "read"
+// commands from a fake 64K memory where each byte is its address's low byte.
+func mockMemoryHandler(t *testing.T) func(cmd string) string {
+	return func(cmd string) string {
+		if cmd == "ping" {
+			return "OK:pong\n"
+		}
+		var addr, count int
+		if _, err := fmt.Sscanf(cmd, "read $%X %d", &addr, &count); err != nil {
+			t.Errorf("unexpected command %q", cmd)
+			return "ERR:unexpected\n"
+		}
+		hex := make([]string, count)
+		for i := range hex {
+			hex[i] = fmt.Sprintf("%02X", byte(addr+i))
+		}
+		return "OK:data " + strings.Join(hex, ",") + "\n"
+	}
+}
+
+// TestParseMemoryRange verifies address/length validation.
+func TestParseMemoryRange(t *testing.T) {
+	symbols := newSymbolTable()
+	symbols.define("BUF", 0x0600)
+
+	tests := []struct {
+		addr, length string
+		wantAddr     uint16
+		wantLen      int
+		wantErr      bool
+	}{
+		{"$0600", "16", 0x0600, 16, false},
+		{"BUF", "$100", 0x0600, 256, false},
+		{"$FFF0", "16", 0xFFF0, 16, false},
+		{"$FFF0", "17", 0, 0, true},
+		{"$0600", "0", 0, 0, true},
+		{"nowhere", "4", 0, 0, true},
+	}
+
+	for _, tc := range tests {
+		addr, length, err := parseMemoryRange(tc.addr, tc.length, symbols)
+		if (err != nil) != tc.wantErr {
+			t.Errorf("parseMemoryRange(%q, %q) error = %v, wantErr %v", tc.addr, tc.length, err, tc.wantErr)
+			continue
+		}
+		if !tc.wantErr && (addr != tc.wantAddr || length != tc.wantLen) {
+			t.Errorf("parseMemoryRange(%q, %q) = $%04X, %d; want $%04X, %d",
+				tc.addr, tc.length, addr, length, tc.wantAddr, tc.wantLen)
+		}
+	}
+}
+
+// TestReadMemoryChunks verifies that large reads are split and joined.
+func TestReadMemoryChunks(t *testing.T) {
+	ms := startMockServer(t, mockMemoryHandler(t))
+	client := atticprotocol.NewClient()
+	if err := client.Connect(ms.socketPath); err != nil {
+		t.Fatalf("failed to connect to mock server: %v", err)
+	}
+	t.Cleanup(func() { client.Disconnect() })
+
+	length := maxReadChunk*2 + 10
+	data, err := readMemory(client, 0x2000, length)
+	if err != nil {
+		t.Fatalf("readMemory failed: %v", err)
+	}
+	if len(data) != length {
+		t.Fatalf("readMemory returned %d bytes, want %d", len(data), length)
+	}
+	for i, b := range data {
+		if b != byte(0x2000+i) {
+			t.Fatalf("byte %d = $%02X, want $%02X", i, b, byte(0x2000+i))
+		}
+	}
+}
+
+// TestREPLSaveBin verifies that .savebin writes the server's bytes to disk.
+func TestREPLSaveBin(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "dump.bin")
+	input := fmt.Sprintf(".savebin $0600 4 %s\n.quit\n", path)
+	output := captureREPL(t, input, mockMemoryHandler(t))
+
+	if !strings.Contains(output, "Saved 4 bytes") {
+		t.Errorf("expected save confirmation, got:\n%s", output)
+	}
+	got, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("failed to read saved file: %v", err)
+	}
+	if want := []byte{0x00, 0x01, 0x02, 0x03}; !bytes.Equal(got, want) {
+		t.Errorf("saved bytes = %X, want %X", got, want)
+	}
+}
+
+// TestREPLVerify verifies matching and mismatching files, including one
+// large enough to need several reads.
+func TestREPLVerify(t *testing.T) {
+	dir := t.TempDir()
+	contents := make([]byte, 1500)
+	for i := range contents {
+		contents[i] = byte(0x0600 + i)
+	}
+	matching := filepath.Join(dir, "match.bin")
+	if err := os.WriteFile(matching, contents, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	contents[1100] ^= 0xFF
+	mismatching := filepath.Join(dir, "mismatch.bin")
+	if err := os.WriteFile(mismatching, contents, 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	output := captureREPL(t, ".verify $0600 "+matching+"\n.quit\n", mockMemoryHandler(t))
+	if want := "Match: 1500 bytes at $0600 equal " + matching; !strings.Contains(output, want) {
+		t.Errorf("expected %q, got:\n%s", want, output)
+	}
+
+	output = captureREPL(t, ".verify $0600 "+mismatching+"\n.quit\n", mockMemoryHandler(t))
+	if want := "Mismatch at $0A4C (offset 1100): file $B3, memory $4C"; !strings.Contains(output, want) {
+		t.Errorf("expected %q, got:\n%s", want, output)
+	}
+}
+
+// TestREPLVerifyUsage verifies that bad arguments read nothing.
+func TestREPLVerifyUsage(t *testing.T) {
+	empty := filepath.Join(t.TempDir(), "empty.bin")
+	if err := os.WriteFile(empty, nil, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	for _, input := range []string{".verify\n", ".verify $0600\n", ".verify $0600 " + empty + "\n", ".verify $FFFF " + empty + "x\n"} {
+		output := captureREPLWithOptions(t, input+".quit\n", nil, replOptions{dryRun: true})
+		if strings.Contains(output, "CMD:read") {
+			t.Errorf("%q: should not read memory, got:\n%s", strings.TrimSpace(input), output)
+		}
+	}
+}
+
+func TestFirstDifference(t *testing.T) {
+	tests := []struct {
+		a, b []byte
+		want int
+	}{
+		{[]byte{1, 2, 3}, []byte{1, 2, 3}, -1},
+		{[]byte{1, 2, 3}, []byte{1, 9, 3}, 1},
+		{[]byte{1, 2, 3}, []byte{1, 2}, 2},
+	}
+	for _, tt := range tests {
+		if got := firstDifference(tt.a, tt.b); got != tt.want {
+			t.Errorf("firstDifference(%X, %X) = %d, want %d", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
+
+// TestWriteChunkCommands verifies chunk boundaries and that every chunk
+// fits in a protocol line.
+func TestWriteChunkCommands(t *testing.T) {
+	data := make([]byte, maxWriteChunk*2+5)
+	for i := range data {
+		data[i] = byte(i)
+	}
+	cmds := writeChunkCommands(0x2000, data)
+	if len(cmds) != 3 {
+		t.Fatalf("got %d commands, want 3", len(cmds))
+	}
+	wantAddrs := []uint16{0x2000, 0x2000 + uint16(maxWriteChunk), 0x2000 + 2*uint16(maxWriteChunk)}
+	wantLens := []int{maxWriteChunk, maxWriteChunk, 5}
+	var joined []byte
+	for i, cmd := range cmds {
+		if cmd.Address != wantAddrs[i] || len(cmd.Data) != wantLens[i] {
+			t.Errorf("chunk %d = $%04X, %d bytes; want $%04X, %d bytes",
+				i, cmd.Address, len(cmd.Data), wantAddrs[i], wantLens[i])
+		}
+		if n := len(atticprotocol.CommandPrefix) + len(cmd.Format()) + 1; n > atticprotocol.MaxLineLength {
+			t.Errorf("chunk %d line is %d bytes, limit %d", i, n, atticprotocol.MaxLineLength)
+		}
+		joined = append(joined, cmd.Data...)
+	}
+	if !bytes.Equal(joined, data) {
+		t.Error("chunks do not add up to the original data")
+	}
+}
+
+// TestREPLLoadBin verifies the write commands .loadbin sends for a small
+// file.
+func TestREPLLoadBin(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "code.bin")
+	if err := os.WriteFile(path, []byte{0xA9, 0x00, 0x60}, 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	var writes []string
+	output := captureREPL(t, ".loadbin $0600 "+path+"\n.quit\n", func(cmd string) string {
+		if cmd == "ping" {
+			return "OK:pong\n"
+		}
+		writes = append(writes, cmd)
+		return "OK:written\n"
+	})
+
+	if want := []string{"write $0600 A9,00,60"}; strings.Join(writes, "\n") != strings.Join(want, "\n") {
+		t.Errorf("commands = %q, want %q", writes, want)
+	}
+	if want := "Loaded 3 bytes from " + path + " to $0600-$0602"; !strings.Contains(output, want) {
+		t.Errorf("expected %q, got:\n%s", want, output)
+	}
+}
+
+// TestREPLLoadBinDryRun verifies that a large file is split at chunk
+// boundaries.
+func TestREPLLoadBinDryRun(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "big.bin")
+	if err := os.WriteFile(path, make([]byte, maxWriteChunk+1), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	output := captureREPLWithOptions(t, ".loadbin $3000 "+path+"\n.quit\n", nil, replOptions{dryRun: true})
+	if got := strings.Count(output, "CMD:write "); got != 2 {
+		t.Fatalf("expected 2 write commands, got %d:\n%s", got, output)
+	}
+	if want := fmt.Sprintf("CMD:write $%04X 00\n", 0x3000+maxWriteChunk); !strings.Contains(output, want) {
+		t.Errorf("expected %q in output", want)
+	}
+}
+
+// TestREPLLoadBinUsage verifies that bad arguments write nothing.
+func TestREPLLoadBinUsage(t *testing.T) {
+	dir := t.TempDir()
+	empty := filepath.Join(dir, "empty.bin")
+	if err := os.WriteFile(empty, nil, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	big := filepath.Join(dir, "big.bin")
+	if err := os.WriteFile(big, make([]byte, 16), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	for _, input := range []string{".loadbin\n", ".loadbin $0600\n", ".loadbin $0600 " + empty + "\n", ".loadbin $FFF8 " + big + "\n", ".loadbin nowhere " + big + "\n"} {
+		output := captureREPLWithOptions(t, input+".quit\n", nil, replOptions{dryRun: true})
+		if strings.Contains(output, "CMD:write") {
+			t.Errorf("%q: should not write memory, got:\n%s", strings.TrimSpace(input), output)
+		}
+	}
+}
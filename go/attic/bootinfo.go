@@ -0,0 +1,57 @@
+// =============================================================================
+// bootinfo.go - Showing the Boot Media (.bootinfo)
+// =============================================================================
+//
+// ".bootinfo" confirms what the emulator is running:
+//
+//	.bootinfo
+//	Disk image in D1: /path/to/game.atr
+//
+// =============================================================================
+
+package main
+
+import (
+	"fmt"
+
+	"github.com/attic/atticprotocol"
+)
+
+// formatBootInfo describes the boot media in one line.
+func formatBootInfo(info atticprotocol.BootInfo) string {
+	switch info.Source {
+	case atticprotocol.BootDisk:
+		return fmt.Sprintf("Disk image in D%d: %s", info.Drive, info.Path)
+	case atticprotocol.BootExecutable:
+		return "Executable: " + info.Path
+	case atticprotocol.BootCartridge:
+		return "Cartridge: " + info.Path
+	default:
+		return "No boot media (built-in BASIC)"
+	}
+}
+
+// runBootInfoCommand handles ".bootinfo".
+func runBootInfoCommand(client *atticprotocol.Client, opts replOptions) {
+	cmd := atticprotocol.NewBootInfoCommand()
+	if opts.dryRun {
+		fmt.Println("CMD:" + cmd.Format())
+		return
+	}
+	if !client.IsConnected() {
+		printError("not connected (use .connect <socket>)")
+		return
+	}
+
+	resp, err := client.Send(cmd)
+	if err != nil {
+		printError(err.Error())
+		return
+	}
+	info, err := resp.AsBootInfo()
+	if err != nil {
+		printError(err.Error())
+		return
+	}
+	fmt.Println(formatBootInfo(info))
+}
@@ -0,0 +1,52 @@
+// =============================================================================
+// bootinfo_test.go - Tests for Showing the Boot Media (bootinfo.go)
+// =============================================================================
+
+package main
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/attic/atticprotocol"
+)
+
+// TestREPLBootInfo verifies that .bootinfo describes the server's answer.
+func TestREPLBootInfo(t *testing.T) {
+	output := captureREPL(t, ".bootinfo\n.quit\n", func(cmd string) string {
+		switch cmd {
+		case "ping":
+			return "OK:pong\n"
+		case "bootinfo":
+			return "OK:bootinfo disk 1 /disks/dos 2.5.atr\n"
+		}
+		return "ERR:unexpected " + cmd + "\n"
+	})
+	if want := "Disk image in D1: /disks/dos 2.5.atr\n"; !strings.Contains(output, want) {
+		t.Errorf("expected %q in output, got:\n%s", want, output)
+	}
+}
+
+func TestFormatBootInfo(t *testing.T) {
+	tests := []struct {
+		info atticprotocol.BootInfo
+		want string
+	}{
+		{atticprotocol.BootInfo{Source: atticprotocol.BootExecutable, Path: "/g.xex"}, "Executable: /g.xex"},
+		{atticprotocol.BootInfo{Source: atticprotocol.BootCartridge, Path: "/sr.car"}, "Cartridge: /sr.car"},
+		{atticprotocol.BootInfo{Source: atticprotocol.BootNone}, "No boot media (built-in BASIC)"},
+	}
+	for _, tt := range tests {
+		if got := formatBootInfo(tt.info); got != tt.want {
+			t.Errorf("formatBootInfo(%+v) = %q, want %q", tt.info, got, tt.want)
+		}
+	}
+}
+
+// TestREPLBootInfoDryRun verifies the protocol command in dry-run mode.
+func TestREPLBootInfoDryRun(t *testing.T) {
+	output := captureREPLWithOptions(t, ".bootinfo\n.quit\n", nil, replOptions{dryRun: true})
+	if !strings.Contains(output, "CMD:bootinfo") {
+		t.Errorf("expected CMD:bootinfo, got:\n%s", output)
+	}
+}
@@ -0,0 +1,176 @@
+// =============================================================================
+// breakpoints.go - Saving and Restoring Breakpoints (.bp)
+// =============================================================================
+//
+// ".bp save" writes the server's breakpoint list to a host file and
+// ".bp load" replays it with "breakpoint set" commands, so a debugging
+// session survives an emulator restart:
+//
+//	.bp save <path>
+//	.bp load <path>
+//
+// The file holds one breakpoint per line in the same form as a
+// "breakpoint list detailed" response, with ";" starting a comment:
+//
+//	; Attic breakpoints
+//	$600A
+//	$602F hits 5 once
+//	$E477 disabled
+//	$0610 if A==$00
+//
+// =============================================================================
+
+package main
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/attic/atticprotocol"
+)
+
+// formatBreakpointLine renders one breakpoint in the file format.
+func formatBreakpointLine(bp atticprotocol.Breakpoint) string {
+	line := fmt.Sprintf("$%04X", bp.Address)
+	if bp.HitCount > 0 {
+		line += fmt.Sprintf(" hits %d", bp.HitCount)
+	}
+	if bp.Once {
+		line += " once"
+	}
+	if !bp.Enabled {
+		line += " disabled"
+	}
+	if bp.Condition != "" {
+		line += " if " + bp.Condition
+	}
+	return line
+}
+
+// formatBreakpointFile builds the file contents for a breakpoint list.
+func formatBreakpointFile(breakpoints []atticprotocol.Breakpoint) string {
+	var sb strings.Builder
+	sb.WriteString("; Attic breakpoints\n")
+	for _, bp := range breakpoints {
+		sb.WriteString(formatBreakpointLine(bp))
+		sb.WriteString("\n")
+	}
+	return sb.String()
+}
+
+// parseBreakpointFile parses the contents of a breakpoint file. Each line
+// uses the "breakpoint list detailed" syntax, so the protocol package's
+// parser does the work once comments and blank lines are removed.
+func parseBreakpointFile(contents string) ([]atticprotocol.Breakpoint, error) {
+	lines := []string{"breakpoints"}
+	for _, line := range strings.Split(contents, "\n") {
+		if i := strings.Index(line, ";"); i >= 0 {
+			line = line[:i]
+		}
+		if line = strings.TrimSpace(line); line != "" {
+			lines = append(lines, line)
+		}
+	}
+	return atticprotocol.NewMultiLineResponse(lines).AsBreakpointsDetailed()
+}
+
+// breakpointRestoreCommands returns the commands that recreate bp: a set
+// command, followed by a disable command if bp was disabled.
+func breakpointRestoreCommands(bp atticprotocol.Breakpoint) []atticprotocol.Command {
+	set := atticprotocol.NewBreakpointSetHitsCommand(bp.Address, bp.HitCount)
+	set.Once = bp.Once
+	set.Condition = bp.Condition
+	cmds := []atticprotocol.Command{set}
+	if !bp.Enabled {
+		cmds = append(cmds, atticprotocol.NewBreakpointEnableCommand(bp.Address, false))
+	}
+	return cmds
+}
+
+// runBreakpointFileCommand handles ".bp save <path>" and ".bp load <path>".
+func runBreakpointFileCommand(client *atticprotocol.Client, args string, opts replOptions) {
+	fields := strings.Fields(args)
+	if len(fields) != 2 || (strings.ToLower(fields[0]) != "save" && strings.ToLower(fields[0]) != "load") {
+		printError("usage: .bp save|load <path>")
+		return
+	}
+	if !opts.dryRun && !client.IsConnected() {
+		printError("not connected (use .connect <socket>)")
+		return
+	}
+	path := expandPath(fields[1])
+
+	if strings.ToLower(fields[0]) == "save" {
+		saveBreakpoints(client, path, opts)
+	} else {
+		loadBreakpoints(client, path, opts)
+	}
+}
+
+// saveBreakpoints writes the server's breakpoints to path.
+func saveBreakpoints(client *atticprotocol.Client, path string, opts replOptions) {
+	cmd := atticprotocol.NewBreakpointListDetailedCommand()
+	if opts.dryRun {
+		fmt.Println("CMD:" + cmd.Format())
+		return
+	}
+
+	resp, err := client.Send(cmd)
+	if err != nil {
+		printError(err.Error())
+		return
+	}
+	breakpoints, err := resp.AsBreakpointsDetailed()
+	if err != nil {
+		printError(err.Error())
+		return
+	}
+
+	if err := os.WriteFile(path, []byte(formatBreakpointFile(breakpoints)), 0o644); err != nil {
+		printError(fmt.Sprintf("cannot write %s: %v", path, err))
+		return
+	}
+	fmt.Printf("Saved %d breakpoints to %s\n", len(breakpoints), path)
+}
+
+// loadBreakpoints replays the breakpoints in path. A breakpoint the server
+// rejects is reported and the rest are still loaded.
+func loadBreakpoints(client *atticprotocol.Client, path string, opts replOptions) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		printError(fmt.Sprintf("cannot read %s: %v", path, err))
+		return
+	}
+	breakpoints, err := parseBreakpointFile(string(data))
+	if err != nil {
+		printError(fmt.Sprintf("%s: %v", path, err))
+		return
+	}
+
+	loaded := 0
+	for _, bp := range breakpoints {
+		ok := true
+		for _, cmd := range breakpointRestoreCommands(bp) {
+			if opts.dryRun {
+				fmt.Println("CMD:" + cmd.Format())
+				continue
+			}
+			resp, err := client.Send(cmd)
+			if err == nil {
+				err = resp.Err()
+			}
+			if err != nil {
+				printError(fmt.Sprintf("$%04X: %v", bp.Address, err))
+				ok = false
+				break
+			}
+		}
+		if ok {
+			loaded++
+		}
+	}
+	if !opts.dryRun {
+		fmt.Printf("Loaded %d breakpoints from %s\n", loaded, path)
+	}
+}
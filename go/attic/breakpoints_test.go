@@ -0,0 +1,95 @@
+// =============================================================================
+// breakpoints_test.go - Tests for Saving and Restoring Breakpoints (breakpoints.go)
+// =============================================================================
+
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// TestREPLBreakpointSaveLoad saves a breakpoint set from one server and
+// loads it into another, checking the commands that recreate it.
+func TestREPLBreakpointSaveLoad(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "session.bp")
+
+	output := captureREPL(t, ".bp save "+path+"\n.quit\n", func(cmd string) string {
+		switch cmd {
+		case "ping":
+			return "OK:pong\n"
+		case "breakpoint list detailed":
+			return "OK:breakpoints\x1E$600A\x1E$602F hits 5 once\x1E$E477 disabled\x1E$0610 hits 2 if A==$00\n"
+		}
+		return "ERR:unexpected " + cmd + "\n"
+	})
+	if !strings.Contains(output, "Saved 4 breakpoints to "+path) {
+		t.Errorf("expected confirmation, got:\n%s", output)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("failed to read breakpoint file: %v", err)
+	}
+	want := "; Attic breakpoints\n$600A\n$602F hits 5 once\n$E477 disabled\n$0610 hits 2 if A==$00\n"
+	if string(data) != want {
+		t.Errorf("file contents = %q, want %q", data, want)
+	}
+
+	var mu sync.Mutex
+	var received []string
+	output = captureREPL(t, ".bp load "+path+"\n.quit\n", func(cmd string) string {
+		if cmd == "ping" {
+			return "OK:pong\n"
+		}
+		mu.Lock()
+		received = append(received, cmd)
+		mu.Unlock()
+		return "OK:" + cmd + "\n"
+	})
+	if !strings.Contains(output, "Loaded 4 breakpoints from "+path) {
+		t.Errorf("expected confirmation, got:\n%s", output)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	wantCmds := []string{
+		"breakpoint set $600A",
+		"breakpoint set $602F hits 5 once",
+		"breakpoint set $E477",
+		"breakpoint disable $E477",
+		"breakpoint set $0610 hits 2 if A==$00",
+	}
+	if got := strings.Join(received, "\n"); got != strings.Join(wantCmds, "\n") {
+		t.Errorf("server received:\n%s\nwant:\n%s", got, strings.Join(wantCmds, "\n"))
+	}
+}
+
+// TestParseBreakpointFile verifies that comments and blank lines are
+// skipped and that malformed lines are rejected.
+func TestParseBreakpointFile(t *testing.T) {
+	bps, err := parseBreakpointFile("; saved\n\n$0600 once ; main loop\n  $0700 hits 2\n")
+	if err != nil {
+		t.Fatalf("parseBreakpointFile() error = %v", err)
+	}
+	if len(bps) != 2 || bps[0].Address != 0x0600 || !bps[0].Once || bps[1].HitCount != 2 {
+		t.Errorf("parseBreakpointFile() = %+v", bps)
+	}
+
+	if _, err := parseBreakpointFile("$0600 sometimes\n"); err == nil {
+		t.Error("expected an error for an unknown attribute")
+	}
+}
+
+// TestREPLBreakpointFileUsage verifies that bad arguments send nothing.
+func TestREPLBreakpointFileUsage(t *testing.T) {
+	for _, input := range []string{".bp\n", ".bp save\n", ".bp export /tmp/x.bp\n"} {
+		output := captureREPLWithOptions(t, input+".quit\n", nil, replOptions{dryRun: true})
+		if strings.Contains(output, "CMD:breakpoint") {
+			t.Errorf("%q: should not send a command, got:\n%s", strings.TrimSpace(input), output)
+		}
+	}
+}
@@ -0,0 +1,122 @@
+// =============================================================================
+// color.go - Colorized REPL Output
+// =============================================================================
+//
+// This file centralizes ANSI coloring for the REPL. When color is enabled:
+//
+//   - Error messages are red
+//   - Event notifications (breakpoints, stops) are yellow
+//   - Prompts are dimmed so responses stand out
+//
+// The --color flag selects the policy: "auto" (the default) colors only
+// when stdout is a terminal and --plain was not given, "always" forces
+// color on, and "never" turns it off. Piped or comint output therefore
+// stays free of escape codes unless explicitly requested.
+//
+// =============================================================================
+
+package main
+
+import (
+	"os"
+	"strings"
+
+	"golang.org/x/term"
+)
+
+// ANSI SGR escape sequences used for REPL output.
+const (
+	ansiReset  = "\033[0m"
+	ansiRed    = "\033[31m"
+	ansiYellow = "\033[33m"
+	ansiDim    = "\033[2m"
+)
+
+// colorMode is the --color policy.
+type colorMode int
+
+const (
+	// colorAuto colors output only when stdout is a terminal.
+	colorAuto colorMode = iota
+	// colorAlways always colors output.
+	colorAlways
+	// colorNever never colors output.
+	colorNever
+)
+
+// parseColorMode converts a --color argument to a colorMode.
+func parseColorMode(s string) (colorMode, bool) {
+	switch strings.ToLower(s) {
+	case "auto":
+		return colorAuto, true
+	case "always":
+		return colorAlways, true
+	case "never":
+		return colorNever, true
+	default:
+		return colorAuto, false
+	}
+}
+
+// GO CONCEPT: Useful Zero Values
+// -------------------------------
+// Go encourages types whose zero value is ready to use. A zero palette
+// (palette{}) has enabled == false, so code can print through it before
+// main() has configured anything — and tests, which never configure it,
+// get plain uncolored output for free.
+//
+// Compare with Swift: Swift requires explicit initialization; you'd give
+// the property a default value: `var enabled = false`.
+//
+// Compare with Python: Python has no zero values — attributes must be set
+// in __init__ or as class attributes: `enabled: bool = False`.
+
+// palette applies ANSI colors to REPL output when enabled.
+type palette struct {
+	enabled bool
+}
+
+// outputColors is the palette used by the REPL print path. It is set once
+// in main() from the command-line options.
+var outputColors palette
+
+// newPalette decides whether to color output given the --color policy,
+// whether --plain was requested, and whether stdout is a terminal.
+func newPalette(mode colorMode, plain bool, stdoutIsTTY bool) palette {
+	switch mode {
+	case colorAlways:
+		return palette{enabled: true}
+	case colorNever:
+		return palette{}
+	default:
+		return palette{enabled: stdoutIsTTY && !plain}
+	}
+}
+
+// stdoutIsTerminal reports whether stdout is connected to a terminal.
+func stdoutIsTerminal() bool {
+	return term.IsTerminal(int(os.Stdout.Fd()))
+}
+
+// wrap surrounds text with an ANSI color code when enabled.
+func (p palette) wrap(code, text string) string {
+	if !p.enabled || text == "" {
+		return text
+	}
+	return code + text + ansiReset
+}
+
+// errorText colors an error message.
+func (p palette) errorText(text string) string {
+	return p.wrap(ansiRed, text)
+}
+
+// eventText colors an async event notification.
+func (p palette) eventText(text string) string {
+	return p.wrap(ansiYellow, text)
+}
+
+// promptText colors a REPL prompt.
+func (p palette) promptText(text string) string {
+	return p.wrap(ansiDim, text)
+}
@@ -0,0 +1,90 @@
+// =============================================================================
+// color_test.go - Tests for Colorized Output (color.go)
+// =============================================================================
+
+package main
+
+import (
+	"os"
+	"strings"
+	"testing"
+)
+
+// TestParseColorMode verifies the accepted --color values.
+func TestParseColorMode(t *testing.T) {
+	tests := []struct {
+		value string
+		mode  colorMode
+		ok    bool
+	}{
+		{"auto", colorAuto, true},
+		{"always", colorAlways, true},
+		{"NEVER", colorNever, true},
+		{"sometimes", colorAuto, false},
+	}
+
+	for _, tc := range tests {
+		mode, ok := parseColorMode(tc.value)
+		if mode != tc.mode || ok != tc.ok {
+			t.Errorf("parseColorMode(%q) = %v, %v; want %v, %v", tc.value, mode, ok, tc.mode, tc.ok)
+		}
+	}
+}
+
+// TestNewPalette verifies the color decision for each policy.
+func TestNewPalette(t *testing.T) {
+	tests := []struct {
+		name    string
+		mode    colorMode
+		plain   bool
+		isTTY   bool
+		enabled bool
+	}{
+		{"auto tty", colorAuto, false, true, true},
+		{"auto piped", colorAuto, false, false, false},
+		{"auto plain", colorAuto, true, true, false},
+		{"always piped", colorAlways, false, false, true},
+		{"never tty", colorNever, false, true, false},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			p := newPalette(tc.mode, tc.plain, tc.isTTY)
+			if p.enabled != tc.enabled {
+				t.Errorf("enabled = %v, want %v", p.enabled, tc.enabled)
+			}
+		})
+	}
+}
+
+// TestPaletteNoColorWhenPiped verifies that the default policy produces no
+// escape codes when stdout is a pipe.
+func TestPaletteNoColorWhenPiped(t *testing.T) {
+	oldStdout := os.Stdout
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatalf("failed to create pipe: %v", err)
+	}
+	defer r.Close()
+	defer w.Close()
+	os.Stdout = w
+	defer func() { os.Stdout = oldStdout }()
+
+	p := newPalette(colorAuto, false, stdoutIsTerminal())
+	for _, text := range []string{p.errorText("Error: x"), p.eventText("*** Stopped"), p.promptText("[basic] > ")} {
+		if strings.Contains(text, "\033[") {
+			t.Errorf("unexpected ANSI escape in piped output: %q", text)
+		}
+	}
+}
+
+// TestPaletteWrap verifies the color codes used when enabled.
+func TestPaletteWrap(t *testing.T) {
+	p := palette{enabled: true}
+	if got := p.errorText("boom"); got != ansiRed+"boom"+ansiReset {
+		t.Errorf("errorText = %q", got)
+	}
+	if got := p.eventText("hit"); got != ansiYellow+"hit"+ansiReset {
+		t.Errorf("eventText = %q", got)
+	}
+}
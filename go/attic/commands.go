@@ -0,0 +1,58 @@
+// =============================================================================
+// commands.go - Listing Protocol Commands (.commands)
+// =============================================================================
+//
+// ".commands" prints every protocol command keyword the server accepts,
+// with its aliases and a one-line summary:
+//
+//	disassemble  disasm, d   Disassemble memory
+//	stepover     so          Step over a subroutine call
+//
+// The list comes from atticprotocol.AllCommands(), the same table the
+// protocol parser uses, so it can't drift out of date.
+//
+// =============================================================================
+
+package main
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/attic/atticprotocol"
+)
+
+// GO CONCEPT: Aligned Columns with text/tabwriter
+// ------------------------------------------------
+// tabwriter.Writer buffers tab-separated cells and pads each column to
+// the width of its widest cell when Flush() is called. This gives neat
+// tables without computing widths by hand.
+//
+// Compare with Swift: there's no built-in equivalent; Swift code usually
+// pads with String(repeating:count:) or `padding(toLength:withPad:...)`.
+//
+// Compare with Python: `f"{name:<12}"` format specs, or the `tabulate`
+// package for full tables.
+
+// writeCommandList writes the protocol command table to w.
+func writeCommandList(w io.Writer) {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	for _, info := range atticprotocol.AllCommands() {
+		aliases := strings.Join(info.Aliases, ", ")
+		if aliases == "" {
+			aliases = "-"
+		}
+		fmt.Fprintf(tw, "  %s\t%s\t%s\n", info.Name, aliases, info.Summary)
+	}
+	tw.Flush()
+}
+
+// printCommandList handles ".commands".
+func printCommandList() {
+	var sb strings.Builder
+	sb.WriteString("Protocol commands (send in monitor mode):\n")
+	writeCommandList(&sb)
+	printPaged(strings.TrimRight(sb.String(), "\n"))
+}
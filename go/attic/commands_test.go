@@ -0,0 +1,46 @@
+// =============================================================================
+// commands_test.go - Tests for the Command List (commands.go)
+// =============================================================================
+
+package main
+
+import (
+	"regexp"
+	"strings"
+	"testing"
+)
+
+// TestWriteCommandList verifies that core commands appear with their
+// aliases.
+func TestWriteCommandList(t *testing.T) {
+	var sb strings.Builder
+	writeCommandList(&sb)
+	out := sb.String()
+
+	for _, pattern := range []string{
+		`(?m)^  ping\s+-\s+\S`,
+		`(?m)^  read\s+-\s+\S`,
+		`(?m)^  breakpoint\s+-\s+\S`,
+		`(?m)^  disassemble\s+disasm, d\s+\S`,
+		`(?m)^  stepout\s+sr\s+\S`,
+		`(?m)^  basic\s+-\s+\S`,
+		`(?m)^  dos\s+-\s+\S`,
+	} {
+		if !regexp.MustCompile(pattern).MatchString(out) {
+			t.Errorf("command list does not match %q:\n%s", pattern, out)
+		}
+	}
+}
+
+// TestREPLCommandsDotCommand verifies .commands is handled locally.
+func TestREPLCommandsDotCommand(t *testing.T) {
+	input := ".commands\n.quit\n"
+	output := captureREPLWithOptions(t, input, nil, replOptions{dryRun: true})
+
+	if !strings.Contains(output, "Protocol commands") || !strings.Contains(output, "stepover") {
+		t.Errorf("expected command list, got:\n%s", output)
+	}
+	if strings.Contains(output, "CMD:") {
+		t.Errorf(".commands should not send anything, got:\n%s", output)
+	}
+}
@@ -0,0 +1,215 @@
+// =============================================================================
+// completion.go - Tab Completion for BASIC Keywords and DOS Filenames
+// =============================================================================
+//
+// In BASIC mode, pressing Tab completes Atari BASIC keywords ("PR" becomes
+// "PRINT") and, at the start of a line, the BASIC mode commands ("ren"
+// becomes "renum"). Keywords are not completed inside a string literal.
+//
+// In DOS mode, pressing Tab after a file command ("type", "info", "copy",
+// ...) completes the filename from the current drive's directory. The
+// listing is fetched with "dos dir" the first time it's needed and cached,
+// so repeated Tabs never wait on the server. Commands that change the
+// directory ("cd", "delete", "rename", "import", "format", ...) invalidate
+// the cache, and the next Tab fetches a fresh listing.
+//
+// =============================================================================
+
+package main
+
+import (
+	"sort"
+	"strings"
+	"sync"
+	"unicode"
+
+	"github.com/attic/atticprotocol"
+)
+
+// dosFileCommands are the DOS mode commands whose arguments are filenames
+// on the current drive.
+var dosFileCommands = map[string]bool{
+	"type": true, "dump": true, "info": true,
+	"delete": true, "del": true, "lock": true, "unlock": true,
+	"copy": true, "cp": true, "diff": true, "rename": true, "ren": true, "export": true,
+}
+
+// dosDirChangingCommands are the DOS mode commands after which the cached
+// directory listing may be stale.
+var dosDirChangingCommands = map[string]bool{
+	"cd": true, "delete": true, "del": true, "rename": true, "ren": true,
+	"import": true, "format": true, "newdisk": true, "mount": true, "unmount": true, "umount": true,
+}
+
+// GO CONCEPT: Satisfying a Third-Party Interface
+// ----------------------------------------------
+// readline.Config.AutoComplete accepts any value with the method
+//
+//	Do(line []rune, pos int) (newLine [][]rune, length int)
+//
+// replCompleter never mentions the readline package — having the method
+// is enough. readline calls Do from its own goroutine while the REPL is
+// blocked in GetLine, so the completer guards its state with a mutex.
+//
+// Compare with Swift: Swift would need an explicit conformance,
+// `extension DOSFileCompleter: AutoCompleter { ... }`.
+//
+// Compare with Python: Python's readline.set_completer() takes a plain
+// function called once per candidate with (text, state).
+
+// replCompleter offers keyword completions in BASIC mode and filename
+// completions in DOS mode.
+type replCompleter struct {
+	mu sync.Mutex
+
+	// fetch lists the current drive's filenames (nil in tests).
+	fetch func() ([]string, error)
+
+	mode   REPLMode
+	files  []string // Cached filenames, valid when cached is true
+	cached bool
+}
+
+// newREPLCompleter creates a completer that lists files through client.
+// A nil client, as in dry-run mode, offers no filenames.
+func newREPLCompleter(client *atticprotocol.Client) *replCompleter {
+	if client == nil {
+		return &replCompleter{}
+	}
+	return &replCompleter{fetch: func() ([]string, error) {
+		return fetchDOSFilenames(client)
+	}}
+}
+
+// fetchDOSFilenames asks the server for the current drive's directory.
+func fetchDOSFilenames(client *atticprotocol.Client) ([]string, error) {
+	if !client.IsConnected() {
+		return nil, atticprotocol.ErrNotConnected
+	}
+	resp, err := client.SendWithTimeout(atticprotocol.NewDosDirectoryCommand(nil), atticprotocol.PingTimeout)
+	if err != nil {
+		return nil, err
+	}
+	entries, err := resp.AsDirectory()
+	if err != nil {
+		return nil, err
+	}
+	names := make([]string, len(entries))
+	for i, entry := range entries {
+		names[i] = entry.Name
+	}
+	return names, nil
+}
+
+// setMode tells the completer which REPL mode is active.
+func (c *replCompleter) setMode(mode REPLMode) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.mode = mode
+}
+
+// invalidate drops the cached listing.
+func (c *replCompleter) invalidate() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.files = nil
+	c.cached = false
+}
+
+// noteCommand invalidates the cache if a DOS mode command line may have
+// changed the current drive's directory.
+func (c *replCompleter) noteCommand(mode REPLMode, line string) {
+	if mode != ModeDOS {
+		return
+	}
+	command, _ := splitCommand(line)
+	if dosDirChangingCommands[command] {
+		c.invalidate()
+	}
+}
+
+// Do implements readline.AutoCompleter. It completes the word before the
+// cursor when it is a BASIC keyword in BASIC mode, or an argument of a DOS
+// file command in DOS mode.
+func (c *replCompleter) Do(line []rune, pos int) ([][]rune, int) {
+	text := strings.TrimLeft(string(line[:pos]), " ")
+	c.mu.Lock()
+	mode := c.mode
+	c.mu.Unlock()
+	if mode == ModeBasic {
+		return completeBASIC(text)
+	}
+
+	command, _ := splitCommand(text)
+	if !strings.Contains(text, " ") || !dosFileCommands[command] {
+		return nil, 0
+	}
+	prefix := text[strings.LastIndex(text, " ")+1:]
+
+	candidates := c.candidates(prefix)
+	suffixes := make([][]rune, len(candidates))
+	for i, name := range candidates {
+		suffixes[i] = []rune(name[len(prefix):])
+	}
+	return suffixes, len([]rune(prefix))
+}
+
+// candidates returns the cached filenames starting with prefix, compared
+// case-insensitively since Atari filenames are uppercase. The listing is
+// fetched on first use; a failed fetch is not cached so a later Tab can
+// try again.
+func (c *replCompleter) candidates(prefix string) []string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.mode != ModeDOS {
+		return nil
+	}
+	if !c.cached && c.fetch != nil {
+		files, err := c.fetch()
+		if err != nil {
+			return nil
+		}
+		c.files, c.cached = files, true
+	}
+
+	upper := strings.ToUpper(prefix)
+	var matches []string
+	for _, name := range c.files {
+		if strings.HasPrefix(strings.ToUpper(name), upper) {
+			matches = append(matches, name)
+		}
+	}
+	sort.Strings(matches)
+	return matches
+}
+
+// completeBASIC completes the word at the end of text, a BASIC mode line
+// up to the cursor. The suffixes follow the case of what was typed, so
+// "pr" completes to "print" and "PR" to "PRINT".
+func completeBASIC(text string) ([][]rune, int) {
+	if strings.Count(text, "\"")%2 == 1 {
+		return nil, 0 // Inside a string literal
+	}
+	start := strings.LastIndexFunc(text, func(r rune) bool { return !isBASICWordRune(r) }) + 1
+	prefix := text[start:]
+	if prefix == "" || !unicode.IsLetter(rune(prefix[0])) {
+		return nil, 0
+	}
+
+	candidates := basicKeywordCandidates(prefix, strings.TrimSpace(text[:start]) == "")
+	lower := prefix == strings.ToLower(prefix)
+	suffixes := make([][]rune, len(candidates))
+	for i, word := range candidates {
+		suffix := word[len(prefix):]
+		if lower {
+			suffix = strings.ToLower(suffix)
+		}
+		suffixes[i] = []rune(suffix)
+	}
+	return suffixes, len(prefix)
+}
+
+// isBASICWordRune reports whether r can be part of a keyword or name.
+func isBASICWordRune(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r) || r == '$'
+}
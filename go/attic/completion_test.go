@@ -0,0 +1,136 @@
+// =============================================================================
+// completion_test.go - Tests for DOS Filename Completion (completion.go)
+// =============================================================================
+
+package main
+
+import (
+	"slices"
+	"testing"
+)
+
+// TestDOSFileCompleterCandidates tests completions from a cached directory.
+func TestDOSFileCompleterCandidates(t *testing.T) {
+	c := &replCompleter{
+		files:  []string{"GAME.BAS", "GAMES.DAT", "DOS.SYS", "README"},
+		cached: true,
+	}
+	c.setMode(ModeDOS)
+
+	tests := []struct {
+		line       string
+		want       []string
+		wantLength int
+	}{
+		{"type GA", []string{"ME.BAS", "MES.DAT"}, 2},
+		{"info ga", []string{"ME.BAS", "MES.DAT"}, 2},
+		{"copy GAME.BAS D", []string{"OS.SYS"}, 1},
+		{"lock ", []string{"DOS.SYS", "GAME.BAS", "GAMES.DAT", "README"}, 0},
+		{"delete X", nil, 1},
+		{"dir GA", nil, 0}, // not a file command
+		{"type", nil, 0},   // still typing the command
+		{"cd GA", nil, 0},  // drives, not files
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.line, func(t *testing.T) {
+			line := []rune(tt.line)
+			got, length := c.Do(line, len(line))
+			var gotStrings []string
+			for _, r := range got {
+				gotStrings = append(gotStrings, string(r))
+			}
+			if !slices.Equal(gotStrings, tt.want) || length != tt.wantLength {
+				t.Errorf("Do(%q) = %q, %d; want %q, %d", tt.line, gotStrings, length, tt.want, tt.wantLength)
+			}
+		})
+	}
+}
+
+// TestDOSFileCompleterCaching verifies that the directory is fetched once,
+// refetched after a directory-changing command, and ignored outside DOS mode.
+func TestDOSFileCompleterCaching(t *testing.T) {
+	fetches := 0
+	c := &replCompleter{fetch: func() ([]string, error) {
+		fetches++
+		return []string{"GAME.BAS"}, nil
+	}}
+
+	c.setMode(ModeBasic)
+	if got := c.candidates("G"); got != nil {
+		t.Errorf("candidates() in BASIC mode = %v, want none", got)
+	}
+
+	c.setMode(ModeDOS)
+	c.candidates("G")
+	c.candidates("GA")
+	if fetches != 1 {
+		t.Errorf("fetches after two completions = %d, want 1", fetches)
+	}
+
+	c.noteCommand(ModeDOS, "type GAME.BAS")
+	c.candidates("G")
+	if fetches != 1 {
+		t.Errorf("fetches after type = %d, want 1", fetches)
+	}
+
+	c.noteCommand(ModeDOS, "DELETE GAME.BAS")
+	c.candidates("G")
+	if fetches != 2 {
+		t.Errorf("fetches after delete = %d, want 2", fetches)
+	}
+}
+
+// TestBASICKeywordCompletion tests keyword completions in BASIC mode.
+func TestBASICKeywordCompletion(t *testing.T) {
+	c := &replCompleter{}
+	c.setMode(ModeBasic)
+
+	tests := []struct {
+		line       string
+		want       []string
+		wantLength int
+	}{
+		{"10 PR", []string{"INT"}, 2},
+		{"10 GO", []string{"SUB", "TO"}, 2},
+		{"10 A$=CH", []string{"R$"}, 2},
+		{"10 IF A THEN GOS", []string{"UB"}, 3},
+		{"10 ? STR", []string{"$", "IG"}, 3},
+		{"pr", []string{"int"}, 2},
+		{"ren", []string{"um", "umber"}, 3}, // mode command at line start
+		{"10 REN", nil, 3},                  // but not inside a program line
+		{`10 PRINT "GO`, nil, 0},            // inside a string
+		{"10 ", nil, 0},
+		{"10 X=12", nil, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.line, func(t *testing.T) {
+			line := []rune(tt.line)
+			got, length := c.Do(line, len(line))
+			var gotStrings []string
+			for _, r := range got {
+				gotStrings = append(gotStrings, string(r))
+			}
+			if !slices.Equal(gotStrings, tt.want) || length != tt.wantLength {
+				t.Errorf("Do(%q) = %q, %d; want %q, %d", tt.line, gotStrings, length, tt.want, tt.wantLength)
+			}
+		})
+	}
+
+	c.setMode(ModeMonitor)
+	if got, _ := c.Do([]rune("PR"), 2); got != nil {
+		t.Errorf("Do() in monitor mode = %q, want none", got)
+	}
+}
+
+// TestCompleterNilClient verifies that a completer without a client, as in
+// dry-run mode, offers no filenames instead of crashing.
+func TestCompleterNilClient(t *testing.T) {
+	c := newREPLCompleter(nil)
+	c.setMode(ModeDOS)
+	line := []rune("type G")
+	if got, _ := c.Do(line, len(line)); len(got) != 0 {
+		t.Errorf("Do(%q) = %q, want none", string(line), got)
+	}
+}
@@ -0,0 +1,92 @@
+// =============================================================================
+// connect.go - Switching Servers from the REPL (.connect / .disconnect)
+// =============================================================================
+//
+// These dot-commands let the user move between emulator servers without
+// restarting the CLI:
+//
+//	.connect <socket-path>   Connect to a Unix socket, e.g. /tmp/attic-1234.sock
+//	.connect <host:port>     Connect to a server over TCP
+//	.disconnect              Drop the connection
+//
+// The same *atticprotocol.Client is reused for every connection, so the
+// event and disconnect handlers installed by main() stay in effect. While
+// disconnected the REPL keeps running, but only dot-commands work.
+//
+// After every successful connection the server's protocol version is
+// checked, and a warning is printed if it is outside the range this CLI
+// understands.
+//
+// =============================================================================
+
+package main
+
+import (
+	"fmt"
+	"net"
+	"os"
+	"strings"
+
+	"github.com/attic/atticprotocol"
+)
+
+// isTCPAddress reports whether a .connect target looks like "host:port"
+// rather than a socket path.
+func isTCPAddress(target string) bool {
+	if strings.HasPrefix(target, "/") || strings.HasPrefix(target, "~") || strings.HasPrefix(target, ".") {
+		return false
+	}
+	_, port, err := net.SplitHostPort(target)
+	return err == nil && port != ""
+}
+
+// handleConnectCommand processes ".connect <target>". Any existing
+// connection is closed first.
+func handleConnectCommand(client *atticprotocol.Client, args string, opts replOptions) {
+	target := strings.TrimSpace(args)
+	if target == "" {
+		printError("usage: .connect <socket-path | host:port>")
+		return
+	}
+	if opts.dryRun {
+		fmt.Println("Dry-run mode: not connecting to " + target)
+		return
+	}
+
+	client.Disconnect()
+
+	var err error
+	if isTCPAddress(target) {
+		err = client.ConnectTCP(target)
+	} else {
+		target = expandPath(target)
+		err = client.Connect(target)
+	}
+	if err != nil {
+		printError(fmt.Sprintf("failed to connect to %s: %v", target, err))
+		fmt.Println("Not connected. Use .connect <socket> to connect to a server.")
+		return
+	}
+	fmt.Printf("Connected to %s\n", client.ConnectedPath())
+	warnIfIncompatible(client)
+}
+
+// warnIfIncompatible prints a warning to stderr when the connected server
+// speaks a protocol version this CLI doesn't support. Commands may still
+// work, so the connection is kept.
+func warnIfIncompatible(client *atticprotocol.Client) {
+	if err := client.CheckServerVersion(); err != nil {
+		fmt.Fprintln(os.Stderr, outputColors.errorText("Warning: "+err.Error()))
+	}
+}
+
+// handleDisconnectCommand processes ".disconnect".
+func handleDisconnectCommand(client *atticprotocol.Client, opts replOptions) {
+	if opts.dryRun || !client.IsConnected() {
+		fmt.Println("Not connected")
+		return
+	}
+	path := client.ConnectedPath()
+	client.Disconnect()
+	fmt.Printf("Disconnected from %s. Use .connect <socket> to reconnect.\n", path)
+}
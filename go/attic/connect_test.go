@@ -0,0 +1,84 @@
+// =============================================================================
+// connect_test.go - Tests for Server Switching (connect.go)
+// =============================================================================
+
+package main
+
+import (
+	"io"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/attic/atticprotocol"
+)
+
+// TestIsTCPAddress verifies how .connect targets are classified.
+func TestIsTCPAddress(t *testing.T) {
+	tests := []struct {
+		target string
+		want   bool
+	}{
+		{"localhost:7000", true},
+		{"127.0.0.1:7000", true},
+		{"[::1]:7000", true},
+		{"/tmp/attic-1234.sock", false},
+		{"~/attic.sock", false},
+		{"./attic.sock", false},
+		{"attic.sock", false},
+	}
+
+	for _, tc := range tests {
+		if got := isTCPAddress(tc.target); got != tc.want {
+			t.Errorf("isTCPAddress(%q) = %v, want %v", tc.target, got, tc.want)
+		}
+	}
+}
+
+// TestWarnIfIncompatible verifies that an unsupported server protocol
+// version produces a warning on stderr, and a supported one does not.
+func TestWarnIfIncompatible(t *testing.T) {
+	tests := []struct {
+		version string
+		warn    bool
+	}{
+		{atticprotocol.ProtocolVersion, false},
+		{"9.0", true},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.version, func(t *testing.T) {
+			server := startMockServer(t, func(cmd string) string {
+				switch cmd {
+				case "ping":
+					return "OK:pong\n"
+				case "version":
+					return "OK:version " + tc.version + "\n"
+				}
+				return "OK:\n"
+			})
+			client := atticprotocol.NewClient()
+			if err := client.Connect(server.socketPath); err != nil {
+				t.Fatalf("Connect() error = %v", err)
+			}
+			defer client.Disconnect()
+
+			oldStderr := os.Stderr
+			r, w, err := os.Pipe()
+			if err != nil {
+				t.Fatalf("failed to create stderr pipe: %v", err)
+			}
+			os.Stderr = w
+			warnIfIncompatible(client)
+			os.Stderr = oldStderr
+			w.Close()
+			data, _ := io.ReadAll(r)
+			r.Close()
+
+			got := strings.Contains(string(data), "Warning:")
+			if got != tc.warn {
+				t.Errorf("warning printed = %v, want %v (stderr: %q)", got, tc.warn, data)
+			}
+		})
+	}
+}
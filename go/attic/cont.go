@@ -0,0 +1,123 @@
+// =============================================================================
+// cont.go - Continuing Past Breakpoint Hits (.cont)
+// =============================================================================
+//
+// ".cont <n>" resumes the emulator and keeps resuming at each breakpoint
+// hit until the nth, then stops there and reports it:
+//
+//	.cont 5
+//	Hit 1/5 at $0612
+//	...
+//	Stopped at $0612 on hit 5  A=$03 X=$00 Y=$10 S=$FD P=$30
+//
+// This debugs "what happens on the fifth time round the loop" without
+// hit-count breakpoints on the server. The counting is done here from
+// breakpoint events, so it works with any breakpoints already set. If no
+// hit arrives within the timeout, or the user presses Ctrl-C, .cont gives
+// up and leaves the emulator running.
+//
+// =============================================================================
+
+package main
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/attic/atticprotocol"
+)
+
+// contTimeout is how long .cont waits for each breakpoint hit. Tests
+// shorten it.
+var contTimeout = 30 * time.Second
+
+// runContCommand handles ".cont [n]".
+func runContCommand(client *atticprotocol.Client, args string, opts replOptions) {
+	hits := 1
+	if arg := strings.TrimSpace(args); arg != "" {
+		n, err := strconv.Atoi(arg)
+		if err != nil || n < 1 {
+			printError("usage: .cont [n]")
+			return
+		}
+		hits = n
+	}
+
+	resume := atticprotocol.NewResumeCommand()
+	if opts.dryRun {
+		fmt.Println("CMD:" + resume.Format())
+		return
+	}
+	if !client.IsConnected() {
+		printError("not connected (use .connect <socket>)")
+		return
+	}
+
+	// Watch events as goto does, keeping the normal event printing.
+	events := make(chan atticprotocol.Event, 8)
+	previous := client.EventHandler()
+	client.SetEventHandler(func(event atticprotocol.Event) {
+		if previous != nil {
+			previous(event)
+		}
+		select {
+		case events <- event:
+		default:
+		}
+	})
+	defer client.SetEventHandler(previous)
+
+	interrupted := make(chan struct{})
+	setInterruptHandler(func() { close(interrupted) })
+	defer setInterruptHandler(nil)
+
+	for hit := 1; ; hit++ {
+		resp, err := client.Send(resume)
+		if err == nil {
+			err = resp.Err()
+		}
+		if err != nil {
+			printError(err.Error())
+			return
+		}
+
+		event, ok := waitForBreakpoint(events, interrupted, hit, hits)
+		if !ok {
+			return
+		}
+		if hit == hits {
+			fmt.Printf("Stopped at $%04X on hit %d  A=$%02X X=$%02X Y=$%02X S=$%02X P=$%02X\n",
+				event.Address, hits, event.A, event.X, event.Y, event.S, event.P)
+			return
+		}
+		fmt.Printf("Hit %d/%d at $%04X\n", hit, hits, event.Address)
+	}
+}
+
+// waitForBreakpoint waits for the next breakpoint event. It reports and
+// returns false if the emulator stops some other way, the user interrupts
+// or the timeout passes.
+func waitForBreakpoint(events <-chan atticprotocol.Event, interrupted <-chan struct{}, hit, hits int) (atticprotocol.Event, bool) {
+	timeout := time.After(contTimeout)
+	for {
+		select {
+		case event := <-events:
+			switch event.Type {
+			case atticprotocol.EventBreakpoint:
+				return event, true
+			case atticprotocol.EventStopped:
+				fmt.Printf("Stopped at $%04X after %d of %d hits\n", event.Address, hit-1, hits)
+				return event, false
+			}
+		case <-interrupted:
+			fmt.Println()
+			printError(fmt.Sprintf(".cont interrupted after %d of %d hits; emulator is still running", hit-1, hits))
+			return atticprotocol.Event{}, false
+		case <-timeout:
+			printError(fmt.Sprintf("no breakpoint hit within %v (%d of %d hits); emulator is still running", contTimeout, hit-1, hits))
+			return atticprotocol.Event{}, false
+		}
+	}
+}
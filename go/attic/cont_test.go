@@ -0,0 +1,83 @@
+// =============================================================================
+// cont_test.go - Tests for Continuing Past Breakpoint Hits (cont.go)
+// =============================================================================
+
+package main
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// contHandler returns a mock server handler that answers each "resume"
+// with a breakpoint event at $0612 while hits remain, counting resumes.
+func contHandler(hits int, mu *sync.Mutex, resumes *int) func(cmd string) string {
+	return func(cmd string) string {
+		switch cmd {
+		case "ping":
+			return "OK:pong\n"
+		case "resume":
+			mu.Lock()
+			defer mu.Unlock()
+			*resumes++
+			if *resumes <= hits {
+				return fmt.Sprintf("OK:resumed\nEVENT:breakpoint $0612 A=$%02X X=$00 Y=$10 S=$FD P=$30\n", *resumes)
+			}
+			return "OK:resumed\n"
+		}
+		return "ERR:unexpected " + cmd + "\n"
+	}
+}
+
+// TestREPLCont verifies that .cont resumes at each hit until the nth.
+func TestREPLCont(t *testing.T) {
+	var mu sync.Mutex
+	var resumes int
+	output := captureREPL(t, ".cont 3\n.quit\n", contHandler(5, &mu, &resumes))
+
+	for _, want := range []string{
+		"Hit 1/3 at $0612\n",
+		"Hit 2/3 at $0612\n",
+		"Stopped at $0612 on hit 3  A=$03 X=$00 Y=$10 S=$FD P=$30\n",
+	} {
+		if !strings.Contains(output, want) {
+			t.Errorf("expected %q in output, got:\n%s", want, output)
+		}
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	if resumes != 3 {
+		t.Errorf("resumed %d times, want 3", resumes)
+	}
+}
+
+// TestREPLContTimeout verifies that .cont gives up when the hits stop.
+func TestREPLContTimeout(t *testing.T) {
+	oldTimeout := contTimeout
+	contTimeout = 50 * time.Millisecond
+	t.Cleanup(func() { contTimeout = oldTimeout })
+
+	var mu sync.Mutex
+	var resumes int
+	output := captureREPL(t, ".cont 4\n.quit\n", contHandler(2, &mu, &resumes))
+
+	if !strings.Contains(output, "Hit 2/4") || strings.Contains(output, "Stopped at") {
+		t.Errorf("expected two hits and no stop, got:\n%s", output)
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	if resumes != 3 {
+		t.Errorf("resumed %d times, want 3", resumes)
+	}
+}
+
+// TestREPLContUsage verifies argument checking in dry-run mode.
+func TestREPLContUsage(t *testing.T) {
+	output := captureREPLWithOptions(t, ".cont\n.cont 0\n.cont x\n.quit\n", nil, replOptions{dryRun: true})
+	if got := strings.Count(output, "CMD:resume"); got != 1 {
+		t.Errorf("expected one CMD:resume, got %d:\n%s", got, output)
+	}
+}
@@ -0,0 +1,65 @@
+// =============================================================================
+// cycles.go - Reading the CPU Cycle Counter (.cycles)
+// =============================================================================
+//
+// ".cycles" shows the emulator's CPU cycle counter. From the second read on
+// it also shows the cycles elapsed since the previous one, so a routine can
+// be timed by reading before and after it runs:
+//
+//	.cycles
+//	1234567 cycles
+//	.cycles
+//	1270135 cycles (+35568 since last .cycles)
+//
+// =============================================================================
+
+package main
+
+import (
+	"fmt"
+
+	"github.com/attic/atticprotocol"
+)
+
+// cycleCounter remembers the last cycle count shown by .cycles.
+type cycleCounter struct {
+	last uint64
+	set  bool
+}
+
+// format renders cycles, with the difference from the previous reading,
+// and records it as the new previous reading.
+func (c *cycleCounter) format(cycles uint64) string {
+	s := fmt.Sprintf("%d cycles", cycles)
+	// A cold reset restarts the counter, so a smaller value has no delta.
+	if c.set && cycles >= c.last {
+		s += fmt.Sprintf(" (+%d since last .cycles)", cycles-c.last)
+	}
+	c.last, c.set = cycles, true
+	return s
+}
+
+// runCyclesCommand handles ".cycles".
+func runCyclesCommand(client *atticprotocol.Client, counter *cycleCounter, opts replOptions) {
+	cmd := atticprotocol.NewCyclesCommand()
+	if opts.dryRun {
+		fmt.Println("CMD:" + cmd.Format())
+		return
+	}
+	if !client.IsConnected() {
+		printError("not connected (use .connect <socket>)")
+		return
+	}
+
+	resp, err := client.Send(cmd)
+	if err != nil {
+		printError(err.Error())
+		return
+	}
+	cycles, err := resp.AsCycles()
+	if err != nil {
+		printError(err.Error())
+		return
+	}
+	fmt.Println(counter.format(cycles))
+}
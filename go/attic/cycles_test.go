@@ -0,0 +1,54 @@
+// =============================================================================
+// cycles_test.go - Tests for Reading the CPU Cycle Counter (cycles.go)
+// =============================================================================
+
+package main
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+// TestREPLCycles verifies that the second .cycles shows the elapsed cycles.
+func TestREPLCycles(t *testing.T) {
+	reads := []uint64{1234567, 1270135}
+	output := captureREPL(t, ".cycles\n.cycles\n.quit\n", func(cmd string) string {
+		switch cmd {
+		case "ping":
+			return "OK:pong\n"
+		case "cycles":
+			n := reads[0]
+			reads = reads[1:]
+			return fmt.Sprintf("OK:cycles %d\n", n)
+		}
+		return "ERR:unexpected " + cmd + "\n"
+	})
+
+	for _, want := range []string{
+		"1234567 cycles\n",
+		"1270135 cycles (+35568 since last .cycles)\n",
+	} {
+		if !strings.Contains(output, want) {
+			t.Errorf("expected %q in output, got:\n%s", want, output)
+		}
+	}
+}
+
+// TestCycleCounterReset verifies that a counter that went backwards, as
+// after a cold reset, shows no difference.
+func TestCycleCounterReset(t *testing.T) {
+	var counter cycleCounter
+	counter.format(5000)
+	if got := counter.format(100); got != "100 cycles" {
+		t.Errorf("format() after reset = %q, want %q", got, "100 cycles")
+	}
+}
+
+// TestREPLCyclesDryRun verifies the protocol command in dry-run mode.
+func TestREPLCyclesDryRun(t *testing.T) {
+	output := captureREPLWithOptions(t, ".cycles\n.quit\n", nil, replOptions{dryRun: true})
+	if !strings.Contains(output, "CMD:cycles") {
+		t.Errorf("expected CMD:cycles, got:\n%s", output)
+	}
+}
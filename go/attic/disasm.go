@@ -0,0 +1,85 @@
+// =============================================================================
+// disasm.go - Saving Disassembly to a Host File (.disasm)
+// =============================================================================
+//
+// ".disasm" disassembles a region of emulator memory and writes the listing
+// to a text file on the host, which is handy for keeping notes during a
+// reverse-engineering session:
+//
+//	.disasm <addr> <lines> <path> [--no-header]
+//
+// The file starts with a comment line recording the address and line
+// count, unless --no-header is given.
+//
+// =============================================================================
+
+package main
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/attic/atticprotocol"
+)
+
+// formatDisassemblyFile builds the file contents for a disassembly listing.
+func formatDisassemblyFile(resp atticprotocol.Response, addr uint16, lines int, header bool) string {
+	var sb strings.Builder
+	if header {
+		fmt.Fprintf(&sb, "; Disassembly of $%04X, %d lines\n", addr, lines)
+	}
+	for _, line := range resp.Lines() {
+		sb.WriteString(line)
+		sb.WriteString("\n")
+	}
+	return sb.String()
+}
+
+// runDisasmCommand handles ".disasm <addr> <lines> <path> [--no-header]".
+func runDisasmCommand(client *atticprotocol.Client, args string, symbols *symbolTable, opts replOptions) {
+	fields := strings.Fields(args)
+	header := true
+	if len(fields) == 4 && fields[3] == "--no-header" {
+		header = false
+		fields = fields[:3]
+	}
+	if len(fields) != 3 {
+		printError("usage: .disasm <addr> <lines> <path> [--no-header]")
+		return
+	}
+
+	addr, ok := symbols.resolveAddress(fields[0])
+	if !ok {
+		printError(fmt.Sprintf("invalid address %q", fields[0]))
+		return
+	}
+	lines, err := strconv.Atoi(fields[1])
+	if err != nil || lines <= 0 {
+		printError(fmt.Sprintf("invalid line count %q", fields[1]))
+		return
+	}
+	path := expandPath(fields[2])
+
+	cmd := atticprotocol.NewDisassembleCommand(&addr, &lines)
+	if opts.dryRun {
+		fmt.Println("CMD:" + cmd.Format())
+		return
+	}
+
+	resp, err := client.Send(cmd)
+	if err == nil {
+		err = resp.Err()
+	}
+	if err != nil {
+		printError(err.Error())
+		return
+	}
+
+	if err := os.WriteFile(path, []byte(formatDisassemblyFile(resp, addr, lines, header)), 0o644); err != nil {
+		printError(fmt.Sprintf("cannot write %s: %v", path, err))
+		return
+	}
+	fmt.Printf("Saved disassembly of $%04X (%d lines) to %s\n", addr, lines, path)
+}
@@ -0,0 +1,73 @@
+// =============================================================================
+// disasm_test.go - Tests for Saving Disassembly (disasm.go)
+// =============================================================================
+
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// disasmHandler answers "disassemble $0600 3" with a three-line listing.
+func disasmHandler(cmd string) string {
+	switch cmd {
+	case "ping":
+		return "OK:pong\n"
+	case "disassemble $0600 3":
+		return "OK:$0600  A9 00     LDA #$00\x1E$0602  8D 00 D4  STA $D400\x1E$0605  60        RTS\n"
+	}
+	return "ERR:unexpected " + cmd + "\n"
+}
+
+// TestREPLDisasmWritesFile verifies that .disasm writes the expanded
+// listing with a header comment.
+func TestREPLDisasmWritesFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "listing.asm")
+
+	output := captureREPL(t, ".disasm $0600 3 "+path+"\n.quit\n", disasmHandler)
+	if !strings.Contains(output, "Saved disassembly of $0600 (3 lines)") {
+		t.Errorf("expected confirmation, got:\n%s", output)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("failed to read listing: %v", err)
+	}
+	want := "; Disassembly of $0600, 3 lines\n" +
+		"$0600  A9 00     LDA #$00\n" +
+		"$0602  8D 00 D4  STA $D400\n" +
+		"$0605  60        RTS\n"
+	if string(data) != want {
+		t.Errorf("file contents = %q, want %q", data, want)
+	}
+}
+
+// TestREPLDisasmNoHeader verifies that --no-header omits the comment line.
+func TestREPLDisasmNoHeader(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "listing.asm")
+
+	_ = captureREPL(t, ".disasm $0600 3 "+path+" --no-header\n.quit\n", disasmHandler)
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("failed to read listing: %v", err)
+	}
+	if !strings.HasPrefix(string(data), "$0600") {
+		t.Errorf("expected no header, got %q", data)
+	}
+}
+
+// TestREPLDisasmUnwritablePath verifies that file errors are reported
+// without leaving a file behind.
+func TestREPLDisasmUnwritablePath(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "missing", "listing.asm")
+
+	_ = captureREPL(t, ".disasm $0600 3 "+path+"\n.quit\n", disasmHandler)
+
+	if _, err := os.Stat(path); err == nil {
+		t.Error("no file should be written to a missing directory")
+	}
+}
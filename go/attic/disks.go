@@ -0,0 +1,90 @@
+// =============================================================================
+// disks.go - Swapping and Ejecting Disks (.swap, .eject)
+// =============================================================================
+//
+// Multi-disk programs ask for "disk 2" partway through. ".swap" changes the
+// disk in a drive in one step instead of an unmount followed by a mount,
+// and ".eject" empties a drive:
+//
+//	.swap 1 ~/disks/side2.atr
+//	.eject 2
+//
+// The drive may also be written D1 or D1:. If the unmount fails, the new
+// disk is not mounted, so the drive is never left with an unexpected disk.
+//
+// =============================================================================
+
+package main
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/attic/atticprotocol"
+)
+
+// parseDriveArg parses a drive number written as "1", "D1" or "D1:".
+func parseDriveArg(s string) (int, bool) {
+	s = strings.TrimSuffix(strings.TrimPrefix(strings.ToUpper(s), "D"), ":")
+	drive, err := strconv.Atoi(s)
+	if err != nil || drive < 1 || drive > 8 {
+		return 0, false
+	}
+	return drive, true
+}
+
+// runSwapCommand handles ".swap <n> <path>".
+func runSwapCommand(client *atticprotocol.Client, args string, opts replOptions) {
+	driveArg, pathArg, _ := strings.Cut(strings.TrimSpace(args), " ")
+	drive, ok := parseDriveArg(driveArg)
+	path := expandPath(strings.TrimSpace(pathArg))
+	if !ok || path == "" {
+		printError("usage: .swap <drive> <path>")
+		return
+	}
+
+	unmount := atticprotocol.NewUnmountCommand(drive)
+	mount := atticprotocol.NewMountCommand(drive, path)
+	if opts.dryRun {
+		fmt.Println("CMD:" + unmount.Format())
+		fmt.Println("CMD:" + mount.Format())
+		return
+	}
+	if !client.IsConnected() {
+		printError("not connected (use .connect <socket>)")
+		return
+	}
+
+	resp, err := client.Send(unmount)
+	if err == nil {
+		err = resp.Err()
+	}
+	if err != nil {
+		printError(fmt.Sprintf("unmount D%d: %v; %s not mounted", drive, err, path))
+		return
+	}
+	resp, err = client.Send(mount)
+	if err == nil {
+		err = resp.Err()
+	}
+	if err != nil {
+		printError(fmt.Sprintf("mount D%d: %v; drive is now empty", drive, err))
+		return
+	}
+	fmt.Printf("D%d: %s\n", drive, path)
+}
+
+// runEjectCommand handles ".eject <n>".
+func runEjectCommand(client *atticprotocol.Client, args string, opts replOptions) {
+	drive, ok := parseDriveArg(strings.TrimSpace(args))
+	if !ok {
+		printError("usage: .eject <drive>")
+		return
+	}
+	if !opts.dryRun && !client.IsConnected() {
+		printError("not connected (use .connect <socket>)")
+		return
+	}
+	sendCommand(client, atticprotocol.NewUnmountCommand(drive), opts)
+}
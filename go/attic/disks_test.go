@@ -0,0 +1,62 @@
+// =============================================================================
+// disks_test.go - Tests for Swapping and Ejecting Disks (disks.go)
+// =============================================================================
+
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+// TestREPLSwap verifies that .swap unmounts before mounting, and stops if
+// the unmount fails.
+func TestREPLSwap(t *testing.T) {
+	var received []string
+	handler := func(cmd string) string {
+		if cmd == "ping" {
+			return "OK:pong\n"
+		}
+		received = append(received, cmd)
+		if cmd == "unmount 2" {
+			return "ERR:Disk image not mounted: 2\n"
+		}
+		return "OK:" + cmd + "ed\n"
+	}
+
+	output := captureREPL(t, ".swap D1: /disks/side2.atr\n.quit\n", handler)
+	want := []string{"unmount 1", "mount 1 /disks/side2.atr"}
+	if strings.Join(received, "|") != strings.Join(want, "|") {
+		t.Errorf("commands = %q, want %q", received, want)
+	}
+	if !strings.Contains(output, "D1: /disks/side2.atr") {
+		t.Errorf("expected swap confirmation, got:\n%s", output)
+	}
+
+	received = nil
+	captureREPL(t, ".swap 2 /disks/side2.atr\n.quit\n", handler)
+	if want := []string{"unmount 2"}; strings.Join(received, "|") != strings.Join(want, "|") {
+		t.Errorf("after failed unmount, commands = %q, want %q", received, want)
+	}
+}
+
+// TestREPLEject verifies .eject and the usage checks in dry-run mode.
+func TestREPLEject(t *testing.T) {
+	output := captureREPLWithOptions(t, ".eject d3\n.eject 9\n.swap 1\n.quit\n", nil, replOptions{dryRun: true})
+	if got := strings.Count(output, "CMD:"); got != 1 || !strings.Contains(output, "CMD:unmount 3") {
+		t.Errorf("expected only CMD:unmount 3, got:\n%s", output)
+	}
+}
+
+func TestParseDriveArg(t *testing.T) {
+	for _, s := range []string{"1", "D1", "d1:", "D1:"} {
+		if drive, ok := parseDriveArg(s); !ok || drive != 1 {
+			t.Errorf("parseDriveArg(%q) = %d, %v; want 1, true", s, drive, ok)
+		}
+	}
+	for _, s := range []string{"", "0", "9", "D", "X1"} {
+		if _, ok := parseDriveArg(s); ok {
+			t.Errorf("parseDriveArg(%q) should fail", s)
+		}
+	}
+}
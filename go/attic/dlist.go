@@ -0,0 +1,217 @@
+// =============================================================================
+// dlist.go - Showing the ANTIC Display List (.dlist)
+// =============================================================================
+//
+// ANTIC builds the screen from a display list: a small program in memory,
+// one instruction per group of scan lines. ".dlist" decodes it:
+//
+//	.dlist            the display list the OS points to (SDLSTL, $0230)
+//	.dlist <addr>     a display list at addr
+//
+//	$9C20  70         8 BLANK x3
+//	$9C23  42 40 9C   MODE 2 (GR.0) LMS $9C40
+//	$9C26  02         MODE 2 (GR.0) x23
+//	$9C3D  41 20 9C   JVB $9C20
+//
+// Identical consecutive instructions are shown once with a count. If the
+// bytes do not form a display list ending in a jump, the raw bytes are
+// shown as a hex dump instead.
+//
+// =============================================================================
+
+package main
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/attic/atticprotocol"
+)
+
+const (
+	// displayListPointer is SDLSTL/SDLSTH, the OS shadow of ANTIC's
+	// display list address.
+	displayListPointer = 0x0230
+
+	// displayListReadSize is how many bytes .dlist reads. A GRAPHICS 8
+	// display list, the longest the OS builds, is about 200 bytes.
+	displayListReadSize = 256
+)
+
+// Display list instruction bits.
+const (
+	anticDLI     = 0x80 // Display list interrupt after this line
+	anticLMS     = 0x40 // Load memory scan: a screen address follows
+	anticVScroll = 0x20 // Vertical fine scrolling
+	anticHScroll = 0x10 // Horizontal fine scrolling
+	anticJump    = 0x01 // Low nibble of JMP and JVB
+	anticJVB     = 0x41 // Jump and wait for vertical blank
+)
+
+// anticModeNames gives the BASIC GRAPHICS mode of each ANTIC mode, where
+// there is one.
+var anticModeNames = map[byte]string{
+	0x2: "GR.0", 0x4: "GR.12", 0x5: "GR.13", 0x6: "GR.1", 0x7: "GR.2",
+	0x8: "GR.3", 0x9: "GR.4", 0xA: "GR.5", 0xB: "GR.6", 0xC: "GR.14",
+	0xD: "GR.7", 0xE: "GR.15", 0xF: "GR.8",
+}
+
+// anticInstruction is one decoded display list instruction, or a run of
+// identical ones.
+type anticInstruction struct {
+	Address uint16
+	Bytes   []byte // The instruction bytes, once for a run
+	Repeat  int    // How many times it occurs in a row
+	Text    string // e.g. "MODE 2 (GR.0) LMS $9C40"
+}
+
+// errNoDisplayListEnd means the bytes ran out before a jump instruction.
+var errNoDisplayListEnd = errors.New("no jump instruction ends the display list")
+
+// describeAnticInstruction returns the text for one instruction; operand
+// is the address following a jump or LMS instruction.
+func describeAnticInstruction(op byte, operand uint16) string {
+	var sb strings.Builder
+	switch {
+	case op&0x4F == anticJVB:
+		fmt.Fprintf(&sb, "JVB $%04X", operand)
+	case op&0x0F == anticJump:
+		fmt.Fprintf(&sb, "JMP $%04X", operand)
+	case op&0x0F == 0:
+		fmt.Fprintf(&sb, "%d BLANK", (op>>4)&0x07+1)
+	default:
+		mode := op & 0x0F
+		fmt.Fprintf(&sb, "MODE %X", mode)
+		if name, ok := anticModeNames[mode]; ok {
+			fmt.Fprintf(&sb, " (%s)", name)
+		}
+		if op&anticLMS != 0 {
+			fmt.Fprintf(&sb, " LMS $%04X", operand)
+		}
+		if op&anticHScroll != 0 {
+			sb.WriteString(" HSCROL")
+		}
+		if op&anticVScroll != 0 {
+			sb.WriteString(" VSCROL")
+		}
+	}
+	if op&anticDLI != 0 {
+		sb.WriteString(" DLI")
+	}
+	return sb.String()
+}
+
+// decodeDisplayList decodes the display list in data, which starts at
+// address, up to and including the first jump instruction.
+func decodeDisplayList(address uint16, data []byte) ([]anticInstruction, error) {
+	var out []anticInstruction
+	for i := 0; i < len(data); {
+		op := data[i]
+		size := 1
+		isJump := op&0x0F == anticJump
+		if isJump || (op&0x0F != 0 && op&anticLMS != 0) {
+			size = 3
+		}
+		if i+size > len(data) {
+			return nil, errNoDisplayListEnd
+		}
+
+		var operand uint16
+		if size == 3 {
+			operand = uint16(data[i+1]) | uint16(data[i+2])<<8
+		}
+		if n := len(out); n > 0 && size == 1 && len(out[n-1].Bytes) == 1 && out[n-1].Bytes[0] == op {
+			out[n-1].Repeat++
+		} else {
+			out = append(out, anticInstruction{
+				Address: address + uint16(i),
+				Bytes:   data[i : i+size],
+				Repeat:  1,
+				Text:    describeAnticInstruction(op, operand),
+			})
+		}
+		i += size
+		if isJump {
+			return out, nil
+		}
+	}
+	return nil, errNoDisplayListEnd
+}
+
+// formatDisplayList renders decoded instructions, one per line.
+func formatDisplayList(instructions []anticInstruction) string {
+	var sb strings.Builder
+	for _, in := range instructions {
+		text := in.Text
+		if in.Repeat > 1 {
+			text += fmt.Sprintf(" x%d", in.Repeat)
+		}
+		hex := make([]string, len(in.Bytes))
+		for i, b := range in.Bytes {
+			hex[i] = fmt.Sprintf("%02X", b)
+		}
+		fmt.Fprintf(&sb, "$%04X  %-9s  %s\n", in.Address, strings.Join(hex, " "), text)
+	}
+	return sb.String()
+}
+
+// runDisplayListCommand handles ".dlist [addr]".
+func runDisplayListCommand(client *atticprotocol.Client, args string, symbols *symbolTable, opts replOptions) {
+	fields := strings.Fields(args)
+	if len(fields) > 1 {
+		printError("usage: .dlist [addr]")
+		return
+	}
+	var address uint16
+	if len(fields) == 1 {
+		var ok bool
+		if address, ok = symbols.resolveAddress(fields[0]); !ok {
+			printError(fmt.Sprintf("invalid address %q", fields[0]))
+			return
+		}
+	}
+
+	if opts.dryRun {
+		if len(fields) == 0 {
+			fmt.Println("CMD:" + atticprotocol.NewReadCommand(displayListPointer, 2).Format())
+			fmt.Printf("CMD:read $<DLIST> %d\n", displayListReadSize)
+			return
+		}
+		fmt.Println("CMD:" + atticprotocol.NewReadCommand(address, displayListReadSize).Format())
+		return
+	}
+	if !client.IsConnected() {
+		printError("not connected (use .connect <socket>)")
+		return
+	}
+
+	if len(fields) == 0 {
+		pointer, err := readMemory(client, displayListPointer, 2)
+		if err != nil {
+			printError(err.Error())
+			return
+		}
+		if len(pointer) < 2 {
+			printError("could not read the display list pointer")
+			return
+		}
+		address = uint16(pointer[0]) | uint16(pointer[1])<<8
+	}
+
+	// Don't read past the top of memory.
+	count := min(displayListReadSize, 0x10000-int(address))
+	data, err := readMemory(client, address, count)
+	if err != nil {
+		printError(err.Error())
+		return
+	}
+
+	instructions, err := decodeDisplayList(address, data)
+	if err != nil {
+		printError(fmt.Sprintf("$%04X: %v; raw bytes follow", address, err))
+		fmt.Print(atticprotocol.FormatHexDump(address, data))
+		return
+	}
+	fmt.Print(formatDisplayList(instructions))
+}
@@ -0,0 +1,110 @@
+// =============================================================================
+// dlist_test.go - Tests for Showing the ANTIC Display List (dlist.go)
+// =============================================================================
+
+package main
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+// gr0DisplayList is the display list the OS builds for GRAPHICS 0 at $9C20.
+func gr0DisplayList() []byte {
+	dl := []byte{0x70, 0x70, 0x70, 0x42, 0x40, 0x9C}
+	for i := 0; i < 23; i++ {
+		dl = append(dl, 0x02)
+	}
+	return append(dl, 0x41, 0x20, 0x9C)
+}
+
+func TestDecodeDisplayList(t *testing.T) {
+	instructions, err := decodeDisplayList(0x9C20, gr0DisplayList())
+	if err != nil {
+		t.Fatalf("decodeDisplayList() error = %v", err)
+	}
+	want := "$9C20  70         8 BLANK x3\n" +
+		"$9C23  42 40 9C   MODE 2 (GR.0) LMS $9C40\n" +
+		"$9C26  02         MODE 2 (GR.0) x23\n" +
+		"$9C3D  41 20 9C   JVB $9C20\n"
+	if got := formatDisplayList(instructions); got != want {
+		t.Errorf("formatDisplayList() =\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestDescribeAnticInstruction(t *testing.T) {
+	tests := []struct {
+		op      byte
+		operand uint16
+		want    string
+	}{
+		{0x00, 0, "1 BLANK"},
+		{0xF0, 0, "8 BLANK DLI"},
+		{0x4F, 0x8010, "MODE F (GR.8) LMS $8010"},
+		{0x03, 0, "MODE 3"},
+		{0x76, 0x3000, "MODE 6 (GR.1) LMS $3000 HSCROL VSCROL"},
+		{0x84, 0, "MODE 4 (GR.12) DLI"},
+		{0x01, 0x9C00, "JMP $9C00"},
+		{0xC1, 0x9C20, "JVB $9C20 DLI"},
+	}
+	for _, tt := range tests {
+		if got := describeAnticInstruction(tt.op, tt.operand); got != tt.want {
+			t.Errorf("describeAnticInstruction($%02X) = %q, want %q", tt.op, got, tt.want)
+		}
+	}
+}
+
+func TestDecodeDisplayListInvalid(t *testing.T) {
+	for name, data := range map[string][]byte{
+		"no jump":       {0x70, 0x70, 0x02, 0x02},
+		"truncated LMS": {0x70, 0x42, 0x40},
+		"truncated JVB": {0x02, 0x41, 0x20},
+	} {
+		if _, err := decodeDisplayList(0x9C20, data); err == nil {
+			t.Errorf("%s: decodeDisplayList() should fail", name)
+		}
+	}
+}
+
+// dlistHandler serves the OS display list pointer and 256 bytes at each
+// display list address.
+func dlistHandler(dl []byte) func(string) string {
+	data := make([]byte, displayListReadSize)
+	copy(data, dl)
+	hex := make([]string, len(data))
+	for i, b := range data {
+		hex[i] = fmt.Sprintf("%02X", b)
+	}
+	return func(cmd string) string {
+		switch cmd {
+		case "ping":
+			return "OK:pong\n"
+		case "read $0230 2":
+			return "OK:data 20,9C\n"
+		case "read $9C20 256", "read $4000 256":
+			return "OK:data " + strings.Join(hex, ",") + "\n"
+		}
+		return "ERR:unexpected " + cmd + "\n"
+	}
+}
+
+// TestREPLDisplayList verifies that .dlist follows the OS pointer.
+func TestREPLDisplayList(t *testing.T) {
+	output := captureREPL(t, ".dlist\n.quit\n", dlistHandler(gr0DisplayList()))
+	if !strings.Contains(output, "$9C23  42 40 9C   MODE 2 (GR.0) LMS $9C40\n") {
+		t.Errorf("expected decoded display list, got:\n%s", output)
+	}
+}
+
+// TestREPLDisplayListRawFallback verifies that bytes that are not a display
+// list are shown as a hex dump.
+func TestREPLDisplayListRawFallback(t *testing.T) {
+	output := captureREPL(t, ".dlist $4000\n.quit\n", dlistHandler([]byte{0x02, 0x02, 0x02}))
+	if !strings.Contains(output, "4000: 02 02 02 00") {
+		t.Errorf("expected a hex dump, got:\n%s", output)
+	}
+	if strings.Contains(output, "MODE") {
+		t.Errorf("should not decode an invalid display list, got:\n%s", output)
+	}
+}
@@ -0,0 +1,74 @@
+// =============================================================================
+// doscheck.go - Checking Disk Integrity (.dos check)
+// =============================================================================
+//
+// ".dos check" asks the server to check the current disk's VTOC and
+// directory against each other, so a damaged disk is noticed before a
+// write makes it worse:
+//
+//	.dos check
+//
+//	2 problems found:
+//	  sector 45 used by GAME.BAS but free in VTOC
+//	  directory entry 3 links to sector 800, past end of disk
+//
+// The check only reads the disk. It works from any mode; in DOS mode a
+// plain "check" sends the same command.
+//
+// =============================================================================
+
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/attic/atticprotocol"
+)
+
+// formatDiskCheck renders a check result for the terminal.
+func formatDiskCheck(result atticprotocol.DiskCheckResult) string {
+	if result.Clean() {
+		return "Disk is clean\n"
+	}
+	var sb strings.Builder
+	noun := "problems"
+	if len(result.Problems) == 1 {
+		noun = "problem"
+	}
+	fmt.Fprintf(&sb, "%d %s found:\n", len(result.Problems), noun)
+	for _, problem := range result.Problems {
+		sb.WriteString("  " + problem + "\n")
+	}
+	return sb.String()
+}
+
+// runDosCheckCommand handles ".dos check".
+func runDosCheckCommand(client *atticprotocol.Client, args string, opts replOptions) {
+	if strings.TrimSpace(args) != "" {
+		printError("usage: .dos check")
+		return
+	}
+
+	cmd := atticprotocol.NewDosCheckCommand()
+	if opts.dryRun {
+		fmt.Println("CMD:" + cmd.Format())
+		return
+	}
+	if !client.IsConnected() {
+		printError("not connected (use .connect <socket>)")
+		return
+	}
+
+	resp, err := client.Send(cmd)
+	if err != nil {
+		printError(err.Error())
+		return
+	}
+	result, err := resp.AsDiskCheck()
+	if err != nil {
+		printError(err.Error())
+		return
+	}
+	fmt.Print(formatDiskCheck(result))
+}
@@ -0,0 +1,56 @@
+// =============================================================================
+// doscheck_test.go - Tests for Checking Disk Integrity (doscheck.go)
+// =============================================================================
+
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+// TestREPLDosCheckClean verifies the report for a consistent disk.
+func TestREPLDosCheckClean(t *testing.T) {
+	output := captureREPL(t, ".dos check\n.quit\n", func(cmd string) string {
+		switch cmd {
+		case "ping":
+			return "OK:pong\n"
+		case "dos check":
+			return "OK:clean\n"
+		}
+		return "ERR:unexpected " + cmd + "\n"
+	})
+
+	if !strings.Contains(output, "Disk is clean") {
+		t.Errorf("expected a clean report, got:\n%s", output)
+	}
+	if strings.Contains(output, "Switched to DOS mode") {
+		t.Errorf(".dos check should not switch modes, got:\n%s", output)
+	}
+}
+
+// TestREPLDosCheckCorrupt verifies that each problem is listed.
+func TestREPLDosCheckCorrupt(t *testing.T) {
+	output := captureREPL(t, ".dos check\n.quit\n", func(cmd string) string {
+		switch cmd {
+		case "ping":
+			return "OK:pong\n"
+		case "dos check":
+			return "OK:errors 2\x1esector 45 used by GAME.BAS but free in VTOC\x1edirectory entry 3 links to sector 800, past end of disk\n"
+		}
+		return "ERR:unexpected " + cmd + "\n"
+	})
+
+	want := "2 problems found:\n  sector 45 used by GAME.BAS but free in VTOC\n  directory entry 3 links to sector 800, past end of disk\n"
+	if !strings.Contains(output, want) {
+		t.Errorf("expected %q, got:\n%s", want, output)
+	}
+}
+
+// TestREPLDosCheckDryRun verifies the command sent.
+func TestREPLDosCheckDryRun(t *testing.T) {
+	output := captureREPLWithOptions(t, ".dos check\n.quit\n", nil, replOptions{dryRun: true})
+	if !strings.Contains(output, "CMD:dos check") {
+		t.Errorf("expected CMD:dos check, got:\n%s", output)
+	}
+}
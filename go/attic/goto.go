@@ -0,0 +1,138 @@
+// =============================================================================
+// goto.go - Run to Cursor (monitor "goto")
+// =============================================================================
+//
+// In monitor mode, "goto <addr>" runs the program until execution reaches
+// the given address, then returns to the prompt:
+//
+//	[monitor] > goto $0642
+//
+// Unlike "g $0642", which jumps there by setting PC, goto leaves PC alone.
+// It sets a one-shot breakpoint at the address, resumes the emulator and
+// waits for the breakpoint event. If no event arrives before the timeout
+// (or the user presses Ctrl-C), the temporary breakpoint is cleared again
+// so it can't fire unexpectedly later.
+//
+// =============================================================================
+
+package main
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/attic/atticprotocol"
+)
+
+// gotoTimeout is how long goto waits for the breakpoint to be reached.
+// Tests shorten it.
+var gotoTimeout = 30 * time.Second
+
+// GO CONCEPT: Wrapping a Callback to Observe Events
+// --------------------------------------------------
+// The client delivers async events to a single callback. To wait for one
+// specific event without losing the normal event printing, goto installs
+// a wrapper that calls the previous handler and also forwards each event
+// into a channel. A deferred call restores the original handler.
+//
+// Compare with Swift: the same effect is achieved by capturing the old
+// closure in a new one: `let old = client.onEvent; client.onEvent = {
+// old?($0); continuation.yield($0) }`.
+//
+// Compare with Python: `old = client.on_event; client.on_event =
+// lambda e: (old and old(e), queue.put_nowait(e))`.
+
+// runGotoCommand handles "goto <addr>" in monitor mode.
+func runGotoCommand(client *atticprotocol.Client, args string, symbols *symbolTable, opts replOptions) {
+	target := strings.TrimSpace(args)
+	if target == "" {
+		printError("usage: goto <addr>")
+		return
+	}
+	addr, ok := symbols.resolveAddress(target)
+	if !ok {
+		printError(fmt.Sprintf("invalid address %q", target))
+		return
+	}
+
+	setBreakpoint := atticprotocol.NewBreakpointSetOnceCommand(addr)
+	if opts.dryRun {
+		fmt.Println("CMD:" + setBreakpoint.Format())
+		fmt.Println("CMD:" + atticprotocol.NewResumeCommand().Format())
+		return
+	}
+
+	resp, err := client.Send(setBreakpoint)
+	if err == nil {
+		err = resp.Err()
+	}
+	if err != nil {
+		printError(err.Error())
+		return
+	}
+
+	events := make(chan atticprotocol.Event, 8)
+	previous := client.EventHandler()
+	client.SetEventHandler(func(event atticprotocol.Event) {
+		if previous != nil {
+			previous(event)
+		}
+		select {
+		case events <- event:
+		default:
+		}
+	})
+	defer client.SetEventHandler(previous)
+
+	interrupted := make(chan struct{})
+	setInterruptHandler(func() { close(interrupted) })
+	defer setInterruptHandler(nil)
+
+	resp, err = client.Send(atticprotocol.NewResumeCommand())
+	if err == nil {
+		err = resp.Err()
+	}
+	if err != nil {
+		clearGotoBreakpoint(client, addr)
+		printError(err.Error())
+		return
+	}
+
+	timeout := time.After(gotoTimeout)
+	for {
+		select {
+		case event := <-events:
+			switch event.Type {
+			case atticprotocol.EventBreakpoint:
+				if event.Address == addr {
+					fmt.Printf("Reached $%04X\n", addr)
+					return
+				}
+				// Stopped at some other breakpoint first.
+				clearGotoBreakpoint(client, addr)
+				fmt.Printf("Stopped at $%04X before reaching $%04X\n", event.Address, addr)
+				return
+			case atticprotocol.EventStopped:
+				clearGotoBreakpoint(client, addr)
+				fmt.Printf("Stopped at $%04X before reaching $%04X\n", event.Address, addr)
+				return
+			}
+		case <-interrupted:
+			clearGotoBreakpoint(client, addr)
+			fmt.Println()
+			printError(fmt.Sprintf("goto $%04X interrupted; emulator is still running", addr))
+			return
+		case <-timeout:
+			clearGotoBreakpoint(client, addr)
+			printError(fmt.Sprintf("$%04X not reached within %v; emulator is still running", addr, gotoTimeout))
+			return
+		}
+	}
+}
+
+// clearGotoBreakpoint removes the temporary breakpoint set by goto. Errors
+// are ignored: the server may already have removed it.
+func clearGotoBreakpoint(client *atticprotocol.Client, addr uint16) {
+	_, _ = client.Send(atticprotocol.NewBreakpointClearCommand(addr))
+}
@@ -0,0 +1,87 @@
+// =============================================================================
+// goto_test.go - Tests for Run to Cursor (goto.go)
+// =============================================================================
+
+package main
+
+import (
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// gotoHandler returns a mock server handler for goto tests. When hit is
+// true, "resume" is followed by a breakpoint event at $0600. Every
+// non-ping command is appended to *sent.
+func gotoHandler(hit bool, mu *sync.Mutex, sent *[]string) func(cmd string) string {
+	return func(cmd string) string {
+		if cmd == "ping" {
+			return "OK:pong\n"
+		}
+		mu.Lock()
+		*sent = append(*sent, cmd)
+		mu.Unlock()
+
+		if cmd == "resume" {
+			if hit {
+				return "OK:resumed\nEVENT:breakpoint $0600 A=$A9 X=$10 Y=$20 S=$FF P=$30\n"
+			}
+			return "OK:resumed\n"
+		}
+		return "OK:\n"
+	}
+}
+
+// TestREPLGotoReachesAddress verifies that goto sets a one-shot breakpoint,
+// resumes, and returns once the breakpoint event arrives.
+func TestREPLGotoReachesAddress(t *testing.T) {
+	var mu sync.Mutex
+	var sent []string
+
+	input := ".monitor\ngoto $0600\n.quit\n"
+	output := captureREPL(t, input, gotoHandler(true, &mu, &sent))
+
+	if !strings.Contains(output, "Reached $0600") {
+		t.Errorf("expected goto to report reaching $0600, got:\n%s", output)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	want := []string{"breakpoint set $0600 once", "resume"}
+	if strings.Join(sent, "|") != strings.Join(want, "|") {
+		t.Errorf("commands sent = %q, want %q", sent, want)
+	}
+}
+
+// TestREPLGotoTimeoutClearsBreakpoint verifies that the temporary
+// breakpoint is removed when the address is never reached.
+func TestREPLGotoTimeoutClearsBreakpoint(t *testing.T) {
+	oldTimeout := gotoTimeout
+	gotoTimeout = 50 * time.Millisecond
+	t.Cleanup(func() { gotoTimeout = oldTimeout })
+
+	var mu sync.Mutex
+	var sent []string
+
+	input := ".monitor\ngoto $0600\n.quit\n"
+	_ = captureREPL(t, input, gotoHandler(false, &mu, &sent))
+
+	mu.Lock()
+	defer mu.Unlock()
+	if len(sent) == 0 || sent[len(sent)-1] != "breakpoint clear $0600" {
+		t.Errorf("expected temporary breakpoint to be cleared, commands sent = %q", sent)
+	}
+}
+
+// TestREPLGotoDryRun verifies the commands goto would send.
+func TestREPLGotoDryRun(t *testing.T) {
+	input := ".monitor\ngoto $0600\n.quit\n"
+	output := captureREPLWithOptions(t, input, nil, replOptions{dryRun: true})
+
+	for _, want := range []string{"CMD:breakpoint set $0600 once", "CMD:resume"} {
+		if !strings.Contains(output, want) {
+			t.Errorf("expected %q in output, got:\n%s", want, output)
+		}
+	}
+}
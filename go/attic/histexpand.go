@@ -0,0 +1,66 @@
+// =============================================================================
+// histexpand.go - Re-running Earlier Commands (!! and !prefix)
+// =============================================================================
+//
+// As in a Unix shell, a line starting with "!" recalls an earlier command
+// from this session instead of being sent as typed:
+//
+//	!!          the previous command
+//	!prefix     the most recent command starting with prefix, e.g. "!d"
+//	            for the last disassembly
+//
+// Anything after the reference is appended, so "!! 20" re-runs the last
+// command with one more argument. The expanded line is printed before it
+// runs, and it is what goes into the recall list, not the "!" form.
+//
+// =============================================================================
+
+package main
+
+import (
+	"fmt"
+	"strings"
+)
+
+// commandRecallSize bounds the commands kept for "!" references.
+const commandRecallSize = 500
+
+// commandRecall holds the commands run in this session, oldest first.
+type commandRecall struct {
+	lines []string
+}
+
+// record remembers line as the most recent command.
+func (c *commandRecall) record(line string) {
+	if len(c.lines) == commandRecallSize {
+		c.lines = c.lines[1:]
+	}
+	c.lines = append(c.lines, line)
+}
+
+// expand replaces a leading "!!" or "!prefix" in line with the command it
+// refers to. It reports whether line was a reference, and fails if nothing
+// matches.
+func (c *commandRecall) expand(line string) (string, bool, error) {
+	if len(line) < 2 || line[0] != '!' {
+		return line, false, nil
+	}
+	ref, rest, _ := strings.Cut(line, " ")
+	if rest != "" {
+		rest = " " + rest
+	}
+
+	if ref == "!!" {
+		if len(c.lines) == 0 {
+			return "", true, fmt.Errorf("!!: no previous command")
+		}
+		return c.lines[len(c.lines)-1] + rest, true, nil
+	}
+	prefix := ref[1:]
+	for i := len(c.lines) - 1; i >= 0; i-- {
+		if strings.HasPrefix(c.lines[i], prefix) {
+			return c.lines[i] + rest, true, nil
+		}
+	}
+	return "", true, fmt.Errorf("%s: no previous command starts with %q", ref, prefix)
+}
@@ -0,0 +1,72 @@
+// =============================================================================
+// histexpand_test.go - Tests for Re-running Earlier Commands (histexpand.go)
+// =============================================================================
+
+package main
+
+import (
+	"strings"
+	"sync"
+	"testing"
+)
+
+func TestCommandRecallExpand(t *testing.T) {
+	var c commandRecall
+	if _, _, err := c.expand("!!"); err == nil {
+		t.Error("!! with no history should fail")
+	}
+	for _, line := range []string{"d $0600 4", "registers", "disassemble $E477", "where"} {
+		c.record(line)
+	}
+
+	tests := []struct {
+		line  string
+		want  string
+		isRef bool
+	}{
+		{"!!", "where", true},
+		{"!! 2", "where 2", true},
+		{"!d", "disassemble $E477", true},
+		{"!d $0700", "disassemble $E477 $0700", true},
+		{"!d ", "disassemble $E477", true},
+		{"!reg", "registers", true},
+		{"!", "!", false},
+		{"status", "status", false},
+	}
+	for _, tt := range tests {
+		got, isRef, err := c.expand(tt.line)
+		if err != nil || got != tt.want || isRef != tt.isRef {
+			t.Errorf("expand(%q) = %q, %v, %v; want %q, %v", tt.line, got, isRef, err, tt.want, tt.isRef)
+		}
+	}
+
+	if _, _, err := c.expand("!x"); err == nil {
+		t.Error("!x with no matching command should fail")
+	}
+}
+
+// TestREPLHistoryExpansion verifies that !! and !prefix re-send earlier
+// commands and that a failed reference sends nothing.
+func TestREPLHistoryExpansion(t *testing.T) {
+	var mu sync.Mutex
+	var received []string
+	output := captureREPL(t, ".monitor\nd $0600 4\nregisters\n!d\n!!\n!zz\n.quit\n", func(cmd string) string {
+		if cmd == "ping" {
+			return "OK:pong\n"
+		}
+		mu.Lock()
+		received = append(received, cmd)
+		mu.Unlock()
+		return "OK:done\n"
+	})
+
+	mu.Lock()
+	defer mu.Unlock()
+	want := []string{"disassemble $0600 4", "registers", "disassemble $0600 4", "disassemble $0600 4"}
+	if strings.Join(received, "\n") != strings.Join(want, "\n") {
+		t.Errorf("server received %q, want %q", received, want)
+	}
+	if !strings.Contains(output, "d $0600 4\n") {
+		t.Errorf("expected the expanded command in the output, got:\n%s", output)
+	}
+}
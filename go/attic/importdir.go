@@ -0,0 +1,160 @@
+// =============================================================================
+// importdir.go - Importing a Host Directory onto a Disk (.importdir)
+// =============================================================================
+//
+// ".importdir" copies every file in a host directory onto the disk in the
+// current drive, one "dos import" per file:
+//
+//	.importdir ~/atari/src
+//
+//	GAME.BAS     <- game.bas        ok
+//	TITLE.SCR    <- title.screen    ok
+//	README       <- README.md       Disk full
+//	Imported 2 of 3 files
+//
+// Each host name is turned into an Atari DOS 8.3 name: upper-cased, with
+// characters DOS doesn't allow dropped and both parts cut to length.
+// Hidden files and subdirectories are skipped. A failed file is reported
+// and the rest are still imported.
+//
+// =============================================================================
+
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/attic/atticprotocol"
+)
+
+// atariFilename converts a host file name into an Atari DOS name: up to
+// eight letters and digits starting with a letter, and an extension of up
+// to three. It returns false if nothing usable is left.
+func atariFilename(name string) (string, bool) {
+	clean := func(s string, max int) string {
+		var sb strings.Builder
+		for _, r := range strings.ToUpper(s) {
+			if (r >= 'A' && r <= 'Z') || (r >= '0' && r <= '9') {
+				sb.WriteRune(r)
+			}
+		}
+		if sb.Len() > max {
+			return sb.String()[:max]
+		}
+		return sb.String()
+	}
+
+	base, ext := name, ""
+	if dot := strings.LastIndexByte(name, '.'); dot > 0 {
+		base, ext = name[:dot], name[dot+1:]
+	}
+	base = strings.TrimLeft(clean(base, len(base)), "0123456789")
+	if len(base) > 8 {
+		base = base[:8]
+	}
+	if base == "" {
+		return "", false
+	}
+	if ext = clean(ext, 3); ext != "" {
+		return base + "." + ext, true
+	}
+	return base, true
+}
+
+// importPlan is one host file and the name it gets on the disk.
+type importPlan struct {
+	hostPath  string
+	hostName  string
+	atariName string
+	problem   string // Set if the file can't be imported
+}
+
+// planImports lists the regular files in dir with their Atari names.
+// Files whose names can't be converted, or clash with an earlier file's,
+// are kept with a problem so they show up in the report.
+func planImports(dir string) ([]importPlan, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	var plans []importPlan
+	used := make(map[string]string)
+	for _, entry := range entries {
+		name := entry.Name()
+		if strings.HasPrefix(name, ".") || !entry.Type().IsRegular() {
+			continue
+		}
+		plan := importPlan{hostPath: filepath.Join(dir, name), hostName: name}
+		atariName, ok := atariFilename(name)
+		switch {
+		case !ok:
+			plan.problem = "no valid Atari name"
+		case used[atariName] != "":
+			plan.atariName = atariName
+			plan.problem = "same Atari name as " + used[atariName]
+		case strings.ContainsAny(plan.hostPath, " \t"):
+			// The server's dos import takes the host path up to the first
+			// space, even if it is quoted or escaped.
+			plan.atariName = atariName
+			plan.problem = "path contains spaces"
+		default:
+			plan.atariName = atariName
+			used[atariName] = name
+		}
+		plans = append(plans, plan)
+	}
+	return plans, nil
+}
+
+// runImportDirCommand handles ".importdir <hostdir>".
+func runImportDirCommand(client *atticprotocol.Client, args string, opts replOptions) {
+	dir := expandPath(strings.TrimSpace(args))
+	if dir == "" {
+		printError("usage: .importdir <hostdir>")
+		return
+	}
+	plans, err := planImports(dir)
+	if err != nil {
+		printError(err.Error())
+		return
+	}
+	if len(plans) == 0 {
+		fmt.Printf("No files to import in %s\n", dir)
+		return
+	}
+
+	if opts.dryRun {
+		for _, plan := range plans {
+			if plan.problem == "" {
+				fmt.Println("CMD:" + atticprotocol.NewDosImportCommand(plan.hostPath, plan.atariName).Format())
+			}
+		}
+		return
+	}
+	if !client.IsConnected() {
+		printError("not connected (use .connect <socket>)")
+		return
+	}
+
+	imported := 0
+	for _, plan := range plans {
+		status := plan.problem
+		if status == "" {
+			resp, err := client.Send(atticprotocol.NewDosImportCommand(plan.hostPath, plan.atariName))
+			if err == nil {
+				err = resp.Err()
+			}
+			if err != nil {
+				status = err.Error()
+			} else {
+				status = "ok"
+				imported++
+			}
+		}
+		fmt.Printf("%-12s <- %-15s %s\n", plan.atariName, plan.hostName, status)
+	}
+	fmt.Printf("Imported %d of %d files\n", imported, len(plans))
+}
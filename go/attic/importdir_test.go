@@ -0,0 +1,128 @@
+// =============================================================================
+// importdir_test.go - Tests for Importing a Host Directory (importdir.go)
+// =============================================================================
+
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// TestAtariFilename verifies the conversion of host names to DOS 8.3 names.
+func TestAtariFilename(t *testing.T) {
+	tests := []struct {
+		name string
+		want string
+		ok   bool
+	}{
+		{"game.bas", "GAME.BAS", true},
+		{"README", "README", true},
+		{"title.screen", "TITLE.SCR", true},
+		{"my-long_filename.txt", "MYLONGFI.TXT", true},
+		{"2048.bas", "", false},
+		{"1st.bas", "ST.BAS", true},
+		{"___", "", false},
+	}
+	for _, tt := range tests {
+		got, ok := atariFilename(tt.name)
+		if got != tt.want || ok != tt.ok {
+			t.Errorf("atariFilename(%q) = %q, %v; want %q, %v", tt.name, got, ok, tt.want, tt.ok)
+		}
+	}
+}
+
+// writeImportDir creates a directory holding the named files.
+func writeImportDir(t *testing.T, names ...string) string {
+	t.Helper()
+	dir := t.TempDir()
+	for _, name := range names {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte("10 END\n"), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return dir
+}
+
+// TestREPLImportDirDryRun verifies one dos import per file, skipping
+// hidden files and subdirectories.
+func TestREPLImportDirDryRun(t *testing.T) {
+	dir := writeImportDir(t, "game.bas", "title.screen", ".DS_Store")
+	if err := os.Mkdir(filepath.Join(dir, "sub"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+
+	output := captureREPLWithOptions(t, ".importdir "+dir+"\n.quit\n", nil, replOptions{dryRun: true})
+	want := "CMD:dos import " + filepath.Join(dir, "game.bas") + " GAME.BAS\n" +
+		"CMD:dos import " + filepath.Join(dir, "title.screen") + " TITLE.SCR\n"
+	if !strings.Contains(output, want) {
+		t.Errorf("expected %q, got:\n%s", want, output)
+	}
+	if strings.Count(output, "CMD:") != 2 {
+		t.Errorf("expected exactly two commands, got:\n%s", output)
+	}
+}
+
+// TestREPLImportDirSpaces verifies that files whose host path contains a
+// space are reported rather than imported, since the server's dos import
+// ends the host path at the first space.
+func TestREPLImportDirSpaces(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "my programs")
+	if err := os.Mkdir(dir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "game.bas"), []byte("10 END\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	plans, err := planImports(dir)
+	if err != nil {
+		t.Fatalf("planImports() error = %v", err)
+	}
+	if len(plans) != 1 || plans[0].problem != "path contains spaces" {
+		t.Errorf("planImports() = %+v, want one plan with a spaces problem", plans)
+	}
+
+	output := captureREPLWithOptions(t, ".importdir "+dir+"\n.quit\n", nil, replOptions{dryRun: true})
+	if strings.Contains(output, "CMD:dos import") {
+		t.Errorf("expected no import, got:\n%s", output)
+	}
+}
+
+// TestREPLImportDirReportsFailures verifies that a failed file is reported
+// and the remaining files are still imported.
+func TestREPLImportDirReportsFailures(t *testing.T) {
+	dir := writeImportDir(t, "game.bas", "music.dat")
+	var sent []string
+	output := captureREPL(t, ".importdir "+dir+"\n.quit\n", func(cmd string) string {
+		if cmd == "ping" {
+			return "OK:pong\n"
+		}
+		sent = append(sent, cmd)
+		if strings.HasSuffix(cmd, " GAME.BAS") {
+			return "ERR:Disk full\n"
+		}
+		return "OK:imported\n"
+	})
+
+	if len(sent) != 2 || !strings.HasPrefix(sent[1], "dos import ") || !strings.HasSuffix(sent[1], " MUSIC.DAT") {
+		t.Errorf("expected two imports, sent %q", sent)
+	}
+	for _, want := range []string{"Disk full", "MUSIC.DAT", "ok", "Imported 1 of 2 files"} {
+		if !strings.Contains(output, want) {
+			t.Errorf("expected %q in output, got:\n%s", want, output)
+		}
+	}
+}
+
+// TestREPLImportDirNameClash verifies that files mapping to the same Atari
+// name are not imported over each other.
+func TestREPLImportDirNameClash(t *testing.T) {
+	dir := writeImportDir(t, "game.bas", "game_.bas")
+	output := captureREPLWithOptions(t, ".importdir "+dir+"\n.quit\n", nil, replOptions{dryRun: true})
+	if strings.Count(output, "CMD:dos import") != 1 {
+		t.Errorf("expected one import, got:\n%s", output)
+	}
+}
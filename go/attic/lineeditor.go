@@ -20,7 +20,10 @@
 //
 // History is stored at ~/.attic_history (same as the Swift CLI) with a
 // 500-entry limit and duplicate suppression, so users can switch between
-// the Go and Swift CLIs and keep their history.
+// the Go and Swift CLIs and keep their history. Piped sessions normally
+// leave history alone; with --save-history their commands (except
+// dot-commands) are appended to the same file on exit. --no-history
+// turns history off for a session: the file is neither read nor written.
 //
 // =============================================================================
 
@@ -139,6 +142,31 @@ type LineEditor struct {
 	// scanner reads lines from stdin in non-interactive mode.
 	// It's nil when running in interactive mode.
 	scanner *bufio.Scanner
+
+	// historyPath is where non-interactive commands are saved on Close.
+	// Empty (the default) means piped sessions don't touch history.
+	historyPath string
+
+	// pendingHistory collects non-interactive commands until Close.
+	pendingHistory []string
+
+	// noHistory turns history off entirely (--no-history): nothing is
+	// loaded, recorded or saved.
+	noHistory bool
+}
+
+// lineEditorConfig holds the settings newLineEditorWithConfig uses.
+// NewLineEditor fills it in from the environment.
+type lineEditorConfig struct {
+	// interactive selects readline line editing over plain line reading.
+	interactive bool
+
+	// historyPath is the history file readline loads and saves.
+	historyPath string
+
+	// noHistory keeps the session out of history: no file is read or
+	// written and no entries are recorded.
+	noHistory bool
 }
 
 // GO CONCEPT: Factory Functions (Constructors)
@@ -176,6 +204,12 @@ type LineEditor struct {
 // under Emacs (e.g., via M-x shell or comint), we always use non-interactive
 // mode because Emacs provides its own line editing.
 func NewLineEditor() *LineEditor {
+	return newLineEditorWithConfig(defaultLineEditorConfig())
+}
+
+// defaultLineEditorConfig returns the configuration NewLineEditor uses:
+// interactive on a terminal, with history in ~/.attic_history.
+func defaultLineEditorConfig() lineEditorConfig {
 	// GO CONCEPT: TTY Detection
 	// -------------------------
 	// A TTY (teletypewriter) is an interactive terminal device. When stdin
@@ -201,13 +235,22 @@ func NewLineEditor() *LineEditor {
 	isInteractive := term.IsTerminal(int(os.Stdin.Fd())) &&
 		os.Getenv("INSIDE_EMACS") == ""
 
-	if !isInteractive {
+	return lineEditorConfig{
+		interactive: isInteractive,
+		historyPath: filepath.Join(homeDir(), historyFileName),
+	}
+}
+
+// newLineEditorWithConfig creates a LineEditor with the given settings.
+func newLineEditorWithConfig(cfg lineEditorConfig) *LineEditor {
+	if !cfg.interactive {
 		// Non-interactive mode: use bufio.Scanner for simple line reading.
 		// The scanner reads from os.Stdin line by line. We print prompts
 		// manually to stdout before each read.
 		return &LineEditor{
 			interactive: false,
 			scanner:     bufio.NewScanner(os.Stdin),
+			noHistory:   cfg.noHistory,
 		}
 	}
 
@@ -231,7 +274,11 @@ func NewLineEditor() *LineEditor {
 	//   Config(history_file=path, history_limit=500)
 	// Python's **kwargs allows arbitrary keyword arguments; Go's struct
 	// literals are strictly typed.
-	historyPath := filepath.Join(homeDir(), historyFileName)
+	// Without a history file readline neither loads nor saves history.
+	historyPath := cfg.historyPath
+	if cfg.noHistory {
+		historyPath = ""
+	}
 
 	rl, err := readline.NewFromConfig(&readline.Config{
 		// HistoryFile specifies where to persist command history.
@@ -262,12 +309,14 @@ func NewLineEditor() *LineEditor {
 		return &LineEditor{
 			interactive: false,
 			scanner:     bufio.NewScanner(os.Stdin),
+			noHistory:   cfg.noHistory,
 		}
 	}
 
 	return &LineEditor{
 		interactive: true,
 		rl:          rl,
+		noHistory:   cfg.noHistory,
 	}
 }
 
@@ -365,7 +414,7 @@ func (le *LineEditor) getInteractiveLine(prompt string) (string, error) {
 	// Save the line to history if it's non-empty.
 	// Empty lines (just pressing Enter) aren't worth remembering.
 	trimmed := strings.TrimSpace(line)
-	if trimmed != "" {
+	if trimmed != "" && !le.noHistory {
 		le.rl.SaveToHistory(trimmed)
 	}
 
@@ -390,13 +439,74 @@ func (le *LineEditor) getNonInteractiveLine(prompt string) (string, error) {
 		return "", io.EOF
 	}
 
-	return le.scanner.Text(), nil
+	line := le.scanner.Text()
+	if le.historyPath != "" {
+		// Dot-commands are session control, not worth recalling later.
+		trimmed := strings.TrimSpace(line)
+		if trimmed != "" && !strings.HasPrefix(trimmed, ".") {
+			le.pendingHistory = append(le.pendingHistory, trimmed)
+		}
+	}
+	return line, nil
+}
+
+// SaveHistoryTo makes a non-interactive editor append the commands it reads
+// to the history file at path when it is closed (--save-history). It has no
+// effect in interactive mode, where readline already manages the file, or
+// when history is off.
+func (le *LineEditor) SaveHistoryTo(path string) {
+	if !le.interactive && !le.noHistory {
+		le.historyPath = path
+	}
+}
+
+// SetCompleter installs the tab-completion handler. It has no effect in
+// non-interactive mode, where there is no Tab key to press.
+func (le *LineEditor) SetCompleter(completer readline.AutoCompleter) {
+	if le.rl == nil {
+		return
+	}
+	cfg := le.rl.GetConfig()
+	cfg.AutoComplete = completer
+	if err := le.rl.SetConfig(cfg); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: tab completion unavailable (%v)\n", err)
+	}
+}
+
+// appendHistory appends entries to the history file at path, skipping any
+// entry identical to the one before it, and keeps only the newest limit
+// entries — the same rules readline applies to interactive history.
+func appendHistory(path string, entries []string, limit int) error {
+	var history []string
+	data, err := os.ReadFile(path)
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	for _, line := range strings.Split(string(data), "\n") {
+		if line != "" {
+			history = append(history, line)
+		}
+	}
+
+	for _, entry := range entries {
+		if len(history) > 0 && history[len(history)-1] == entry {
+			continue
+		}
+		history = append(history, entry)
+	}
+	if len(history) > limit {
+		history = history[len(history)-limit:]
+	}
+
+	return os.WriteFile(path, []byte(strings.Join(history, "\n")+"\n"), 0o600)
 }
 
 // Close releases resources held by the LineEditor.
 //
 // In interactive mode, this saves the command history to disk and closes
-// the readline instance. In non-interactive mode, this is a no-op.
+// the readline instance. In non-interactive mode, it appends the session's
+// commands to the history file if SaveHistoryTo was called, and otherwise
+// does nothing.
 //
 // Close is safe to call multiple times (idempotent). After Close(), further
 // calls to GetLine will fail.
@@ -432,6 +542,12 @@ func (le *LineEditor) Close() {
 		le.rl.Close()
 		le.rl = nil
 	}
+	if len(le.pendingHistory) > 0 {
+		if err := appendHistory(le.historyPath, le.pendingHistory, historySize); err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: could not save history: %v\n", err)
+		}
+		le.pendingHistory = nil
+	}
 }
 
 // IsInteractive returns whether the line editor is running in interactive
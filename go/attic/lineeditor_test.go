@@ -924,6 +924,126 @@ func TestAllModePromptsWorkWithGetLine(t *testing.T) {
 	}
 }
 
+// =============================================================================
+// History Saving for Piped Sessions (--save-history)
+// =============================================================================
+
+// TestSaveHistoryFromPipedSession verifies that a piped session appends its
+// commands to the history file on Close, skipping dot-commands, blank lines
+// and consecutive duplicates.
+func TestSaveHistoryFromPipedSession(t *testing.T) {
+	historyPath := filepath.Join(t.TempDir(), ".attic_history")
+	if err := os.WriteFile(historyPath, []byte("status\n"), 0o600); err != nil {
+		t.Fatalf("failed to seed history: %v", err)
+	}
+
+	editor, writer := newTestEditor(t)
+	editor.SaveHistoryTo(historyPath)
+
+	fmt.Fprint(writer, "status\n.monitor\n\nread $0600 16\nread $0600 16\n  step  \n.quit\n")
+	writer.Close()
+	for {
+		if _, err := editor.GetLine("> "); err != nil {
+			break
+		}
+	}
+	editor.Close()
+
+	data, err := os.ReadFile(historyPath)
+	if err != nil {
+		t.Fatalf("failed to read history: %v", err)
+	}
+	want := "status\nread $0600 16\nstep\n"
+	if string(data) != want {
+		t.Errorf("history = %q, want %q", data, want)
+	}
+}
+
+// TestSaveHistoryDisabledByDefault verifies that piped sessions leave the
+// history alone unless SaveHistoryTo is called.
+func TestSaveHistoryDisabledByDefault(t *testing.T) {
+	editor, writer := newTestEditor(t)
+	fmt.Fprint(writer, "status\n")
+	writer.Close()
+	_, _ = editor.GetLine("> ")
+
+	if len(editor.pendingHistory) != 0 {
+		t.Errorf("pendingHistory = %q, want none", editor.pendingHistory)
+	}
+}
+
+// TestAppendHistoryLimit verifies that only the newest entries are kept.
+func TestAppendHistoryLimit(t *testing.T) {
+	historyPath := filepath.Join(t.TempDir(), ".attic_history")
+	if err := os.WriteFile(historyPath, []byte("a\nb\nc\n"), 0o600); err != nil {
+		t.Fatalf("failed to seed history: %v", err)
+	}
+
+	if err := appendHistory(historyPath, []string{"d", "e"}, 3); err != nil {
+		t.Fatalf("appendHistory() error = %v", err)
+	}
+
+	data, _ := os.ReadFile(historyPath)
+	if string(data) != "c\nd\ne\n" {
+		t.Errorf("history = %q, want %q", data, "c\nd\ne\n")
+	}
+}
+
 // Ensure bufio is used (it's imported in the test helpers even though
 // some tests use the higher-level newTestEditor helper).
 var _ = bufio.NewScanner
+
+// TestNoHistoryCreatesNoFile verifies that with history off neither an
+// interactive nor a piped editor creates the history file, even when asked
+// to save piped history.
+func TestNoHistoryCreatesNoFile(t *testing.T) {
+	historyPath := filepath.Join(t.TempDir(), ".attic_history")
+
+	editor := newLineEditorWithConfig(lineEditorConfig{interactive: true, historyPath: historyPath, noHistory: true})
+	editor.Close()
+	if _, err := os.Stat(historyPath); !os.IsNotExist(err) {
+		t.Errorf("interactive editor created %s (err %v)", historyPath, err)
+	}
+
+	oldStdin := os.Stdin
+	reader, writer, err := os.Pipe()
+	if err != nil {
+		t.Fatalf("failed to create pipe: %v", err)
+	}
+	os.Stdin = reader
+	t.Cleanup(func() {
+		os.Stdin = oldStdin
+		reader.Close()
+	})
+
+	editor = newLineEditorWithConfig(lineEditorConfig{historyPath: historyPath, noHistory: true})
+	editor.SaveHistoryTo(historyPath)
+	fmt.Fprint(writer, "status\nread $0600 16\n")
+	writer.Close()
+	for {
+		if _, err := editor.GetLine("> "); err != nil {
+			break
+		}
+	}
+	if len(editor.pendingHistory) != 0 {
+		t.Errorf("pendingHistory = %q, want none", editor.pendingHistory)
+	}
+	editor.Close()
+	if _, err := os.Stat(historyPath); !os.IsNotExist(err) {
+		t.Errorf("piped editor created %s (err %v)", historyPath, err)
+	}
+}
+
+// TestHistoryFileCreatedByDefault verifies the contrast case: an
+// interactive editor with history creates its file.
+func TestHistoryFileCreatedByDefault(t *testing.T) {
+	historyPath := filepath.Join(t.TempDir(), ".attic_history")
+	editor := newLineEditorWithConfig(lineEditorConfig{interactive: true, historyPath: historyPath})
+	if !editor.IsInteractive() {
+		t.Skip("readline unavailable in this environment")
+	}
+	editor.Close()
+	if _, err := os.Stat(historyPath); err != nil {
+		t.Errorf("expected history file: %v", err)
+	}
+}
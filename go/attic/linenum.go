@@ -0,0 +1,76 @@
+// =============================================================================
+// linenum.go - Numbering Lines of Long Responses (.set linenum)
+// =============================================================================
+//
+// ".set linenum on" prefixes each line of a multi-line response (a
+// disassembly, a hex dump, a directory listing) with a 1-based index, so
+// lines can be referred to by number when discussing the output:
+//
+//	.set linenum on
+//	d $E477 3
+//	1  $E477  4C 6C E4  JMP $E46C
+//	2  $E47A  4C 7A E4  JMP $E47A
+//	3  $E47D  A9 00     LDA #$00
+//
+// Numbering is off by default. Single-line responses are never numbered.
+//
+// =============================================================================
+
+package main
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// outputLineNumbers is toggled by ".set linenum on|off".
+var outputLineNumbers bool
+
+// numberLines prefixes each line of text with its 1-based index, right
+// aligned so the text columns stay lined up.
+func numberLines(text string) string {
+	lines := strings.Split(text, "\n")
+	width := len(strconv.Itoa(len(lines)))
+	for i, line := range lines {
+		lines[i] = fmt.Sprintf("%*d  %s", width, i+1, line)
+	}
+	return strings.Join(lines, "\n")
+}
+
+// displayText returns multi-line response text as it should be shown,
+// numbered if line numbers are on.
+func displayText(text string) string {
+	if outputLineNumbers && strings.Contains(text, "\n") {
+		return numberLines(text)
+	}
+	return text
+}
+
+// handleSetCommand handles ".set <option> [on|off]". Without a value it
+// shows the option's current setting.
+func handleSetCommand(args string) {
+	fields := strings.Fields(strings.ToLower(args))
+	if len(fields) == 0 || len(fields) > 2 || fields[0] != "linenum" {
+		printError("usage: .set linenum [on|off]")
+		return
+	}
+	if len(fields) == 1 {
+		if outputLineNumbers {
+			fmt.Println("linenum is on")
+		} else {
+			fmt.Println("linenum is off")
+		}
+		return
+	}
+	switch fields[1] {
+	case "on":
+		outputLineNumbers = true
+		fmt.Println("Line numbers enabled")
+	case "off":
+		outputLineNumbers = false
+		fmt.Println("Line numbers disabled")
+	default:
+		printError("usage: .set linenum [on|off]")
+	}
+}
@@ -0,0 +1,56 @@
+// =============================================================================
+// linenum_test.go - Tests for Numbering Lines of Long Responses (linenum.go)
+// =============================================================================
+
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+// TestNumberLines verifies the index prefix and its alignment.
+func TestNumberLines(t *testing.T) {
+	text := strings.Repeat("x\n", 9) + "y"
+	got := numberLines(text)
+	if !strings.HasPrefix(got, " 1  x\n 2  x\n") {
+		t.Errorf("numberLines() starts %q, want right-aligned indexes", got[:12])
+	}
+	if !strings.HasSuffix(got, "\n10  y") {
+		t.Errorf("numberLines() ends %q, want %q", got[len(got)-6:], "\n10  y")
+	}
+}
+
+// TestREPLLineNumbers verifies numbered and unnumbered multi-line output,
+// and that single-line responses are left alone.
+func TestREPLLineNumbers(t *testing.T) {
+	defer func() { outputLineNumbers = false }()
+
+	handler := func(cmd string) string {
+		switch cmd {
+		case "ping":
+			return "OK:pong\n"
+		case "disassemble":
+			return "OK:$0600  A9 00     LDA #$00\x1E$0602  60        RTS\n"
+		}
+		return "OK:" + cmd + "\n"
+	}
+
+	output := captureREPL(t, ".monitor\ndisassemble\n.quit\n", handler)
+	if !strings.Contains(output, "$0600  A9 00     LDA #$00\n$0602  60        RTS\n") {
+		t.Errorf("expected unnumbered listing, got:\n%s", output)
+	}
+
+	output = captureREPL(t, ".monitor\n.set linenum on\ndisassemble\nstatus\n.quit\n", handler)
+	if !strings.Contains(output, "1  $0600  A9 00     LDA #$00\n2  $0602  60        RTS\n") {
+		t.Errorf("expected numbered listing, got:\n%s", output)
+	}
+	if strings.Contains(output, "1  status") {
+		t.Errorf("single-line response was numbered, got:\n%s", output)
+	}
+
+	output = captureREPL(t, ".set linenum off\ndisassemble\n.quit\n", handler)
+	if strings.Contains(output, "1  $0600") {
+		t.Errorf("expected numbering off, got:\n%s", output)
+	}
+}
@@ -65,9 +65,13 @@ package main
 // though linters like flake8 flag them. Python also has no automatic
 // import formatting built into the compiler.
 import (
+	"errors"
 	"fmt"
+	"io"
 	"os"
 	"os/signal"
+	"path/filepath"
+	"sync"
 	"syscall"
 
 	"github.com/attic/atticprotocol"
@@ -234,6 +238,39 @@ type arguments struct {
 	// Defaults to true for accurate visual representation in terminals.
 	atascii bool
 
+	// dryRun prints translated protocol commands instead of sending them.
+	// The CLI does not connect to (or launch) a server in this mode.
+	dryRun bool
+
+	// screenshotDir is the directory for auto-named screenshots taken with
+	// ".screenshot" and no path. Empty means ~/Desktop.
+	screenshotDir string
+
+	// pager enables paging of long responses in interactive sessions.
+	pager bool
+
+	// quiet suppresses the welcome banner and connection progress messages.
+	// Command responses and errors are still printed.
+	quiet bool
+
+	// echo prints each command line before its response in piped
+	// (non-interactive) sessions, so the output reads as a transcript.
+	echo bool
+
+	// saveHistory makes piped (non-interactive) sessions append their
+	// commands to ~/.attic_history on exit.
+	saveHistory bool
+
+	// noHistory keeps the session out of ~/.attic_history entirely, even
+	// in interactive mode.
+	noHistory bool
+
+	// color is the --color policy (auto, always, never).
+	color colorMode
+
+	// autoPause is the --auto-pause policy (on, resume, off).
+	autoPause autoPauseMode
+
 	// showHelp causes usage information to be printed and the program to exit.
 	showHelp bool
 
@@ -287,14 +324,15 @@ type arguments struct {
 // parseArguments parses command-line arguments.
 //
 // This is a simple hand-written parser matching the Swift CLI's behavior.
-// There are only 6 flags and no subcommands, so a framework like cobra
+// There are only a handful of flags and no subcommands, so a framework like cobra
 // would be over-engineering.
 func parseArguments() arguments {
 	// Create an arguments struct with atascii defaulting to true.
 	// This is a "struct literal" — you can name specific fields and all
 	// others get their zero values (false for bool, "" for string).
 	args := arguments{
-		atascii: true, // Default: rich ATASCII rendering enabled
+		atascii:   true,        // Default: rich ATASCII rendering enabled
+		autoPause: autoPauseOn, // Default: pause before monitor writes
 	}
 
 	// os.Args is a []string (slice of strings) with all command-line arguments.
@@ -351,6 +389,58 @@ func parseArguments() arguments {
 			args.socketPath = remaining[0]
 			remaining = remaining[1:]
 
+		case "--screenshot-dir":
+			if len(remaining) == 0 {
+				printError("--screenshot-dir requires a directory argument")
+				os.Exit(1)
+			}
+			args.screenshotDir = remaining[0]
+			remaining = remaining[1:]
+
+		case "--color":
+			if len(remaining) == 0 {
+				printError("--color requires auto, always, or never")
+				os.Exit(1)
+			}
+			mode, ok := parseColorMode(remaining[0])
+			if !ok {
+				printError(fmt.Sprintf("Invalid --color value: %s (use auto, always, or never)", remaining[0]))
+				os.Exit(1)
+			}
+			args.color = mode
+			remaining = remaining[1:]
+
+		case "--auto-pause":
+			if len(remaining) == 0 {
+				printError("--auto-pause requires on, resume, or off")
+				os.Exit(1)
+			}
+			mode, ok := parseAutoPauseMode(remaining[0])
+			if !ok {
+				printError(fmt.Sprintf("Invalid --auto-pause value: %s (use on, resume, or off)", remaining[0]))
+				os.Exit(1)
+			}
+			args.autoPause = mode
+			remaining = remaining[1:]
+
+		case "--pager":
+			args.pager = true
+
+		case "--save-history":
+			args.saveHistory = true
+
+		case "--no-history":
+			args.noHistory = true
+
+		case "--quiet":
+			args.quiet = true
+
+		case "--echo":
+			args.echo = true
+
+		case "--dry-run":
+			args.dryRun = true
+
 		// Multiple values in one case — equivalent to Swift's "case "--help", "-h":"
 		case "--help", "-h":
 			args.showHelp = true
@@ -383,6 +473,17 @@ OPTIONS:
   --silent            Disable audio output
   --plain             Plain ASCII rendering (no ANSI codes or Unicode)
   --socket <path>     Connect to existing server at specific socket path
+  --color <when>      Colorize output: auto, always, or never (default auto)
+  --auto-pause <when> Pause before monitor writes and .loadbin: on, resume, or off
+                      (default on; resume continues the program afterwards)
+  --pager             Page long responses (uses $PAGER if set)
+  --dry-run           Print translated protocol commands without sending
+  --save-history      Save commands from piped input to the history file
+  --no-history        Don't read, record or save command history
+  --quiet             Don't print the welcome banner or connection messages
+  --echo              Print each piped command as "+ <command>" before its output
+  --screenshot-dir <dir>
+                      Directory for auto-named screenshots (default ~/Desktop)
   --help, -h          Show this help
   --version, -v       Show version
 
@@ -391,10 +492,14 @@ DISPLAY:
   characters and Unicode glyphs for ATASCII graphics. Use --plain for
   clean ASCII output compatible with text files and simple terminals.
 
+  Set ATTIC_PROMPT to customize the prompt, e.g. ATTIC_PROMPT='attic[%m]> '
+  where %m is the mode name.
+
 EXAMPLES:
   attic-go                                Launch server and connect REPL
   attic-go --plain                        Use plain ASCII rendering
   attic-go --socket /tmp/attic-1234.sock  Connect to existing server
+  attic-go --dry-run                      Show wire commands (no server)
 
 MODES:
   The REPL operates in three modes. Switch with dot-commands:
@@ -425,9 +530,38 @@ func printVersion() {
 // Python's print() takes a `file` keyword argument. You can also use
 // `sys.stderr.write(f"Error: {msg}\n")`.
 
+// printWelcome writes the welcome banner and the session status line to w,
+// unless --quiet was given.
+func printWelcome(w io.Writer, args arguments) {
+	if args.quiet {
+		return
+	}
+	fmt.Fprint(w, welcomeBanner())
+	if args.dryRun {
+		fmt.Fprintln(w, "Dry-run mode: protocol commands are printed, not sent")
+	} else {
+		fmt.Fprintln(w, "Connected to AtticServer via CLI protocol")
+	}
+	fmt.Fprintln(w)
+}
+
+// newREPLLineEditor creates the REPL's LineEditor, enabling history saving
+// for piped sessions when --save-history was given, or turning history off
+// with --no-history.
+func newREPLLineEditor(args arguments) *LineEditor {
+	cfg := defaultLineEditorConfig()
+	cfg.noHistory = args.noHistory
+	editor := newLineEditorWithConfig(cfg)
+	if args.saveHistory {
+		editor.SaveHistoryTo(filepath.Join(homeDir(), historyFileName))
+	}
+	return editor
+}
+
 // printError prints an error message to stderr.
+// When color output is enabled the message is shown in red.
 func printError(message string) {
-	fmt.Fprintf(os.Stderr, "Error: %s\n", message)
+	fmt.Fprintln(os.Stderr, outputColors.errorText("Error: "+message))
 }
 
 // =============================================================================
@@ -501,13 +635,20 @@ func discoverOrConnect(args arguments) (*atticprotocol.Client, int) {
 		// User specified a socket path explicitly
 		socketPath = args.socketPath
 	} else {
-		// Try to discover an existing server
-		socketPath = atticprotocol.DiscoverSocket()
+		// Try to discover an existing server. A directory that can't be
+		// read is worth mentioning, since it hides any running server.
+		var err error
+		socketPath, err = atticprotocol.DiscoverSocketErr()
+		if err != nil && !errors.Is(err, atticprotocol.ErrSocketNotFound) {
+			printError(fmt.Sprintf("Server discovery failed: %v", err))
+		}
 	}
 
 	// If no socket found, launch a new server
 	if socketPath == "" {
-		fmt.Println("No running AtticServer found. Launching...")
+		if !args.quiet {
+			fmt.Println("No running AtticServer found. Launching...")
+		}
 
 		// GO CONCEPT: Error Handling with Multiple Returns
 		// -------------------------------------------------
@@ -536,7 +677,9 @@ func discoverOrConnect(args arguments) (*atticprotocol.Client, int) {
 			printError("You can start it manually with: AtticServer")
 			os.Exit(1)
 		}
-		fmt.Printf("AtticServer started (PID: %d)\n", launchedPid)
+		if !args.quiet {
+			fmt.Printf("AtticServer started (PID: %d)\n", launchedPid)
+		}
 	}
 
 	// GO CONCEPT: Short Variable Scoping in if
@@ -559,11 +702,18 @@ func discoverOrConnect(args arguments) (*atticprotocol.Client, int) {
 	// no block scoping for if/while statements.
 
 	// Connect to the socket
-	fmt.Printf("Connecting to %s...\n", socketPath)
+	if !args.quiet {
+		fmt.Printf("Connecting to %s...\n", socketPath)
+	}
 	if err := client.Connect(socketPath); err != nil {
 		printError(fmt.Sprintf("Failed to connect to AtticServer: %v", err))
 		os.Exit(1)
 	}
+	warnIfIncompatible(client)
+
+	// Adopt the server's multi-line separator, if it advertises one, so
+	// responses split the way the server meant.
+	client.NegotiateSeparator()
 
 	return client, launchedPid
 }
@@ -641,13 +791,46 @@ func setupSignalHandler(cleanup func()) {
 	// The trailing "()" is important — it immediately CALLS the anonymous
 	// function. "go func() { ... }" without "()" would be a syntax error.
 	go func() {
-		<-sigCh       // Block until a signal is received (the value is discarded)
-		fmt.Println() // Print a newline after ^C for clean terminal output
-		cleanup()     // Run the cleanup function passed by the caller
-		os.Exit(0)
+		for sig := range sigCh {
+			// A long-running REPL command (such as .watchmem) may claim
+			// Ctrl-C so that it stops the command instead of the CLI.
+			if sig == syscall.SIGINT {
+				if interrupt := takeInterruptHandler(); interrupt != nil {
+					interrupt()
+					continue
+				}
+			}
+			fmt.Println() // Print a newline after ^C for clean terminal output
+			cleanup()     // Run the cleanup function passed by the caller
+			os.Exit(0)
+		}
 	}()
 }
 
+// interruptMu guards interruptHandler.
+var interruptMu sync.Mutex
+
+// interruptHandler, when set, is called on SIGINT instead of exiting.
+var interruptHandler func()
+
+// setInterruptHandler makes the next SIGINT call fn instead of exiting the
+// CLI. Pass nil to restore the default behavior.
+func setInterruptHandler(fn func()) {
+	interruptMu.Lock()
+	defer interruptMu.Unlock()
+	interruptHandler = fn
+}
+
+// takeInterruptHandler returns the current interrupt handler (or nil) and
+// clears it, so each handler runs at most once.
+func takeInterruptHandler() func() {
+	interruptMu.Lock()
+	defer interruptMu.Unlock()
+	fn := interruptHandler
+	interruptHandler = nil
+	return fn
+}
+
 // =============================================================================
 // Main
 // =============================================================================
@@ -686,6 +869,19 @@ func main() {
 		return
 	}
 
+	// Decide once whether REPL output is colorized.
+	outputColors = newPalette(args.color, !args.atascii, stdoutIsTerminal())
+	outputPager.enabled = args.pager
+
+	// Dry-run mode never talks to a server: translate, print, and exit.
+	if args.dryRun {
+		editor := newREPLLineEditor(args)
+		defer editor.Close()
+		printWelcome(os.Stdout, args)
+		runREPL(nil, editor, replOptions{atascii: args.atascii, dryRun: true, echo: args.echo, screenshotDir: args.screenshotDir})
+		return
+	}
+
 	// Discover or launch server, then connect
 	client, launchedPid := discoverOrConnect(args)
 
@@ -714,18 +910,19 @@ func main() {
 		// goroutine). It prints async events to stdout as they arrive.
 		switch event.Type {
 		case atticprotocol.EventBreakpoint:
-			fmt.Printf("\n*** Breakpoint at $%04X  A=$%02X X=$%02X Y=$%02X S=$%02X P=$%02X\n",
-				event.Address, event.A, event.X, event.Y, event.S, event.P)
+			fmt.Printf("\n%s\n", outputColors.eventText(fmt.Sprintf(
+				"*** Breakpoint at $%04X  A=$%02X X=$%02X Y=$%02X S=$%02X P=$%02X",
+				event.Address, event.A, event.X, event.Y, event.S, event.P)))
 		case atticprotocol.EventStopped:
-			fmt.Printf("\n*** Stopped at $%04X\n", event.Address)
+			fmt.Printf("\n%s\n", outputColors.eventText(fmt.Sprintf("*** Stopped at $%04X", event.Address)))
 		case atticprotocol.EventError:
-			fmt.Printf("\n*** Error: %s\n", event.Message)
+			fmt.Printf("\n%s\n", outputColors.eventText("*** Error: "+event.Message))
 		}
 	})
 
 	// Set up disconnect handler
 	client.SetDisconnectHandler(func(err error) {
-		fmt.Fprintf(os.Stderr, "\nDisconnected from AtticServer: %v\n", err)
+		fmt.Fprintf(os.Stderr, "\n%s\n", outputColors.errorText(fmt.Sprintf("Disconnected from AtticServer: %v", err)))
 	})
 
 	// GO CONCEPT: Closures Capture by Reference
@@ -769,12 +966,14 @@ func main() {
 	// Create the LineEditor for REPL input.
 	// The LineEditor detects TTY vs piped input and selects the appropriate
 	// mode. In interactive mode, it provides Emacs keybindings and history.
-	editor := NewLineEditor()
+	editor := newREPLLineEditor(args)
 	defer editor.Close()
 
 	// Cleanup function for signal handling and normal exit
 	cleanup := func() {
 		editor.Close() // Save history and release readline resources
+		// Disconnect gives a command the REPL is still waiting on a short
+		// grace period, then fails it cleanly instead of racing with it.
 		client.Disconnect()
 		if launchedPid > 0 {
 			// We launched the server, so terminate it on exit.
@@ -792,14 +991,12 @@ func main() {
 	setupSignalHandler(cleanup)
 
 	// Print welcome banner
-	fmt.Print(welcomeBanner())
-	fmt.Println("Connected to AtticServer via CLI protocol")
-	fmt.Println()
+	printWelcome(os.Stdout, args)
 
 	// Run the REPL — this blocks until the user types .quit or Ctrl-D.
 	// The LineEditor provides line editing in interactive mode and simple
 	// line reading in non-interactive (piped/comint) mode.
-	runREPL(client, editor, args.atascii)
+	runREPL(client, editor, replOptions{atascii: args.atascii, autoPause: args.autoPause, echo: args.echo, quiet: args.quiet, screenshotDir: args.screenshotDir})
 
 	// Clean up on normal exit (REPL returned because user typed .quit)
 	cleanup()
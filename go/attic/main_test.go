@@ -41,6 +41,7 @@
 package main
 
 import (
+	"io"
 	"os"
 	"strings"
 	"testing"
@@ -232,6 +233,158 @@ func TestParseArgumentsSocket(t *testing.T) {
 	}
 }
 
+// TestParseArgumentsDryRun tests the --dry-run flag.
+func TestParseArgumentsDryRun(t *testing.T) {
+	oldArgs := os.Args
+	defer func() { os.Args = oldArgs }()
+
+	os.Args = []string{"attic-go", "--dry-run"}
+	args := parseArguments()
+
+	if !args.dryRun {
+		t.Error("--dry-run flag not recognized")
+	}
+}
+
+// TestParseArgumentsScreenshotDir tests the --screenshot-dir flag.
+func TestParseArgumentsScreenshotDir(t *testing.T) {
+	oldArgs := os.Args
+	defer func() { os.Args = oldArgs }()
+
+	os.Args = []string{"attic-go", "--screenshot-dir", "/tmp/shots"}
+	args := parseArguments()
+
+	if args.screenshotDir != "/tmp/shots" {
+		t.Errorf("screenshotDir = %q, want %q", args.screenshotDir, "/tmp/shots")
+	}
+}
+
+// TestParseArgumentsColor tests the --color flag.
+func TestParseArgumentsColor(t *testing.T) {
+	oldArgs := os.Args
+	defer func() { os.Args = oldArgs }()
+
+	os.Args = []string{"attic-go", "--color", "never"}
+	args := parseArguments()
+
+	if args.color != colorNever {
+		t.Errorf("color = %v, want colorNever", args.color)
+	}
+}
+
+// TestParseArgumentsAutoPause tests the --auto-pause flag and its default.
+func TestParseArgumentsAutoPause(t *testing.T) {
+	oldArgs := os.Args
+	defer func() { os.Args = oldArgs }()
+
+	os.Args = []string{"attic-go"}
+	if args := parseArguments(); args.autoPause != autoPauseOn {
+		t.Errorf("default autoPause = %v, want autoPauseOn", args.autoPause)
+	}
+
+	os.Args = []string{"attic-go", "--auto-pause", "resume"}
+	if args := parseArguments(); args.autoPause != autoPauseResume {
+		t.Errorf("autoPause = %v, want autoPauseResume", args.autoPause)
+	}
+}
+
+// TestParseArgumentsPager tests the --pager flag.
+func TestParseArgumentsPager(t *testing.T) {
+	oldArgs := os.Args
+	defer func() { os.Args = oldArgs }()
+
+	os.Args = []string{"attic-go", "--pager"}
+	args := parseArguments()
+
+	if !args.pager {
+		t.Error("--pager flag not recognized")
+	}
+}
+
+// TestParseArgumentsSaveHistory tests the --save-history flag.
+func TestParseArgumentsSaveHistory(t *testing.T) {
+	oldArgs := os.Args
+	defer func() { os.Args = oldArgs }()
+
+	os.Args = []string{"attic-go", "--save-history"}
+	args := parseArguments()
+
+	if !args.saveHistory {
+		t.Error("--save-history flag not recognized")
+	}
+}
+
+// TestParseArgumentsQuiet tests the --quiet flag.
+func TestParseArgumentsQuiet(t *testing.T) {
+	oldArgs := os.Args
+	defer func() { os.Args = oldArgs }()
+
+	os.Args = []string{"attic-go", "--quiet"}
+	args := parseArguments()
+
+	if !args.quiet {
+		t.Error("--quiet flag not recognized")
+	}
+	if args.silent {
+		t.Error("--quiet should not imply --silent")
+	}
+}
+
+// TestParseArgumentsEcho verifies the --echo flag.
+func TestParseArgumentsEcho(t *testing.T) {
+	oldArgs := os.Args
+	defer func() { os.Args = oldArgs }()
+
+	os.Args = []string{"attic-go", "--echo"}
+	if args := parseArguments(); !args.echo {
+		t.Error("--echo flag not recognized")
+	}
+
+	os.Args = []string{"attic-go"}
+	if args := parseArguments(); args.echo {
+		t.Error("echo should be off by default")
+	}
+}
+
+// TestPrintWelcomeQuiet verifies that --quiet suppresses the banner and the
+// connection line, which are printed otherwise.
+func TestPrintWelcomeQuiet(t *testing.T) {
+	tests := []struct {
+		name       string
+		args       arguments
+		wantBanner bool
+	}{
+		{"default", arguments{}, true},
+		{"dry run", arguments{dryRun: true}, true},
+		{"quiet", arguments{quiet: true}, false},
+		{"quiet dry run", arguments{quiet: true, dryRun: true}, false},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			oldStdout := os.Stdout
+			r, w, err := os.Pipe()
+			if err != nil {
+				t.Fatalf("failed to create stdout pipe: %v", err)
+			}
+			os.Stdout = w
+			printWelcome(os.Stdout, tc.args)
+			os.Stdout = oldStdout
+			w.Close()
+			data, _ := io.ReadAll(r)
+			r.Close()
+
+			out := string(data)
+			if got := strings.Contains(out, appName); got != tc.wantBanner {
+				t.Errorf("banner printed = %v, want %v (stdout: %q)", got, tc.wantBanner, out)
+			}
+			if !tc.wantBanner && out != "" {
+				t.Errorf("quiet output = %q, want nothing", out)
+			}
+		})
+	}
+}
+
 // TestParseArgumentsHelp tests help flags.
 func TestParseArgumentsHelp(t *testing.T) {
 	tests := []struct {
@@ -340,3 +493,14 @@ func TestCopyrightNotEmpty(t *testing.T) {
 		t.Error("copyright should not be empty")
 	}
 }
+
+// TestParseArgumentsNoHistory tests the --no-history flag.
+func TestParseArgumentsNoHistory(t *testing.T) {
+	oldArgs := os.Args
+	defer func() { os.Args = oldArgs }()
+
+	os.Args = []string{"attic-go", "--no-history"}
+	if args := parseArguments(); !args.noHistory {
+		t.Error("--no-history flag not recognized")
+	}
+}
@@ -0,0 +1,96 @@
+// =============================================================================
+// memint.go - Reading Memory as Integers (.u8, .u16, .i16)
+// =============================================================================
+//
+// Pointers and counters are easier to inspect as numbers than as hex bytes:
+//
+//	.u8 <addr>     unsigned byte
+//	.u16 <addr>    unsigned 16-bit word, little-endian
+//	.i16 <addr>    signed 16-bit word, little-endian
+//
+// Each prints the address, the raw value in hex and the decoded value in
+// decimal, e.g. "$0600  $FFFE = -2".
+//
+// =============================================================================
+
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/attic/atticprotocol"
+)
+
+// memoryIntType describes one of the integer dot-commands.
+type memoryIntType struct {
+	size   int  // Bytes read: 1 or 2
+	signed bool // Decode as two's complement
+}
+
+// memoryIntTypes maps each dot-command to the type it decodes.
+var memoryIntTypes = map[string]memoryIntType{
+	".u8":  {size: 1},
+	".u16": {size: 2},
+	".i16": {size: 2, signed: true},
+}
+
+// decodeMemoryInt decodes little-endian bytes as an integer of type typ. It
+// returns the raw unsigned value and the decoded value.
+func decodeMemoryInt(data []byte, typ memoryIntType) (raw uint16, value int) {
+	raw = uint16(data[0])
+	if typ.size == 2 {
+		raw |= uint16(data[1]) << 8
+	}
+	value = int(raw)
+	if typ.signed {
+		value = int(int16(raw))
+	}
+	return raw, value
+}
+
+// formatMemoryInt formats a decoded value as "$0600  $FFFE = -2".
+func formatMemoryInt(addr uint16, raw uint16, value int, typ memoryIntType) string {
+	hexDigits := typ.size * 2
+	return fmt.Sprintf("$%04X  $%0*X = %d", addr, hexDigits, raw, value)
+}
+
+// runMemoryIntCommand handles ".u8", ".u16" and ".i16"; name is the
+// dot-command and args its argument text.
+func runMemoryIntCommand(client *atticprotocol.Client, name, args string, symbols *symbolTable, opts replOptions) {
+	typ := memoryIntTypes[name]
+	fields := strings.Fields(args)
+	if len(fields) != 1 {
+		printError(fmt.Sprintf("usage: %s <addr>", name))
+		return
+	}
+	addr, ok := symbols.resolveAddress(fields[0])
+	if !ok {
+		printError(fmt.Sprintf("invalid address %q", fields[0]))
+		return
+	}
+
+	cmd := atticprotocol.NewReadCommand(addr, uint16(typ.size))
+	if opts.dryRun {
+		fmt.Println("CMD:" + cmd.Format())
+		return
+	}
+
+	resp, err := client.Send(cmd)
+	if err != nil {
+		printError(err.Error())
+		return
+	}
+	data, err := resp.AsBytes()
+	if err != nil {
+		printError(err.Error())
+		return
+	}
+	if len(data) < typ.size {
+		printError(fmt.Sprintf("expected %d bytes, got %d", typ.size, len(data)))
+		return
+	}
+
+	raw, value := decodeMemoryInt(data, typ)
+	fmt.Println(formatMemoryInt(addr, raw, value, typ))
+}
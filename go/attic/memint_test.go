@@ -0,0 +1,84 @@
+// =============================================================================
+// memint_test.go - Tests for Reading Memory as Integers (memint.go)
+// =============================================================================
+
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+// memIntHandler serves $FE,$FF at $0600 and $34,$12 at $0700.
+func memIntHandler(cmd string) string {
+	switch cmd {
+	case "ping":
+		return "OK:pong\n"
+	case "read $0600 1":
+		return "OK:data FE\n"
+	case "read $0600 2":
+		return "OK:data FE,FF\n"
+	case "read $0700 2":
+		return "OK:data 34,12\n"
+	}
+	return "ERR:unexpected " + cmd + "\n"
+}
+
+// TestDecodeMemoryInt verifies little-endian decoding and sign handling.
+func TestDecodeMemoryInt(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    []byte
+		typ     memoryIntType
+		wantRaw uint16
+		wantVal int
+	}{
+		{"u8", []byte{0xFE}, memoryIntTypes[".u8"], 0xFE, 254},
+		{"u16", []byte{0x34, 0x12}, memoryIntTypes[".u16"], 0x1234, 4660},
+		{"u16 high", []byte{0xFE, 0xFF}, memoryIntTypes[".u16"], 0xFFFE, 65534},
+		{"i16 positive", []byte{0x34, 0x12}, memoryIntTypes[".i16"], 0x1234, 4660},
+		{"i16 negative", []byte{0xFE, 0xFF}, memoryIntTypes[".i16"], 0xFFFE, -2},
+		{"i16 minimum", []byte{0x00, 0x80}, memoryIntTypes[".i16"], 0x8000, -32768},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			raw, value := decodeMemoryInt(tt.data, tt.typ)
+			if raw != tt.wantRaw || value != tt.wantVal {
+				t.Errorf("decodeMemoryInt(% X) = $%04X, %d; want $%04X, %d", tt.data, raw, value, tt.wantRaw, tt.wantVal)
+			}
+		})
+	}
+}
+
+// TestREPLMemoryInt verifies each dot-command end to end.
+func TestREPLMemoryInt(t *testing.T) {
+	tests := []struct {
+		input string
+		want  string
+	}{
+		{".u8 $0600", "$0600  $FE = 254"},
+		{".u16 $0600", "$0600  $FFFE = 65534"},
+		{".i16 $0600", "$0600  $FFFE = -2"},
+		{".U16 $0700", "$0700  $1234 = 4660"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			output := captureREPL(t, tt.input+"\n.quit\n", memIntHandler)
+			if !strings.Contains(output, tt.want) {
+				t.Errorf("expected %q in output, got:\n%s", tt.want, output)
+			}
+		})
+	}
+}
+
+// TestREPLMemoryIntUsage verifies that bad arguments send nothing.
+func TestREPLMemoryIntUsage(t *testing.T) {
+	for _, input := range []string{".u8\n", ".u16 $0600 $0700\n", ".i16 nowhere\n"} {
+		output := captureREPLWithOptions(t, input+".quit\n", nil, replOptions{dryRun: true})
+		if strings.Contains(output, "CMD:read") {
+			t.Errorf("%q: should not send a command, got:\n%s", strings.TrimSpace(input), output)
+		}
+	}
+}
@@ -0,0 +1,63 @@
+// =============================================================================
+// memmap.go - Showing the Memory Map (.memmap)
+// =============================================================================
+//
+// ".memmap" lists the ROM, RAM and I/O regions of the address space as the
+// server currently has them banked in:
+//
+//	.memmap
+//	$0000-$9FFF  RAM  40960  Main memory
+//	$A000-$BFFF  ROM   8192  BASIC
+//	$D000-$D0FF  IO     256  GTIA
+//
+// =============================================================================
+
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/attic/atticprotocol"
+)
+
+// formatMemoryMap renders regions as an aligned table with region sizes.
+func formatMemoryMap(regions []atticprotocol.MemoryRegion) string {
+	var sb strings.Builder
+	for _, region := range regions {
+		size := int(region.End) - int(region.Start) + 1
+		line := fmt.Sprintf("$%04X-$%04X  %-4s %6d  %s", region.Start, region.End, region.Kind, size, region.Name)
+		sb.WriteString(strings.TrimRight(line, " "))
+		sb.WriteString("\n")
+	}
+	return sb.String()
+}
+
+// runMemoryMapCommand handles ".memmap".
+func runMemoryMapCommand(client *atticprotocol.Client, opts replOptions) {
+	cmd := atticprotocol.NewMemoryMapCommand()
+	if opts.dryRun {
+		fmt.Println("CMD:" + cmd.Format())
+		return
+	}
+	if !client.IsConnected() {
+		printError("not connected (use .connect <socket>)")
+		return
+	}
+
+	resp, err := client.Send(cmd)
+	if err != nil {
+		printError(err.Error())
+		return
+	}
+	regions, err := resp.AsMemoryMap()
+	if err != nil {
+		printError(err.Error())
+		return
+	}
+	if len(regions) == 0 {
+		fmt.Println("(no regions)")
+		return
+	}
+	fmt.Print(formatMemoryMap(regions))
+}
@@ -0,0 +1,41 @@
+// =============================================================================
+// memmap_test.go - Tests for Showing the Memory Map (memmap.go)
+// =============================================================================
+
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+// TestREPLMemoryMap verifies that .memmap prints one aligned row per region.
+func TestREPLMemoryMap(t *testing.T) {
+	output := captureREPL(t, ".memmap\n.quit\n", func(cmd string) string {
+		switch cmd {
+		case "ping":
+			return "OK:pong\n"
+		case "memmap":
+			return "OK:$0000-$9FFF RAM Main memory\x1E$A000-$BFFF ROM BASIC\x1E$D000-$D0FF IO GTIA\n"
+		}
+		return "ERR:unexpected " + cmd + "\n"
+	})
+
+	for _, want := range []string{
+		"$0000-$9FFF  RAM   40960  Main memory\n",
+		"$A000-$BFFF  ROM    8192  BASIC\n",
+		"$D000-$D0FF  IO      256  GTIA\n",
+	} {
+		if !strings.Contains(output, want) {
+			t.Errorf("expected %q in output, got:\n%s", want, output)
+		}
+	}
+}
+
+// TestREPLMemoryMapDryRun verifies the protocol command in dry-run mode.
+func TestREPLMemoryMapDryRun(t *testing.T) {
+	output := captureREPLWithOptions(t, ".memmap\n.quit\n", nil, replOptions{dryRun: true})
+	if !strings.Contains(output, "CMD:memmap") {
+		t.Errorf("expected CMD:memmap, got:\n%s", output)
+	}
+}
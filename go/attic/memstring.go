@@ -0,0 +1,118 @@
+// =============================================================================
+// memstring.go - Reading Text from Emulator Memory (.strings)
+// =============================================================================
+//
+// ".strings" reads memory and shows it as text, which is easier to scan
+// than a hex dump when reverse-engineering messages and tables:
+//
+//	.strings <addr> [maxlen] [--prefixed]
+//
+// The string ends at the first zero byte or ATASCII EOL ($9B), or after
+// maxlen bytes (default 64). With --prefixed the first byte is the length
+// instead, as in Pascal-style strings, and no terminator is looked for.
+//
+// =============================================================================
+
+package main
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/attic/atticprotocol"
+)
+
+// defaultStringMaxLen is the read size when .strings has no maxlen.
+const defaultStringMaxLen = 64
+
+// terminateString cuts data at the first zero byte or ATASCII EOL.
+func terminateString(data []byte) []byte {
+	for i, b := range data {
+		if b == 0 || b == atticprotocol.ATASCIIEOL {
+			return data[:i]
+		}
+	}
+	return data
+}
+
+// decodeMemoryText renders ATASCII bytes for display. With atascii,
+// graphics characters become Unicode glyphs and inverse video uses ANSI
+// reverse video. Otherwise bit 7 is dropped and characters with no
+// printable ASCII equivalent are shown as ".".
+func decodeMemoryText(data []byte, atascii bool) string {
+	if atascii {
+		return atticprotocol.DecodeATASCII(data)
+	}
+	var sb strings.Builder
+	for _, b := range data {
+		c := b & 0x7F
+		if c < 0x20 || c == 0x7F {
+			c = '.'
+		}
+		sb.WriteByte(c)
+	}
+	return sb.String()
+}
+
+// runStringsCommand handles ".strings <addr> [maxlen] [--prefixed]".
+func runStringsCommand(client *atticprotocol.Client, args string, symbols *symbolTable, opts replOptions) {
+	fields := strings.Fields(args)
+	prefixed := false
+	if len(fields) > 0 && fields[len(fields)-1] == "--prefixed" {
+		prefixed = true
+		fields = fields[:len(fields)-1]
+	}
+	if len(fields) < 1 || len(fields) > 2 {
+		printError("usage: .strings <addr> [maxlen] [--prefixed]")
+		return
+	}
+
+	addr, ok := symbols.resolveAddress(fields[0])
+	if !ok {
+		printError(fmt.Sprintf("invalid address %q", fields[0]))
+		return
+	}
+	maxLen := defaultStringMaxLen
+	if len(fields) == 2 {
+		n, err := strconv.Atoi(fields[1])
+		if err != nil || n <= 0 || n > 0xFFFF {
+			printError(fmt.Sprintf("invalid length %q", fields[1]))
+			return
+		}
+		maxLen = n
+	}
+
+	// A length-prefixed string is at most 255 bytes after its length byte.
+	count := maxLen
+	if prefixed {
+		count = min(maxLen, 255) + 1
+	}
+	cmd := atticprotocol.NewReadCommand(addr, uint16(count))
+	if opts.dryRun {
+		fmt.Println("CMD:" + cmd.Format())
+		return
+	}
+
+	resp, err := client.Send(cmd)
+	if err != nil {
+		printError(err.Error())
+		return
+	}
+	data, err := resp.AsBytes()
+	if err != nil {
+		printError(err.Error())
+		return
+	}
+
+	if prefixed {
+		if len(data) == 0 {
+			printError("no data")
+			return
+		}
+		data = data[1:][:min(int(data[0]), len(data)-1, maxLen)]
+	} else {
+		data = terminateString(data)
+	}
+	fmt.Printf("$%04X  \"%s\" (%d bytes)\n", addr, decodeMemoryText(data, opts.atascii), len(data))
+}
@@ -0,0 +1,77 @@
+// =============================================================================
+// memstring_test.go - Tests for Reading Text from Memory (memstring.go)
+// =============================================================================
+
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+// stringsHandler serves "HELLO", a zero terminator and trailing garbage at
+// $0600, and a length-prefixed "ATARI" at $0700.
+func stringsHandler(cmd string) string {
+	switch cmd {
+	case "ping":
+		return "OK:pong\n"
+	case "read $0600 64", "read $0600 8":
+		return "OK:data 48,45,4C,4C,4F,00,FF,FF\n"
+	case "read $0700 65":
+		return "OK:data 05,41,54,41,52,49,58,58\n"
+	case "read $0800 64":
+		return "OK:data 47,C1,CD,C5,9B,00\n"
+	}
+	return "ERR:unexpected " + cmd + "\n"
+}
+
+// TestREPLStringsZeroTerminated verifies that the string stops at the
+// zero byte.
+func TestREPLStringsZeroTerminated(t *testing.T) {
+	output := captureREPL(t, ".strings $0600\n.quit\n", stringsHandler)
+	if !strings.Contains(output, `$0600  "HELLO" (5 bytes)`) {
+		t.Errorf("expected decoded string, got:\n%s", output)
+	}
+}
+
+// TestREPLStringsMaxLen verifies that maxlen is used as the read size.
+func TestREPLStringsMaxLen(t *testing.T) {
+	output := captureREPL(t, ".strings $0600 8\n.quit\n", stringsHandler)
+	if !strings.Contains(output, `"HELLO"`) {
+		t.Errorf("expected decoded string, got:\n%s", output)
+	}
+}
+
+// TestREPLStringsPrefixed verifies that --prefixed takes the length from
+// the first byte.
+func TestREPLStringsPrefixed(t *testing.T) {
+	output := captureREPL(t, ".strings $0700 --prefixed\n.quit\n", stringsHandler)
+	if !strings.Contains(output, `$0700  "ATARI" (5 bytes)`) {
+		t.Errorf("expected decoded string, got:\n%s", output)
+	}
+}
+
+// TestREPLStringsInverse verifies that inverse video is shown with ANSI
+// reverse video in ATASCII mode and that EOL ends the string.
+func TestREPLStringsInverse(t *testing.T) {
+	output := captureREPLWithOptions(t, ".strings $0800\n.quit\n", stringsHandler, replOptions{atascii: true})
+	want := "\"G\x1b[7mA\x1b[27m\x1b[7mM\x1b[27m\x1b[7mE\x1b[27m\" (4 bytes)"
+	if !strings.Contains(output, want) {
+		t.Errorf("expected %q in output, got:\n%q", want, output)
+	}
+
+	output = captureREPL(t, ".strings $0800\n.quit\n", stringsHandler)
+	if !strings.Contains(output, `"GAME" (4 bytes)`) {
+		t.Errorf("expected plain text without ATASCII mode, got:\n%s", output)
+	}
+}
+
+// TestREPLStringsUsage verifies that bad arguments send nothing.
+func TestREPLStringsUsage(t *testing.T) {
+	for _, input := range []string{".strings\n", ".strings $0600 0\n", ".strings $0600 8 9\n"} {
+		output := captureREPLWithOptions(t, input+".quit\n", nil, replOptions{dryRun: true})
+		if strings.Contains(output, "CMD:read") {
+			t.Errorf("%q: should not send a command, got:\n%s", strings.TrimSpace(input), output)
+		}
+	}
+}
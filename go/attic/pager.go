@@ -0,0 +1,159 @@
+// =============================================================================
+// pager.go - Paged Output for Long Responses
+// =============================================================================
+//
+// Long responses (a big disassembly, a full directory listing) can scroll
+// past faster than they can be read. When paging is enabled with --pager
+// or ".page on", responses taller than the terminal are shown one screen
+// at a time:
+//
+//   - If $PAGER is set (e.g. "less -R"), the text is piped to it.
+//   - Otherwise a small built-in pager prints a screenful and waits for
+//     a key: space or Enter shows the next page, q stops.
+//
+// Paging only engages when stdout is a terminal, so piped and comint
+// output is never held back.
+//
+// =============================================================================
+
+package main
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+
+	"golang.org/x/term"
+)
+
+// pagerSettings holds the current paging state.
+type pagerSettings struct {
+	// enabled is toggled by --pager and ".page on|off".
+	enabled bool
+}
+
+// outputPager is the pager state used by the REPL print path.
+var outputPager pagerSettings
+
+// shouldPage decides whether output with lineCount lines should be paged.
+// Paging requires it to be enabled, stdout to be a terminal, and the output
+// to not fit on screen alongside the next prompt.
+func shouldPage(enabled, stdoutIsTTY bool, lineCount, termHeight int) bool {
+	if !enabled || !stdoutIsTTY || termHeight <= 0 {
+		return false
+	}
+	return lineCount >= termHeight
+}
+
+// terminalHeight returns the height of the terminal attached to stdout, or
+// 0 if it cannot be determined.
+func terminalHeight() int {
+	_, height, err := term.GetSize(int(os.Stdout.Fd()))
+	if err != nil {
+		return 0
+	}
+	return height
+}
+
+// printPaged prints text, paging it if the current settings call for it.
+func printPaged(text string) {
+	lines := strings.Split(text, "\n")
+	height := terminalHeight()
+	if !shouldPage(outputPager.enabled, stdoutIsTerminal(), len(lines), height) {
+		fmt.Println(text)
+		return
+	}
+
+	if pager := os.Getenv("PAGER"); pager != "" {
+		if err := runExternalPager(pager, text); err == nil {
+			return
+		}
+		// Fall back to the built-in pager if $PAGER can't be run.
+	}
+	runInternalPager(lines, height)
+}
+
+// GO CONCEPT: Running External Programs (os/exec)
+// ------------------------------------------------
+// exec.Command builds a command; setting Stdin/Stdout/Stderr connects it
+// to our streams. Run() starts it and waits for it to finish. Running the
+// pager through "sh -c" lets $PAGER contain arguments like "less -R".
+//
+// Compare with Swift: Foundation's Process class with standardInput and
+// standardOutput pipes does the same job.
+//
+// Compare with Python: `subprocess.run(pager, shell=True, input=text,
+// text=True)` is the direct equivalent.
+
+// runExternalPager pipes text through the given pager command line.
+func runExternalPager(pager, text string) error {
+	cmd := exec.Command("sh", "-c", pager)
+	cmd.Stdin = strings.NewReader(text + "\n")
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	return cmd.Run()
+}
+
+// runInternalPager shows lines one screen at a time, leaving the last
+// terminal row for the "-- More --" prompt.
+func runInternalPager(lines []string, height int) {
+	pageSize := height - 1
+	for start := 0; start < len(lines); start += pageSize {
+		end := start + pageSize
+		if end > len(lines) {
+			end = len(lines)
+		}
+		for _, line := range lines[start:end] {
+			fmt.Println(line)
+		}
+		if end == len(lines) {
+			return
+		}
+
+		fmt.Print("-- More -- (space: next page, q: quit)")
+		key := readKey()
+		fmt.Print("\r\033[K") // Erase the "-- More --" prompt
+		if key == 'q' || key == 'Q' {
+			return
+		}
+	}
+}
+
+// readKey reads a single key press from the terminal without waiting for
+// Enter. If stdin can't be put into raw mode, it behaves as if space was
+// pressed.
+func readKey() byte {
+	fd := int(os.Stdin.Fd())
+	state, err := term.MakeRaw(fd)
+	if err != nil {
+		return ' '
+	}
+	defer term.Restore(fd, state)
+
+	buf := make([]byte, 1)
+	if _, err := os.Stdin.Read(buf); err != nil {
+		return 'q'
+	}
+	return buf[0]
+}
+
+// handlePageCommand processes the arguments of a ".page" dot-command.
+func handlePageCommand(args string) {
+	switch strings.ToLower(strings.TrimSpace(args)) {
+	case "":
+		if outputPager.enabled {
+			fmt.Println("Paging is on")
+		} else {
+			fmt.Println("Paging is off")
+		}
+	case "on":
+		outputPager.enabled = true
+		fmt.Println("Paging enabled")
+	case "off":
+		outputPager.enabled = false
+		fmt.Println("Paging disabled")
+	default:
+		printError("usage: .page [on|off]")
+	}
+}
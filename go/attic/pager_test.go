@@ -0,0 +1,51 @@
+// =============================================================================
+// pager_test.go - Tests for Paged Output (pager.go)
+// =============================================================================
+
+package main
+
+import "testing"
+
+// TestShouldPage verifies the paging decision independent of any pager.
+func TestShouldPage(t *testing.T) {
+	tests := []struct {
+		name      string
+		enabled   bool
+		isTTY     bool
+		lineCount int
+		height    int
+		want      bool
+	}{
+		{"fits on screen", true, true, 10, 24, false},
+		{"one short of height", true, true, 23, 24, false},
+		{"exactly height", true, true, 24, 24, true},
+		{"taller than screen", true, true, 200, 24, true},
+		{"disabled", false, true, 200, 24, false},
+		{"piped output", true, false, 200, 24, false},
+		{"unknown height", true, true, 200, 0, false},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			got := shouldPage(tc.enabled, tc.isTTY, tc.lineCount, tc.height)
+			if got != tc.want {
+				t.Errorf("shouldPage(%v, %v, %d, %d) = %v, want %v",
+					tc.enabled, tc.isTTY, tc.lineCount, tc.height, got, tc.want)
+			}
+		})
+	}
+}
+
+// TestHandlePageCommand verifies the .page toggle.
+func TestHandlePageCommand(t *testing.T) {
+	defer func() { outputPager = pagerSettings{} }()
+
+	handlePageCommand(" on")
+	if !outputPager.enabled {
+		t.Error(".page on should enable paging")
+	}
+	handlePageCommand(" OFF")
+	if outputPager.enabled {
+		t.Error(".page off should disable paging")
+	}
+}
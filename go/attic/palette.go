@@ -0,0 +1,147 @@
+// =============================================================================
+// palette.go - Showing and Changing the Color Registers (.palette)
+// =============================================================================
+//
+// ".palette" shows the playfield and background colors with approximate
+// names, and ".palette set" changes one, for tweaking a program's look
+// while it runs:
+//
+//	.palette
+//	COLOR0  COLPF0  $28  orange
+//	COLOR1  COLPF1  $CA  light green
+//	COLOR2  COLPF2  $94  sky blue
+//	COLOR3  COLPF3  $46  pink
+//	COLOR4  COLBK   $00  black
+//
+//	.palette set colbk $94
+//
+// The OS copies its shadow registers to the GTIA every vertical blank, so
+// both read and write the shadows at $02C4-$02C8; a write to the hardware
+// register would be overwritten within a frame. Registers can be named by
+// shadow (COLOR0-4) or hardware (COLPF0-3, COLBK) name.
+//
+// =============================================================================
+
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/attic/atticprotocol"
+)
+
+// paletteCOLOR0 is the shadow of COLPF0; COLOR1-4 follow it.
+const paletteCOLOR0 = 0x02C4
+
+// paletteRegisters names the color shadows in address order.
+var paletteRegisters = []struct {
+	shadow, hardware string
+}{
+	{"COLOR0", "COLPF0"},
+	{"COLOR1", "COLPF1"},
+	{"COLOR2", "COLPF2"},
+	{"COLOR3", "COLPF3"},
+	{"COLOR4", "COLBK"},
+}
+
+// hueNames approximates the 16 NTSC hues, indexed by the high nibble.
+var hueNames = [16]string{
+	"gray", "gold", "orange", "red-orange", "pink", "purple", "violet", "indigo",
+	"blue", "sky blue", "turquoise", "sea green", "green", "yellow-green", "olive", "tan",
+}
+
+// colorName returns an approximate name for an Atari color value, e.g.
+// "light green" for $CA. Bit 0 is ignored, as the GTIA does.
+func colorName(c byte) string {
+	hue, lum := c>>4, c&0x0E
+	if hue == 0 {
+		switch {
+		case lum == 0:
+			return "black"
+		case lum == 0x0E:
+			return "white"
+		}
+	}
+	switch {
+	case lum <= 2:
+		return "dark " + hueNames[hue]
+	case lum >= 0x0A:
+		return "light " + hueNames[hue]
+	default:
+		return hueNames[hue]
+	}
+}
+
+// paletteRegisterAddr returns the shadow address for a register name.
+func paletteRegisterAddr(name string) (uint16, bool) {
+	for i, r := range paletteRegisters {
+		if strings.EqualFold(name, r.shadow) || strings.EqualFold(name, r.hardware) {
+			return paletteCOLOR0 + uint16(i), true
+		}
+	}
+	return 0, false
+}
+
+// formatPalette renders the color registers, one per line.
+func formatPalette(colors []byte) string {
+	var sb strings.Builder
+	for i, r := range paletteRegisters {
+		fmt.Fprintf(&sb, "%-7s %-7s $%02X  %s\n", r.shadow, r.hardware, colors[i], colorName(colors[i]))
+	}
+	return sb.String()
+}
+
+// runPaletteCommand handles ".palette [set <reg> <value>]".
+func runPaletteCommand(client *atticprotocol.Client, args string, opts replOptions) {
+	fields := strings.Fields(args)
+	var cmd atticprotocol.Command
+	switch {
+	case len(fields) == 0:
+		cmd = atticprotocol.NewReadCommand(paletteCOLOR0, uint16(len(paletteRegisters)))
+	case len(fields) == 3 && strings.EqualFold(fields[0], "set"):
+		addr, ok := paletteRegisterAddr(fields[1])
+		if !ok {
+			printError(fmt.Sprintf("unknown color register %q (use COLPF0-3, COLBK or COLOR0-4)", fields[1]))
+			return
+		}
+		value, ok := parseAddressArg(fields[2])
+		if !ok || value > 0xFF {
+			printError(fmt.Sprintf("invalid color %q", fields[2]))
+			return
+		}
+		cmd = atticprotocol.NewWriteCommand(addr, []byte{byte(value)})
+	default:
+		printError("usage: .palette [set <reg> <value>]")
+		return
+	}
+
+	if opts.dryRun {
+		fmt.Println("CMD:" + cmd.Format())
+		return
+	}
+	if !client.IsConnected() {
+		printError("not connected (use .connect <socket>)")
+		return
+	}
+
+	if cmd.Type == atticprotocol.CmdWrite {
+		resp, err := client.Send(cmd)
+		if err == nil {
+			err = resp.Err()
+		}
+		if err != nil {
+			printError(err.Error())
+			return
+		}
+		value := cmd.Data[0]
+		fmt.Printf("%s = $%02X  %s\n", paletteRegisters[cmd.Address-paletteCOLOR0].shadow, value, colorName(value))
+		return
+	}
+	colors, err := readMemory(client, cmd.Address, cmd.Count)
+	if err != nil {
+		printError(err.Error())
+		return
+	}
+	fmt.Print(formatPalette(colors))
+}
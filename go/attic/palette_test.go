@@ -0,0 +1,73 @@
+// =============================================================================
+// palette_test.go - Tests for the Color Registers (palette.go)
+// =============================================================================
+
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+// TestColorName verifies names for known color values.
+func TestColorName(t *testing.T) {
+	tests := []struct {
+		c    byte
+		want string
+	}{
+		{0x00, "black"},
+		{0x0E, "white"},
+		{0x0F, "white"},
+		{0x06, "gray"},
+		{0x02, "dark gray"},
+		{0x28, "orange"},
+		{0xCA, "light green"},
+		{0x94, "sky blue"},
+		{0x82, "dark blue"},
+	}
+	for _, tt := range tests {
+		if got := colorName(tt.c); got != tt.want {
+			t.Errorf("colorName($%02X) = %q, want %q", tt.c, got, tt.want)
+		}
+	}
+}
+
+// TestREPLPalette verifies that .palette decodes the color shadows.
+func TestREPLPalette(t *testing.T) {
+	output := captureREPL(t, ".palette\n.quit\n", func(cmd string) string {
+		switch cmd {
+		case "ping":
+			return "OK:pong\n"
+		case "read $02C4 5":
+			return "OK:data 28,CA,94,46,00\n"
+		}
+		return "ERR:unexpected " + cmd + "\n"
+	})
+	for _, want := range []string{
+		"COLOR0  COLPF0  $28  orange\n",
+		"COLOR1  COLPF1  $CA  light green\n",
+		"COLOR4  COLBK   $00  black\n",
+	} {
+		if !strings.Contains(output, want) {
+			t.Errorf("expected %q in output, got:\n%s", want, output)
+		}
+	}
+}
+
+// TestREPLPaletteSet verifies the write for either register name, and that
+// bad arguments send nothing.
+func TestREPLPaletteSet(t *testing.T) {
+	output := captureREPLWithOptions(t, ".palette set colbk $94\n.palette set COLOR1 202\n.quit\n", nil, replOptions{dryRun: true})
+	for _, want := range []string{"CMD:write $02C8 94\n", "CMD:write $02C5 CA\n"} {
+		if !strings.Contains(output, want) {
+			t.Errorf("expected %q in output, got:\n%s", want, output)
+		}
+	}
+
+	for _, input := range []string{".palette set colpm0 $10\n", ".palette set colbk $100\n", ".palette set colbk\n", ".palette dump\n"} {
+		output := captureREPLWithOptions(t, input+".quit\n", nil, replOptions{dryRun: true})
+		if strings.Contains(output, "CMD:") {
+			t.Errorf("%q: should send nothing, got:\n%s", strings.TrimSpace(input), output)
+		}
+	}
+}
@@ -0,0 +1,153 @@
+// =============================================================================
+// paths.go - Tilde Expansion for Host Path Arguments
+// =============================================================================
+//
+// The server receives host file paths literally, so "boot ~/game.atr" would
+// look for a directory named "~". Shells normally expand the tilde, but the
+// REPL is not a shell, so the CLI does it before a command is sent:
+//
+//	~             → /Users/me
+//	~/game.atr    → /Users/me/game.atr
+//	~bob/disk.atr → /Users/bob/disk.atr
+//
+// Expansion happens here, on the CLI side, so the protocol package and the
+// wire format stay literal. The expanded path is spliced in as is: the
+// server takes the rest of the line as the path, so spaces in the home
+// directory need no quoting. "dos import" is the exception, since its host
+// path ends at the first space; a home directory with spaces is an error
+// there.
+//
+// =============================================================================
+
+package main
+
+import (
+	"fmt"
+	"os"
+	"os/user"
+	"path/filepath"
+	"strings"
+	"unicode"
+)
+
+// expandPath replaces a leading "~" or "~user" with the corresponding home
+// directory. Paths without a leading tilde, or whose user can't be found,
+// are returned unchanged.
+func expandPath(path string) string {
+	if !strings.HasPrefix(path, "~") {
+		return path
+	}
+
+	name, rest := path[1:], ""
+	if slash := strings.IndexByte(name, '/'); slash >= 0 {
+		name, rest = name[:slash], name[slash+1:]
+	}
+
+	var home string
+	if name == "" {
+		dir, err := os.UserHomeDir()
+		if err != nil {
+			return path
+		}
+		home = dir
+	} else {
+		u, err := user.Lookup(name)
+		if err != nil {
+			return path
+		}
+		home = u.HomeDir
+	}
+
+	if rest == "" {
+		return home
+	}
+	return filepath.Join(home, rest)
+}
+
+// pathArgIndexes returns the positions (within strings.Fields of a protocol
+// command line) of the arguments that are host file paths.
+func pathArgIndexes(fields []string) []int {
+	if len(fields) == 0 {
+		return nil
+	}
+	sub := ""
+	if len(fields) > 1 {
+		sub = strings.ToLower(fields[1])
+	}
+
+	switch strings.ToLower(fields[0]) {
+	case "boot", "screenshot":
+		return []int{1} // boot <path>
+	case "mount":
+		return []int{2} // mount <drive> <path>
+	case "state":
+		if sub == "save" || sub == "load" {
+			return []int{2} // state save|load <path>
+		}
+	case "basic":
+		if sub == "export" || sub == "import" {
+			return []int{2} // basic export|import <path>
+		}
+	case "dos":
+		switch sub {
+		case "export":
+			return []int{3} // dos export <file> <path>
+		case "import":
+			return []int{2} // dos import <path> <file>
+		}
+	}
+	return nil
+}
+
+// expandCommandPaths expands a leading tilde in the host path arguments of
+// a protocol command line (boot, mount, state, screenshot, basic and dos
+// import/export). Only the path itself is replaced, so the rest of the line,
+// spacing included, reaches the server as typed. It fails if a dos import
+// host path expands to one containing whitespace, which the server would
+// cut short. Other commands are returned unchanged.
+func expandCommandPaths(line string) (string, error) {
+	spans := fieldSpans(line)
+	fields := make([]string, len(spans))
+	for i, span := range spans {
+		fields[i] = line[span[0]:span[1]]
+	}
+
+	// Replace from the end so earlier offsets stay valid.
+	indexes := pathArgIndexes(fields)
+	for j := len(indexes) - 1; j >= 0; j-- {
+		i := indexes[j]
+		if i >= len(fields) || !strings.HasPrefix(fields[i], "~") {
+			continue
+		}
+		expanded := expandPath(fields[i])
+		if expanded == fields[i] {
+			continue
+		}
+		if strings.EqualFold(fields[0], "dos") && strings.EqualFold(fields[1], "import") && strings.ContainsAny(expanded, " \t") {
+			return "", fmt.Errorf("dos import can't use a host path with spaces: %s", expanded)
+		}
+		line = line[:spans[i][0]] + expanded + line[spans[i][1]:]
+	}
+	return line, nil
+}
+
+// fieldSpans returns the start and end offsets of the fields of line, as
+// split by strings.Fields.
+func fieldSpans(line string) [][2]int {
+	var spans [][2]int
+	start := -1
+	for i, r := range line {
+		if unicode.IsSpace(r) {
+			if start >= 0 {
+				spans = append(spans, [2]int{start, i})
+				start = -1
+			}
+		} else if start < 0 {
+			start = i
+		}
+	}
+	if start >= 0 {
+		spans = append(spans, [2]int{start, len(line)})
+	}
+	return spans
+}
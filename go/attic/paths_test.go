@@ -0,0 +1,99 @@
+// =============================================================================
+// paths_test.go - Tests for Tilde Expansion (paths.go)
+// =============================================================================
+
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// TestExpandPath verifies tilde expansion for the common forms.
+func TestExpandPath(t *testing.T) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		t.Skip("no home directory available")
+	}
+
+	tests := []struct {
+		input string
+		want  string
+	}{
+		{"~/x", filepath.Join(home, "x")},
+		{"~/disks/game.atr", filepath.Join(home, "disks", "game.atr")},
+		{"~", home},
+		{"/tmp/game.atr", "/tmp/game.atr"},
+		{"game~1.atr", "game~1.atr"},
+		{"", ""},
+		{"~no-such-user-attic/x", "~no-such-user-attic/x"},
+	}
+
+	for _, tc := range tests {
+		if got := expandPath(tc.input); got != tc.want {
+			t.Errorf("expandPath(%q) = %q, want %q", tc.input, got, tc.want)
+		}
+	}
+}
+
+// TestExpandCommandPaths verifies that only host path arguments of
+// path-bearing commands are expanded.
+func TestExpandCommandPaths(t *testing.T) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		t.Skip("no home directory available")
+	}
+
+	tests := []struct {
+		input string
+		want  string
+	}{
+		{"boot ~/game.atr", "boot " + filepath.Join(home, "game.atr")},
+		{"mount 1 ~/d.atr", "mount 1 " + filepath.Join(home, "d.atr")},
+		{"state save ~/s.attic", "state save " + filepath.Join(home, "s.attic")},
+		{"screenshot ~/shot.png", "screenshot " + filepath.Join(home, "shot.png")},
+		{"basic export ~/p.bas", "basic export " + filepath.Join(home, "p.bas")},
+		{"dos export GAME.BAS ~/g.bas", "dos export GAME.BAS " + filepath.Join(home, "g.bas")},
+		{"dos import ~/g.bas GAME.BAS", "dos import " + filepath.Join(home, "g.bas") + " GAME.BAS"},
+		{"dos type ~FILE", "dos type ~FILE"},
+		{"inject keys ~/x", "inject keys ~/x"},
+		{"boot /tmp/game.atr", "boot /tmp/game.atr"},
+		{"dos  export   GAME.BAS  ~/g.bas", "dos  export   GAME.BAS  " + filepath.Join(home, "g.bas")},
+		{"basic 10 PRINT \"A  B\"", "basic 10 PRINT \"A  B\""},
+		{"inject keys A\\s\\sB  ~/x", "inject keys A\\s\\sB  ~/x"},
+	}
+
+	for _, tc := range tests {
+		got, err := expandCommandPaths(tc.input)
+		if err != nil || got != tc.want {
+			t.Errorf("expandCommandPaths(%q) = %q, %v; want %q", tc.input, got, err, tc.want)
+		}
+	}
+}
+
+// TestExpandCommandPathsSpaces verifies that a home directory with spaces
+// is spliced in unquoted, since the server takes the rest of the line as
+// the path, and that dos import reports it instead.
+func TestExpandCommandPathsSpaces(t *testing.T) {
+	t.Setenv("HOME", "/Users/Jane Doe")
+
+	tests := []struct {
+		input string
+		want  string
+	}{
+		{"mount  1 ~/d.atr", "mount  1 /Users/Jane Doe/d.atr"},
+		{"state save ~/s.attic", "state save /Users/Jane Doe/s.attic"},
+		{"dos export GAME.BAS ~/g.bas", "dos export GAME.BAS /Users/Jane Doe/g.bas"},
+	}
+	for _, tc := range tests {
+		got, err := expandCommandPaths(tc.input)
+		if err != nil || got != tc.want {
+			t.Errorf("expandCommandPaths(%q) = %q, %v; want %q", tc.input, got, err, tc.want)
+		}
+	}
+
+	if got, err := expandCommandPaths("dos  import ~/g.bas   GAME.BAS"); err == nil {
+		t.Errorf("expandCommandPaths(dos import) = %q, want an error", got)
+	}
+}
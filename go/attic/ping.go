@@ -0,0 +1,91 @@
+// =============================================================================
+// ping.go - Measuring Round-Trip Latency (.ping)
+// =============================================================================
+//
+// ".ping" sends a number of pings (10 by default) and summarizes how long
+// the server took to answer, to tell a sluggish connection or an
+// overloaded server from a slow command:
+//
+//	.ping 20
+//	20 pings: min 41µs, avg 63µs, max 210µs
+//
+// =============================================================================
+
+package main
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/attic/atticprotocol"
+)
+
+// defaultPingCount is the number of pings .ping sends without an argument.
+const defaultPingCount = 10
+
+// pingStats summarizes a series of round-trip times.
+type pingStats struct {
+	count         int
+	min, avg, max time.Duration
+}
+
+// summarizePings computes the statistics for a non-empty series of
+// round-trip times.
+func summarizePings(times []time.Duration) pingStats {
+	s := pingStats{count: len(times), min: times[0], max: times[0]}
+	var total time.Duration
+	for _, d := range times {
+		total += d
+		s.min = min(s.min, d)
+		s.max = max(s.max, d)
+	}
+	s.avg = total / time.Duration(len(times))
+	return s
+}
+
+// String renders the summary line .ping prints.
+func (s pingStats) String() string {
+	return fmt.Sprintf("%d pings: min %v, avg %v, max %v", s.count,
+		s.min.Round(time.Microsecond), s.avg.Round(time.Microsecond), s.max.Round(time.Microsecond))
+}
+
+// runPingCommand handles ".ping [count]".
+func runPingCommand(client *atticprotocol.Client, args string, opts replOptions) {
+	count := defaultPingCount
+	if arg := strings.TrimSpace(args); arg != "" {
+		n, err := strconv.Atoi(arg)
+		if err != nil || n < 1 {
+			printError("usage: .ping [count]")
+			return
+		}
+		count = n
+	}
+
+	cmd := atticprotocol.NewPingCommand()
+	if opts.dryRun {
+		for i := 0; i < count; i++ {
+			fmt.Println("CMD:" + cmd.Format())
+		}
+		return
+	}
+	if !client.IsConnected() {
+		printError("not connected (use .connect <socket>)")
+		return
+	}
+
+	times := make([]time.Duration, 0, count)
+	for i := 0; i < count; i++ {
+		resp, elapsed, err := client.SendTimed(cmd)
+		if err == nil {
+			err = resp.Err()
+		}
+		if err != nil {
+			printError(fmt.Sprintf("ping %d: %v", i+1, err))
+			return
+		}
+		times = append(times, elapsed)
+	}
+	fmt.Println(summarizePings(times))
+}
@@ -0,0 +1,61 @@
+// =============================================================================
+// ping_test.go - Tests for Measuring Round-Trip Latency (ping.go)
+// =============================================================================
+
+package main
+
+import (
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+)
+
+// TestSummarizePings verifies min, average and max.
+func TestSummarizePings(t *testing.T) {
+	got := summarizePings([]time.Duration{3 * time.Millisecond, time.Millisecond, 2 * time.Millisecond})
+	want := pingStats{count: 3, min: time.Millisecond, avg: 2 * time.Millisecond, max: 3 * time.Millisecond}
+	if got != want {
+		t.Errorf("summarizePings() = %+v, want %+v", got, want)
+	}
+	if s := got.String(); s != "3 pings: min 1ms, avg 2ms, max 3ms" {
+		t.Errorf("String() = %q", s)
+	}
+}
+
+// TestREPLPing verifies that .ping sends the requested number of pings and
+// reports nonzero times.
+func TestREPLPing(t *testing.T) {
+	var pings int
+	output := captureREPL(t, ".ping 5\n.quit\n", func(cmd string) string {
+		if cmd == "ping" {
+			pings++
+			return "OK:pong\n"
+		}
+		return "ERR:unexpected " + cmd + "\n"
+	})
+
+	// One ping is the connection check.
+	if pings != 6 {
+		t.Errorf("server saw %d pings, want 6", pings)
+	}
+	m := regexp.MustCompile(`5 pings: min (\S+), avg (\S+), max (\S+)`).FindStringSubmatch(output)
+	if m == nil {
+		t.Fatalf("expected a summary line, got:\n%s", output)
+	}
+	for _, d := range m[1:] {
+		if d == "0s" {
+			t.Errorf("expected nonzero durations, got %q", m[0])
+		}
+	}
+}
+
+// TestREPLPingUsage verifies that a bad count sends nothing.
+func TestREPLPingUsage(t *testing.T) {
+	for _, input := range []string{".ping 0\n", ".ping x\n"} {
+		output := captureREPLWithOptions(t, input+".quit\n", nil, replOptions{dryRun: true})
+		if strings.Contains(output, "CMD:") {
+			t.Errorf("%q: should send nothing, got:\n%s", strings.TrimSpace(input), output)
+		}
+	}
+}
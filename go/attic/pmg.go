@@ -0,0 +1,182 @@
+// =============================================================================
+// pmg.go - Showing Player/Missile Graphics State (.pmg)
+// =============================================================================
+//
+// ".pmg" summarizes the player/missile graphics setup for game debugging:
+//
+//	.pmg [pmbase]
+//
+//	DMA: players on, missiles on, single-line resolution; priority $01
+//	      color            playfield hits  player hits  data
+//	P0    $46 hue 4 lum 6  PF0 PF1         P1           $3400-$34FF
+//	...
+//	M0    (as P0)          PF2             -            $3300-$33FF
+//
+// Much of P/M graphics hardware is write-only: the horizontal positions,
+// sizes and PMBASE cannot be read back, and reading their addresses
+// returns the collision registers instead. So .pmg shows what can be read:
+// the colors and DMA settings from the OS shadow registers, which the OS
+// copies to the hardware every vertical blank, and the collision
+// registers. Given the PMBASE page a program used (often kept in a BASIC
+// variable), it also shows where each player's data lives.
+//
+// =============================================================================
+
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/attic/atticprotocol"
+)
+
+// OS shadow registers and GTIA collision registers read by .pmg.
+const (
+	pmgSDMCTL     = 0x022F // Shadow of DMACTL
+	pmgGPRIOR     = 0x026F // Shadow of PRIOR
+	pmgPCOLR0     = 0x02C0 // Shadows of COLPM0-3
+	pmgCollisions = 0xD000 // M0PF-M3PF, P0PF-P3PF, M0PL-M3PL, P0PL-P3PL
+)
+
+// SDMCTL bits for player/missile DMA.
+const (
+	dmaMissiles   = 0x04
+	dmaPlayers    = 0x08
+	dmaSingleLine = 0x10
+)
+
+// pmgState holds the registers .pmg reads.
+type pmgState struct {
+	dmactl     byte
+	prior      byte
+	colors     [4]byte
+	collisions [16]byte
+}
+
+// formatCollisions lists the set bits of a collision register as names,
+// e.g. "PF0 PF2", or "-" if none is set.
+func formatCollisions(bits byte, prefix string) string {
+	var names []string
+	for i := 0; i < 4; i++ {
+		if bits&(1<<i) != 0 {
+			names = append(names, fmt.Sprintf("%s%d", prefix, i))
+		}
+	}
+	if len(names) == 0 {
+		return "-"
+	}
+	return strings.Join(names, " ")
+}
+
+// pmgDataRange returns the memory used by player n (0-3) or by the
+// missiles (n = 4) for a PMBASE page and resolution.
+func pmgDataRange(pmbase byte, n int, singleLine bool) (uint16, uint16) {
+	// Single-line resolution uses a 2K area with 256 bytes per player,
+	// double-line a 1K area with 128; missiles come first after a gap.
+	stride := uint16(128)
+	if singleLine {
+		stride = 256
+	}
+	start := uint16(pmbase)<<8 + 3*stride
+	if n < 4 {
+		start += uint16(n+1) * stride
+	}
+	return start, start + stride - 1
+}
+
+// formatPMGState renders the state as a table. A nil pmbase leaves out
+// the data column.
+func formatPMGState(s pmgState, pmbase *byte) string {
+	var sb strings.Builder
+	onOff := func(on bool) string {
+		if on {
+			return "on"
+		}
+		return "off"
+	}
+	resolution := "double-line"
+	if s.dmactl&dmaSingleLine != 0 {
+		resolution = "single-line"
+	}
+	fmt.Fprintf(&sb, "DMA: players %s, missiles %s, %s resolution; priority $%02X\n",
+		onOff(s.dmactl&dmaPlayers != 0), onOff(s.dmactl&dmaMissiles != 0), resolution, s.prior)
+
+	header := fmt.Sprintf("      %-16s %-15s %-12s", "color", "playfield hits", "player hits")
+	if pmbase != nil {
+		header += " data"
+	}
+	sb.WriteString(strings.TrimRight(header, " ") + "\n")
+
+	row := func(name, color string, pf, pl byte, n int) {
+		line := fmt.Sprintf("%-5s %-16s %-15s %-12s", name, color, formatCollisions(pf, "PF"), formatCollisions(pl, "P"))
+		if pmbase != nil {
+			start, end := pmgDataRange(*pmbase, n, s.dmactl&dmaSingleLine != 0)
+			line += fmt.Sprintf(" $%04X-$%04X", start, end)
+		}
+		sb.WriteString(strings.TrimRight(line, " ") + "\n")
+	}
+	for i := 0; i < 4; i++ {
+		c := s.colors[i]
+		row(fmt.Sprintf("P%d", i), fmt.Sprintf("$%02X hue %d lum %d", c, c>>4, c&0x0E), s.collisions[4+i], s.collisions[12+i], i)
+	}
+	for i := 0; i < 4; i++ {
+		// Missiles take their player's color; they share one data area.
+		row(fmt.Sprintf("M%d", i), fmt.Sprintf("(as P%d)", i), s.collisions[i], s.collisions[8+i], 4)
+	}
+	sb.WriteString("Positions, sizes and PMBASE are write-only and can't be read back.\n")
+	return sb.String()
+}
+
+// runPMGCommand handles ".pmg [pmbase]".
+func runPMGCommand(client *atticprotocol.Client, args string, symbols *symbolTable, opts replOptions) {
+	fields := strings.Fields(args)
+	if len(fields) > 1 {
+		printError("usage: .pmg [pmbase]")
+		return
+	}
+	var pmbase *byte
+	if len(fields) == 1 {
+		// Accept the page ($30) or an address on it ($3000).
+		addr, ok := symbols.resolveAddress(fields[0])
+		if !ok {
+			printError(fmt.Sprintf("invalid PMBASE %q", fields[0]))
+			return
+		}
+		page := byte(addr)
+		if addr > 0xFF {
+			page = byte(addr >> 8)
+		}
+		pmbase = &page
+	}
+
+	reads := []atticprotocol.Command{
+		atticprotocol.NewReadCommand(pmgSDMCTL, 1),
+		atticprotocol.NewReadCommand(pmgGPRIOR, 1),
+		atticprotocol.NewReadCommand(pmgPCOLR0, 4),
+		atticprotocol.NewReadCommand(pmgCollisions, 16),
+	}
+	if opts.dryRun {
+		for _, cmd := range reads {
+			fmt.Println("CMD:" + cmd.Format())
+		}
+		return
+	}
+	if !client.IsConnected() {
+		printError("not connected (use .connect <socket>)")
+		return
+	}
+
+	var state pmgState
+	targets := [][]byte{{0}, {0}, state.colors[:], state.collisions[:]}
+	for i, cmd := range reads {
+		data, err := readMemory(client, cmd.Address, cmd.Count)
+		if err != nil {
+			printError(err.Error())
+			return
+		}
+		copy(targets[i], data)
+	}
+	state.dmactl, state.prior = targets[0][0], targets[1][0]
+	fmt.Print(formatPMGState(state, pmbase))
+}
@@ -0,0 +1,70 @@
+// =============================================================================
+// pmg_test.go - Tests for Showing Player/Missile Graphics State (pmg.go)
+// =============================================================================
+
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+// pmgHandler serves a setup with player and missile DMA in single-line
+// resolution, player 0 colored $46 touching PF0, PF1 and player 1, and
+// missile 0 touching PF2.
+func pmgHandler(cmd string) string {
+	switch cmd {
+	case "ping":
+		return "OK:pong\n"
+	case "read $022F 1":
+		return "OK:data 3E\n"
+	case "read $026F 1":
+		return "OK:data 01\n"
+	case "read $02C0 4":
+		return "OK:data 46,88,0E,C4\n"
+	case "read $D000 16":
+		return "OK:data 04,00,00,00,03,00,00,00,00,00,00,00,02,01,00,00\n"
+	}
+	return "ERR:unexpected " + cmd + "\n"
+}
+
+// TestREPLPMG verifies the decoded DMA settings, colors and collisions.
+func TestREPLPMG(t *testing.T) {
+	output := captureREPL(t, ".pmg\n.quit\n", pmgHandler)
+	for _, want := range []string{
+		"DMA: players on, missiles on, single-line resolution; priority $01\n",
+		"P0    $46 hue 4 lum 6  PF0 PF1         P1\n",
+		"P1    $88 hue 8 lum 8  -               P0\n",
+		"P3    $C4 hue 12 lum 4 -               -\n",
+		"M0    (as P0)          PF2             -\n",
+		"write-only",
+	} {
+		if !strings.Contains(output, want) {
+			t.Errorf("expected %q in output, got:\n%s", want, output)
+		}
+	}
+	if strings.Contains(output, "$3400") {
+		t.Errorf("data column shown without a PMBASE, got:\n%s", output)
+	}
+}
+
+// TestREPLPMGWithBase verifies the player data addresses for a PMBASE.
+func TestREPLPMGWithBase(t *testing.T) {
+	for _, base := range []string{"$30", "$3000"} {
+		output := captureREPL(t, ".pmg "+base+"\n.quit\n", pmgHandler)
+		for _, want := range []string{"$3400-$34FF", "$3700-$37FF", "$3300-$33FF"} {
+			if !strings.Contains(output, want) {
+				t.Errorf(".pmg %s: expected %q in output, got:\n%s", base, want, output)
+			}
+		}
+	}
+}
+
+func TestPMGDataRangeDoubleLine(t *testing.T) {
+	if start, end := pmgDataRange(0x30, 0, false); start != 0x3200 || end != 0x327F {
+		t.Errorf("player 0 = $%04X-$%04X, want $3200-$327F", start, end)
+	}
+	if start, end := pmgDataRange(0x30, 4, false); start != 0x3180 || end != 0x31FF {
+		t.Errorf("missiles = $%04X-$%04X, want $3180-$31FF", start, end)
+	}
+}
@@ -0,0 +1,65 @@
+// =============================================================================
+// progress.go - Progress Indicator for Slow Commands
+// =============================================================================
+//
+// Saving or loading state, taking a screenshot or formatting a disk can
+// take a while with nothing on screen. For commands whose timeout is longer
+// than the default CommandTimeout — the client's own hint that they are
+// slow — the interactive REPL shows a spinner with the elapsed time on
+// stderr until the response arrives:
+//
+//	| waiting for state save ~/game.state... 3.2s
+//
+// Piped sessions and --quiet never show it, so scripts and logs stay clean.
+//
+// =============================================================================
+
+package main
+
+import (
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/attic/atticprotocol"
+)
+
+// progressInterval is how often the spinner is redrawn.
+const progressInterval = 100 * time.Millisecond
+
+// spinnerFrames are drawn in turn; plain ASCII works in every terminal.
+var spinnerFrames = []string{"|", "/", "-", "\\"}
+
+// commandShowsProgress reports whether waiting for a command of type t
+// deserves a progress indicator: its timeout is longer than the default.
+func commandShowsProgress(client *atticprotocol.Client, t atticprotocol.CommandType) bool {
+	return client.CommandTimeoutFor(t) > atticprotocol.CommandTimeout
+}
+
+// startProgress draws a spinner labeled with label on w until the
+// returned stop function is called, which erases it.
+func startProgress(w io.Writer, label string) (stop func()) {
+	done := make(chan struct{})
+	finished := make(chan struct{})
+	start := time.Now()
+
+	go func() {
+		defer close(finished)
+		ticker := time.NewTicker(progressInterval)
+		defer ticker.Stop()
+		for frame := 0; ; frame++ {
+			fmt.Fprintf(w, "\r%s waiting for %s... %.1fs", spinnerFrames[frame%len(spinnerFrames)], label, time.Since(start).Seconds())
+			select {
+			case <-done:
+				fmt.Fprint(w, "\r\x1b[K")
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+
+	return func() {
+		close(done)
+		<-finished
+	}
+}
@@ -0,0 +1,58 @@
+// =============================================================================
+// progress_test.go - Tests for the Progress Indicator (progress.go)
+// =============================================================================
+
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/attic/atticprotocol"
+)
+
+func TestCommandShowsProgress(t *testing.T) {
+	client := atticprotocol.NewClient()
+	tests := []struct {
+		name string
+		t    atticprotocol.CommandType
+		want bool
+	}{
+		{"state save", atticprotocol.CmdStateSave, true},
+		{"state load", atticprotocol.CmdStateLoad, true},
+		{"screenshot", atticprotocol.CmdScreenshot, true},
+		{"dos format", atticprotocol.CmdDosFormat, true},
+		{"read", atticprotocol.CmdRead, false},
+		{"ping", atticprotocol.CmdPing, false},
+		{"resume", atticprotocol.CmdResume, false},
+	}
+	for _, tt := range tests {
+		if got := commandShowsProgress(client, tt.t); got != tt.want {
+			t.Errorf("%s: commandShowsProgress() = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+
+	// A user-configured timeout changes the decision.
+	client.SetCommandTimeout(atticprotocol.CmdRead, 5*time.Minute)
+	if !commandShowsProgress(client, atticprotocol.CmdRead) {
+		t.Error("a read with a long timeout should show progress")
+	}
+}
+
+// TestStartProgress verifies that the spinner draws and erases itself.
+func TestStartProgress(t *testing.T) {
+	var buf bytes.Buffer
+	stop := startProgress(&buf, "state save")
+	time.Sleep(2 * progressInterval)
+	stop()
+
+	out := buf.String()
+	if !strings.Contains(out, "waiting for state save...") {
+		t.Errorf("expected spinner label, got %q", out)
+	}
+	if !strings.HasSuffix(out, "\r\x1b[K") {
+		t.Errorf("spinner not erased, got %q", out)
+	}
+}
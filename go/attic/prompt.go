@@ -0,0 +1,82 @@
+// =============================================================================
+// prompt.go - Customizable Prompts (ATTIC_PROMPT)
+// =============================================================================
+//
+// The default prompts ("[basic] > ", "[monitor] > ", ...) can be replaced
+// by setting ATTIC_PROMPT to a template. These placeholders are expanded:
+//
+//	%m   the mode name: monitor, basic or dos
+//	%s   "!" when not connected to a server, otherwise empty
+//	%%   a literal percent sign
+//
+// For example, ATTIC_PROMPT='attic[%m]%s> ' gives "attic[basic]> ".
+// Emacs comint and other tools match on the prompt, so the result always
+// ends with a space, as the built-in prompts do.
+//
+// =============================================================================
+
+package main
+
+import (
+	"os"
+	"strings"
+)
+
+// promptEnvVar is the environment variable holding the prompt template.
+const promptEnvVar = "ATTIC_PROMPT"
+
+// name returns the lowercase mode name used in prompts.
+func (m REPLMode) name() string {
+	switch m {
+	case ModeMonitor:
+		return "monitor"
+	case ModeBasic:
+		return "basic"
+	case ModeDOS:
+		return "dos"
+	default:
+		return ""
+	}
+}
+
+// expandPromptTemplate expands the placeholders in an ATTIC_PROMPT
+// template. Unknown "%x" sequences are kept as they are.
+func expandPromptTemplate(template string, mode REPLMode, connected bool) string {
+	var sb strings.Builder
+	for i := 0; i < len(template); i++ {
+		if template[i] != '%' || i+1 == len(template) {
+			sb.WriteByte(template[i])
+			continue
+		}
+		i++
+		switch template[i] {
+		case 'm':
+			sb.WriteString(mode.name())
+		case 's':
+			if !connected {
+				sb.WriteString("!")
+			}
+		case '%':
+			sb.WriteByte('%')
+		default:
+			sb.WriteByte('%')
+			sb.WriteByte(template[i])
+		}
+	}
+
+	prompt := sb.String()
+	if !strings.HasSuffix(prompt, " ") {
+		prompt += " "
+	}
+	return prompt
+}
+
+// replPrompt returns the prompt for mode, using the ATTIC_PROMPT template
+// when it is set and the built-in prompt otherwise.
+func replPrompt(mode REPLMode, connected bool) string {
+	template := os.Getenv(promptEnvVar)
+	if template == "" {
+		return mode.prompt()
+	}
+	return expandPromptTemplate(template, mode, connected)
+}
@@ -0,0 +1,64 @@
+// =============================================================================
+// prompt_test.go - Tests for Customizable Prompts (prompt.go)
+// =============================================================================
+
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+// TestExpandPromptTemplate tests placeholder expansion for each mode.
+func TestExpandPromptTemplate(t *testing.T) {
+	tests := []struct {
+		template  string
+		mode      REPLMode
+		connected bool
+		want      string
+	}{
+		{"attic[%m]%s> ", ModeMonitor, true, "attic[monitor]> "},
+		{"attic[%m]%s> ", ModeBasic, true, "attic[basic]> "},
+		{"attic[%m]%s> ", ModeDOS, true, "attic[dos]> "},
+		{"attic[%m]%s> ", ModeBasic, false, "attic[basic]!> "},
+		{"%m>", ModeDOS, true, "dos> "},
+		{"100%% %m", ModeBasic, true, "100% basic "},
+		{"%x%", ModeBasic, true, "%x% "},
+	}
+
+	for _, tc := range tests {
+		got := expandPromptTemplate(tc.template, tc.mode, tc.connected)
+		if got != tc.want {
+			t.Errorf("expandPromptTemplate(%q, %v, %v) = %q, want %q",
+				tc.template, tc.mode, tc.connected, got, tc.want)
+		}
+	}
+}
+
+// TestReplPromptFallsBackWhenUnset verifies that the built-in prompts are
+// used when ATTIC_PROMPT is not set.
+func TestReplPromptFallsBackWhenUnset(t *testing.T) {
+	t.Setenv(promptEnvVar, "")
+
+	for _, mode := range []REPLMode{ModeMonitor, ModeBasic, ModeDOS} {
+		if got := replPrompt(mode, true); got != mode.prompt() {
+			t.Errorf("replPrompt(%v) = %q, want %q", mode, got, mode.prompt())
+		}
+	}
+}
+
+// TestREPLUsesPromptTemplate verifies that the REPL prints the templated
+// prompt.
+func TestREPLUsesPromptTemplate(t *testing.T) {
+	t.Setenv(promptEnvVar, "attic[%m]%s> ")
+
+	output := captureREPL(t, ".monitor\n.quit\n", func(cmd string) string {
+		return "OK:pong\n"
+	})
+
+	for _, want := range []string{"attic[basic]> ", "attic[monitor]> "} {
+		if !strings.Contains(output, want) {
+			t.Errorf("expected prompt %q in output, got:\n%s", want, output)
+		}
+	}
+}
@@ -0,0 +1,69 @@
+// =============================================================================
+// recall.go - Recalling the Last Response (.last, .save-last)
+// =============================================================================
+//
+// The REPL remembers the data of the most recent successful response to a
+// command typed at the prompt, so long output can be seen again or kept
+// without re-running the command:
+//
+//	.last               — print it again
+//	.save-last <path>   — write it to a file, one line per response line
+//
+// Error responses are not remembered; .last still shows the last success.
+//
+// =============================================================================
+
+package main
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/attic/atticprotocol"
+)
+
+// responseRecall holds the last OK response.
+type responseRecall struct {
+	resp atticprotocol.Response
+	set  bool
+}
+
+// record remembers resp if it is an OK response.
+func (r *responseRecall) record(resp atticprotocol.Response) {
+	if resp.IsOK() {
+		r.resp, r.set = resp, true
+	}
+}
+
+// text returns the recalled data with multi-line separators expanded.
+func (r *responseRecall) text() string {
+	return r.resp.Text()
+}
+
+// runLastCommand handles ".last".
+func runLastCommand(recall *responseRecall) {
+	if !recall.set {
+		printError("no response yet")
+		return
+	}
+	printResponse(recall.resp)
+}
+
+// runSaveLastCommand handles ".save-last <path>".
+func runSaveLastCommand(recall *responseRecall, args string) {
+	path := expandPath(strings.TrimSpace(args))
+	if path == "" {
+		printError("usage: .save-last <path>")
+		return
+	}
+	if !recall.set {
+		printError("no response yet")
+		return
+	}
+	if err := os.WriteFile(path, []byte(recall.text()+"\n"), 0o644); err != nil {
+		printError(fmt.Sprintf("cannot write %s: %v", path, err))
+		return
+	}
+	fmt.Printf("Saved last response to %s\n", path)
+}
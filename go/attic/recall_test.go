@@ -0,0 +1,60 @@
+// =============================================================================
+// recall_test.go - Tests for Recalling the Last Response (recall.go)
+// =============================================================================
+
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// recallHandler answers two commands, one of them with an error.
+func recallHandler(cmd string) string {
+	switch cmd {
+	case "ping":
+		return "OK:pong\n"
+	case "drives":
+		return "OK:D1: /tmp/game.atr\x1ED2: (none)\n"
+	}
+	return "ERR:unexpected " + cmd + "\n"
+}
+
+// TestREPLLast verifies that .last reprints the previous response, and
+// that an error response does not replace it.
+func TestREPLLast(t *testing.T) {
+	output := captureREPL(t, ".monitor\ndrives\nbogus\n.last\n.quit\n", recallHandler)
+	if got := strings.Count(output, "D1: /tmp/game.atr\nD2: (none)"); got != 2 {
+		t.Errorf("expected the drives response twice, found %d times in:\n%s", got, output)
+	}
+}
+
+// TestREPLSaveLast verifies that .save-last writes the response lines.
+func TestREPLSaveLast(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "drives.txt")
+
+	output := captureREPL(t, ".monitor\ndrives\n.save-last "+path+"\n.quit\n", recallHandler)
+	if !strings.Contains(output, "Saved last response to "+path) {
+		t.Errorf("expected confirmation, got:\n%s", output)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("failed to read saved response: %v", err)
+	}
+	if want := "D1: /tmp/game.atr\nD2: (none)\n"; string(data) != want {
+		t.Errorf("file contents = %q, want %q", data, want)
+	}
+}
+
+// TestREPLSaveLastNothing verifies that nothing is written before any
+// response has been received.
+func TestREPLSaveLastNothing(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "none.txt")
+
+	_ = captureREPL(t, ".save-last "+path+"\n.quit\n", recallHandler)
+	if _, err := os.Stat(path); err == nil {
+		t.Error("no file should be written without a response")
+	}
+}
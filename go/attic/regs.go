@@ -0,0 +1,108 @@
+// =============================================================================
+// regs.go - Register Snapshots (.regs)
+// =============================================================================
+//
+// ".regs" keeps named copies of the CPU registers for the session, so an
+// experiment can be rolled back:
+//
+//	.regs save before
+//	... step, poke registers ...
+//	.regs restore before
+//	.regs                    list the saved snapshots
+//
+// Snapshots live only in the REPL; restoring sets all six registers with
+// one "registers A=.. X=.. ..." command.
+//
+// =============================================================================
+
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/attic/atticprotocol"
+)
+
+// regSnapshots maps snapshot names to saved registers.
+type regSnapshots map[string]atticprotocol.Registers
+
+// restoreRegistersCommand returns the command that sets every register to regs.
+func restoreRegistersCommand(regs atticprotocol.Registers) atticprotocol.Command {
+	return atticprotocol.NewRegistersCommand([]atticprotocol.RegisterModification{
+		{Name: "A", Value: uint16(regs.A)},
+		{Name: "X", Value: uint16(regs.X)},
+		{Name: "Y", Value: uint16(regs.Y)},
+		{Name: "S", Value: uint16(regs.S)},
+		{Name: "P", Value: uint16(regs.P)},
+		{Name: "PC", Value: regs.PC},
+	})
+}
+
+// formatRegisters renders registers the way the server lists them.
+func formatRegisters(regs atticprotocol.Registers) string {
+	return fmt.Sprintf("A=$%02X X=$%02X Y=$%02X S=$%02X P=$%02X PC=$%04X",
+		regs.A, regs.X, regs.Y, regs.S, regs.P, regs.PC)
+}
+
+// runRegsCommand handles ".regs [save|restore <name>]".
+func runRegsCommand(client *atticprotocol.Client, snapshots regSnapshots, args string, opts replOptions) {
+	fields := strings.Fields(args)
+	if len(fields) == 0 {
+		if len(snapshots) == 0 {
+			fmt.Println("No register snapshots")
+			return
+		}
+		names := make([]string, 0, len(snapshots))
+		for name := range snapshots {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			fmt.Printf("%-12s %s\n", name, formatRegisters(snapshots[name]))
+		}
+		return
+	}
+	if len(fields) != 2 || (strings.ToLower(fields[0]) != "save" && strings.ToLower(fields[0]) != "restore") {
+		printError("usage: .regs [save|restore <name>]")
+		return
+	}
+	name := fields[1]
+
+	if strings.ToLower(fields[0]) == "restore" {
+		regs, ok := snapshots[name]
+		if !ok {
+			printError(fmt.Sprintf("no register snapshot named %q", name))
+			return
+		}
+		if !opts.dryRun && !client.IsConnected() {
+			printError("not connected (use .connect <socket>)")
+			return
+		}
+		sendCommand(client, restoreRegistersCommand(regs), opts)
+		return
+	}
+
+	cmd := atticprotocol.NewRegistersCommand(nil)
+	if opts.dryRun {
+		fmt.Println("CMD:" + cmd.Format())
+		return
+	}
+	if !client.IsConnected() {
+		printError("not connected (use .connect <socket>)")
+		return
+	}
+	resp, err := client.Send(cmd)
+	if err != nil {
+		printError(err.Error())
+		return
+	}
+	regs, err := resp.AsRegisters()
+	if err != nil {
+		printError(err.Error())
+		return
+	}
+	snapshots[name] = regs
+	fmt.Printf("Saved %s: %s\n", name, formatRegisters(regs))
+}
@@ -0,0 +1,44 @@
+// =============================================================================
+// regs_test.go - Tests for Register Snapshots (regs.go)
+// =============================================================================
+
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+// TestREPLRegsSaveRestore verifies that restoring a snapshot sets every
+// saved register.
+func TestREPLRegsSaveRestore(t *testing.T) {
+	var received []string
+	output := captureREPL(t, ".regs save start\n.regs\n.regs restore start\n.quit\n", func(cmd string) string {
+		if cmd == "ping" {
+			return "OK:pong\n"
+		}
+		received = append(received, cmd)
+		if cmd == "registers" {
+			return "OK:A=$12 X=$34 Y=$56 S=$FD P=$30 PC=$0642\n"
+		}
+		return "OK:" + strings.TrimPrefix(cmd, "registers ") + "\n"
+	})
+
+	want := []string{"registers", "registers A=$0012 X=$0034 Y=$0056 S=$00FD P=$0030 PC=$0642"}
+	if strings.Join(received, "|") != strings.Join(want, "|") {
+		t.Errorf("commands = %q, want %q", received, want)
+	}
+	if !strings.Contains(output, "start        A=$12 X=$34 Y=$56 S=$FD P=$30 PC=$0642") {
+		t.Errorf("expected snapshot listing, got:\n%s", output)
+	}
+}
+
+// TestREPLRegsUsage verifies that bad arguments and unknown snapshots
+// send nothing.
+func TestREPLRegsUsage(t *testing.T) {
+	input := ".regs restore missing\n.regs save\n.regs drop x\n.quit\n"
+	output := captureREPLWithOptions(t, input, nil, replOptions{dryRun: true})
+	if strings.Contains(output, "CMD:") {
+		t.Errorf("expected no commands, got:\n%s", output)
+	}
+}
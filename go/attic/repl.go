@@ -36,27 +36,15 @@ package main
 // Scanner is simpler for line-by-line reading (which is what a REPL needs),
 // while Reader is better when you need more control.
 //
-<<<<<<< HEAD
-<<<<<<< HEAD
-<<<<<<< HEAD
-=======
->>>>>>> f994c51 (docs(go-cli): Add Python comparisons to all GO CONCEPT tutorial blocks)
-=======
 // In this file, we no longer use bufio directly for REPL input — that's
 // been moved to the LineEditor (lineeditor.go). But the concept is still
 // relevant for understanding the non-interactive fallback path.
 //
->>>>>>> 9bad526 (feat(go-cli): Phase 2 & 3 — line editor and REPL integration)
 // Compare with Python: Python file objects are buffered by default.
 // `sys.stdin` can be iterated line by line: `for line in sys.stdin:`.
 // For explicit buffering, use `io.BufferedReader`. The `readline` module
 // adds line editing (history, tab completion) on top of stdin.
 //
-<<<<<<< HEAD
-=======
->>>>>>> 810cc4d (docs(go-cli): Add comprehensive Go tutorial comments for learning)
-=======
->>>>>>> f994c51 (docs(go-cli): Add Python comparisons to all GO CONCEPT tutorial blocks)
 // The "strings" package provides string manipulation functions. Go strings
 // are immutable (like Swift), so operations return new strings rather than
 // modifying in place.
@@ -65,6 +53,7 @@ import (
 	"io"
 	"os"
 	"strings"
+	"time"
 
 	"github.com/attic/atticprotocol"
 )
@@ -85,20 +74,11 @@ import (
 // Go's approach:
 //   type REPLMode int
 //   const ( ModeMonitor REPLMode = iota; ModeBasic; ModeDOS )
-<<<<<<< HEAD
-<<<<<<< HEAD
-=======
->>>>>>> f994c51 (docs(go-cli): Add Python comparisons to all GO CONCEPT tutorial blocks)
 //
 // Compare with Python: Python uses `enum.IntEnum` for integer enums:
 //   `class REPLMode(IntEnum): MONITOR = 0; BASIC = 1; DOS = 2`
 // Python enums are full classes with rich features: iteration, names,
 // and pattern matching support.
-<<<<<<< HEAD
-=======
->>>>>>> 810cc4d (docs(go-cli): Add comprehensive Go tutorial comments for learning)
-=======
->>>>>>> f994c51 (docs(go-cli): Add Python comparisons to all GO CONCEPT tutorial blocks)
 
 // REPLMode represents the current operating mode of the REPL.
 type REPLMode int
@@ -109,26 +89,17 @@ type REPLMode int
 // at 0 and increments by 1 for each constant. It resets to 0 in each
 // new const block.
 //
-<<<<<<< HEAD
 //	const (
 //	    ModeMonitor REPLMode = iota  // 0
 //	    ModeBasic                     // 1 (type and iota+1 carried forward)
 //	    ModeDOS                       // 2
 //	)
-=======
-//   const (
-//       ModeMonitor REPLMode = iota  // 0
-//       ModeBasic                     // 1 (type and iota+1 carried forward)
-//       ModeDOS                       // 2
-//   )
->>>>>>> 810cc4d (docs(go-cli): Add comprehensive Go tutorial comments for learning)
 //
 // The type "REPLMode" and the expression "iota" carry forward to
 // subsequent lines that omit them. This is a special Go shorthand for
 // const blocks.
 //
 // You can also do arithmetic with iota:
-<<<<<<< HEAD
 //
 //	const ( KB = 1 << (10 * (iota + 1)); MB; GB; TB )
 //	// KB=1024, MB=1048576, GB=1073741824, TB=1099511627776
@@ -143,21 +114,6 @@ type REPLMode int
 //
 // Values start at 1 by default (not 0), but you can override with a
 // custom `_generate_next_value_` method.
-=======
-//   const ( KB = 1 << (10 * (iota + 1)); MB; GB; TB )
-//   // KB=1024, MB=1048576, GB=1073741824, TB=1099511627776
-//
-// Compare to Swift: Swift enums auto-assign raw values similarly:
-//   enum Mode: Int { case monitor = 0, basic, dos }
-<<<<<<< HEAD
->>>>>>> 810cc4d (docs(go-cli): Add comprehensive Go tutorial comments for learning)
-=======
-//
-// Compare with Python: Python's `enum.auto()` serves the same purpose:
-//   `class REPLMode(IntEnum): MONITOR = auto(); BASIC = auto()`
-// Values start at 1 by default (not 0), but you can override with a
-// custom `_generate_next_value_` method.
->>>>>>> f994c51 (docs(go-cli): Add Python comparisons to all GO CONCEPT tutorial blocks)
 const (
 	// ModeMonitor is the 6502 debugging mode.
 	ModeMonitor REPLMode = iota
@@ -193,21 +149,12 @@ const (
 //     func prompt() -> String { switch self { ... } }
 //   Go requires you to name the receiver explicitly:
 //     func (m REPLMode) prompt() string { switch m { ... } }
-<<<<<<< HEAD
-<<<<<<< HEAD
-=======
->>>>>>> f994c51 (docs(go-cli): Add Python comparisons to all GO CONCEPT tutorial blocks)
 //
 // Compare with Python: Python methods always take `self` as the first
 // parameter (like Go's receiver, but always named `self`):
 //   `def prompt(self) -> str: ...`
 // Python allows adding methods to any class, including subclasses of
 // built-in types.
-<<<<<<< HEAD
-=======
->>>>>>> 810cc4d (docs(go-cli): Add comprehensive Go tutorial comments for learning)
-=======
->>>>>>> f994c51 (docs(go-cli): Add Python comparisons to all GO CONCEPT tutorial blocks)
 
 // prompt returns the display prompt for the current REPL mode.
 func (m REPLMode) prompt() string {
@@ -229,62 +176,6 @@ func (m REPLMode) prompt() string {
 // internally. This is "dependency injection" — the caller (main.go) creates
 // the LineEditor and passes it in. Benefits:
 //
-<<<<<<< HEAD
-// Here "atasciiMode" is accepted but not yet used (full implementation
-// comes in a later phase). In Swift you'd use "_ atasciiMode: Bool" to
-// suppress the external label, but Go doesn't have external parameter names.
-<<<<<<< HEAD
-<<<<<<< HEAD
-=======
->>>>>>> f994c51 (docs(go-cli): Add Python comparisons to all GO CONCEPT tutorial blocks)
-//
-// Compare with Python: Python uses `_` for unused parameters by
-// convention: `def run_repl(client, _atascii_mode):`. Unlike Go, Python
-// never raises errors for unused variables — it's purely a linter
-// concern (e.g., pylint W0613).
-<<<<<<< HEAD
-=======
->>>>>>> 810cc4d (docs(go-cli): Add comprehensive Go tutorial comments for learning)
-=======
->>>>>>> f994c51 (docs(go-cli): Add Python comparisons to all GO CONCEPT tutorial blocks)
-
-// runREPL runs the main REPL loop.
-//
-// This is a minimal stub for Phase 1. It reads lines from stdin and sends
-// them as raw protocol commands. The full implementation with command
-// translation, mode switching, help system, and line editing will be added
-// in subsequent phases.
-func runREPL(client *atticprotocol.Client, atasciiMode bool) {
-	// GO CONCEPT: bufio.Scanner
-	// -------------------------
-	// Scanner provides a convenient interface for reading input line by line.
-	//
-	//   scanner := bufio.NewScanner(os.Stdin)  — create scanner from stdin
-	//   scanner.Scan()                          — read next line (returns bool)
-	//   scanner.Text()                          — get the line (without \n)
-	//   scanner.Err()                           — check for errors after loop
-	//
-	// Scan() returns true if a line was read, false on EOF or error.
-	// This makes it perfect for a "for scanner.Scan() { ... }" loop.
-	//
-	// Compare to Swift:
-	//   Swift: readLine() returns String? (nil on EOF)
-	//   Go:    scanner.Scan() returns bool + scanner.Text() for the string
-<<<<<<< HEAD
-<<<<<<< HEAD
-=======
->>>>>>> f994c51 (docs(go-cli): Add Python comparisons to all GO CONCEPT tutorial blocks)
-	//
-	// Compare with Python: `for line in sys.stdin:` iterates lines (keeping
-	// `\n`). `input()` reads one line (stripping `\n`) and raises `EOFError`
-	// on EOF. Python's `input()` is closest to Go's Scanner for REPL use.
-<<<<<<< HEAD
-=======
->>>>>>> 810cc4d (docs(go-cli): Add comprehensive Go tutorial comments for learning)
-=======
->>>>>>> f994c51 (docs(go-cli): Add Python comparisons to all GO CONCEPT tutorial blocks)
-	scanner := bufio.NewScanner(os.Stdin)
-=======
 //   1. Testability: Tests can pass a mock or test-configured LineEditor.
 //   2. Lifetime control: The caller manages creation and cleanup (Close).
 //   3. Flexibility: The same REPL code works with different input sources.
@@ -300,6 +191,31 @@ func runREPL(client *atticprotocol.Client, atasciiMode bool) {
 // Python also supports dependency injection frameworks like `inject` or
 // `dependency-injector`, but simple parameter passing is most common.
 
+// replOptions holds the command-line settings that affect REPL behavior.
+type replOptions struct {
+	// atascii enables rich ATASCII rendering in program listings.
+	atascii bool
+
+	// dryRun prints the translated protocol commands instead of sending
+	// them. No server connection is needed in this mode.
+	dryRun bool
+
+	// echo prints each non-interactive input line as "+ <line>" before
+	// handling it.
+	echo bool
+
+	// quiet suppresses the progress indicator for slow commands.
+	quiet bool
+
+	// autoPause pauses a running emulator before monitor-mode memory
+	// writes (see autopause.go).
+	autoPause autoPauseMode
+
+	// screenshotDir is the directory for auto-named screenshots. Empty
+	// means the default (~/Desktop).
+	screenshotDir string
+}
+
 // runREPL runs the main REPL loop.
 //
 // It reads user input via the LineEditor, processes local dot-commands
@@ -307,38 +223,55 @@ func runREPL(client *atticprotocol.Client, atasciiMode bool) {
 // as raw protocol commands. Responses are displayed with multi-line
 // expansion (replacing Record Separator characters with newlines).
 //
+// In dry-run mode (opts.dryRun), input is run through translateToProtocol
+// and each resulting command is printed as "CMD:<command>" instead of being
+// sent. client may be nil in this mode.
+//
 // The REPL exits on:
 //   - .quit command
 //   - .shutdown command (also stops the server)
 //   - EOF (Ctrl-D in interactive mode, end of piped input)
 //   - LineEditor read error
-func runREPL(client *atticprotocol.Client, editor *LineEditor, atasciiMode bool) {
->>>>>>> 9bad526 (feat(go-cli): Phase 2 & 3 — line editor and REPL integration)
+func runREPL(client *atticprotocol.Client, editor *LineEditor, opts replOptions) {
 	mode := ModeBasic
+	symbols := newSymbolTable()
+	screenshots := newScreenshotNamer(opts.screenshotDir)
+	completer := newREPLCompleter(client)
+	editor.SetCompleter(completer)
+	var recall responseRecall
+	var results resultVars
+	var cycles cycleCounter
+	var commands commandRecall
+	var state runState
+	snapshots := regSnapshots{}
+
+	// Follow stops and breakpoints so writes know whether to pause.
+	if !opts.dryRun && client != nil {
+		previous := client.EventHandler()
+		client.SetEventHandler(func(event atticprotocol.Event) {
+			if previous != nil {
+				previous(event)
+			}
+			state.noteEvent(event)
+		})
+	}
 
 	// GO CONCEPT: Infinite Loops
 	// ---------------------------
 	// "for { ... }" is Go's infinite loop (equivalent to "while true").
 	// We use break/return to exit the loop. Most REPLs use this pattern:
 	// loop forever, reading input and processing it, until the user quits.
-<<<<<<< HEAD
-<<<<<<< HEAD
 	//
 	// Compare with Python: `while True:` is Python's infinite loop. `break`
 	// and `return` exit it, just like in Go.
-=======
->>>>>>> 810cc4d (docs(go-cli): Add comprehensive Go tutorial comments for learning)
-=======
-	//
-	// Compare with Python: `while True:` is Python's infinite loop. `break`
-	// and `return` exit it, just like in Go.
->>>>>>> f994c51 (docs(go-cli): Add Python comparisons to all GO CONCEPT tutorial blocks)
 	for {
 		// Read a line of input using the LineEditor.
 		// In interactive mode, this provides Emacs keybindings, history
 		// navigation (up/down arrows, Ctrl-R), and persistent history.
 		// In non-interactive mode, it prints the prompt and reads from stdin.
-		line, err := editor.GetLine(mode.prompt())
+		connected := !opts.dryRun && client.IsConnected()
+		completer.setMode(mode)
+		line, err := editor.GetLine(outputColors.promptText(replPrompt(mode, connected)))
 		if err != nil {
 			// GO CONCEPT: Comparing Errors with ==
 			// --------------------------------------
@@ -381,25 +314,12 @@ func runREPL(client *atticprotocol.Client, editor *LineEditor, atasciiMode bool)
 		//
 		// These are standalone functions, not methods, because Go's string
 		// type is a built-in primitive. You can't add methods to built-in types.
-<<<<<<< HEAD
-<<<<<<< HEAD
-=======
->>>>>>> f994c51 (docs(go-cli): Add Python comparisons to all GO CONCEPT tutorial blocks)
 		//
 		// Compare with Python: Python strings have methods directly (not
 		// standalone functions): `line.strip()`, `line.startswith(".")`,
 		// `line.split()`, `" ".join(parts)`, `line.upper()`, `"sub" in line`.
 		// This is the opposite of Go — Python attaches methods to the str type.
-<<<<<<< HEAD
-<<<<<<< HEAD
-=======
->>>>>>> 810cc4d (docs(go-cli): Add comprehensive Go tutorial comments for learning)
-=======
->>>>>>> f994c51 (docs(go-cli): Add Python comparisons to all GO CONCEPT tutorial blocks)
-		line := strings.TrimSpace(scanner.Text())
-=======
 		line = strings.TrimSpace(line)
->>>>>>> 9bad526 (feat(go-cli): Phase 2 & 3 — line editor and REPL integration)
 		if line == "" {
 			// GO CONCEPT: continue and break
 			// --------------------------------
@@ -408,23 +328,35 @@ func runREPL(client *atticprotocol.Client, editor *LineEditor, atasciiMode bool)
 			// "return" exits the entire function.
 			//
 			// Same semantics as Swift's continue/break/return.
-<<<<<<< HEAD
-<<<<<<< HEAD
-=======
->>>>>>> f994c51 (docs(go-cli): Add Python comparisons to all GO CONCEPT tutorial blocks)
 			//
 			// Compare with Python: Identical keywords and semantics: `continue`,
 			// `break`, `return`. Python also has `else` clauses on loops
 			// (`for...else`, `while...else`) that run when the loop completes
 			// without `break` — a feature neither Go nor Swift has.
-<<<<<<< HEAD
-=======
->>>>>>> 810cc4d (docs(go-cli): Add comprehensive Go tutorial comments for learning)
-=======
->>>>>>> f994c51 (docs(go-cli): Add Python comparisons to all GO CONCEPT tutorial blocks)
 			continue
 		}
 
+		// Replace "!!" and "!prefix" with an earlier command, showing the
+		// result as a shell does. With --echo the echo below shows it.
+		expanded, isRef, err := commands.expand(line)
+		if err != nil {
+			printError(err.Error())
+			continue
+		}
+		line = expanded
+		commands.record(line)
+
+		// Echo piped input so each response follows its command, like a
+		// shell's "set -x" trace.
+		if opts.echo && !editor.IsInteractive() {
+			fmt.Println("+ " + line)
+		} else if isRef {
+			fmt.Println(line)
+		}
+
+		// Substitute $_ and $? from the previous response.
+		line = results.expand(line)
+
 		// GO CONCEPT: String Case Conversion for Commands
 		// ------------------------------------------------
 		// strings.ToLower() converts a string to lowercase. We use this
@@ -466,6 +398,10 @@ func runREPL(client *atticprotocol.Client, editor *LineEditor, atasciiMode bool)
 			return
 		case ".shutdown":
 			// .shutdown tells the server to stop, then exits the CLI.
+			if opts.dryRun {
+				fmt.Println("CMD:shutdown")
+				return
+			}
 			_, _ = client.SendRaw("shutdown")
 			return
 		case ".monitor":
@@ -477,9 +413,11 @@ func runREPL(client *atticprotocol.Client, editor *LineEditor, atasciiMode bool)
 		case ".dos":
 			mode = ModeDOS
 			fmt.Println("Switched to DOS mode")
+		case ".commands":
+			printCommandList()
 		case ".help":
 			fmt.Println("Help system will be implemented in Phase 6.")
-			fmt.Println("Dot-commands: .monitor .basic .dos .quit .shutdown .help")
+			fmt.Println("Dot-commands: .monitor .basic .basic load-host .dos .dos check .importdir .sym .tokens .watchmem .savebin .loadbin .verify .memmap .cycles .bootinfo .swap .eject .screen .dlist .pmg .palette .strings .u8 .u16 .i16 .disasm .bp .cont .regs .bt .trace .where .audio .video .set .ping .connect .disconnect .last .save-last .page .commands .quit .shutdown .help")
 		default:
 			handled = false
 		}
@@ -500,43 +438,497 @@ func runREPL(client *atticprotocol.Client, editor *LineEditor, atasciiMode bool)
 		if strings.HasPrefix(lowerLine, ".help ") {
 			// Help with topic — extract the topic after ".help "
 			topic := strings.TrimSpace(line[6:])
+			if strings.EqualFold(topic, "basic") {
+				printBASICHelp()
+				continue
+			}
 			fmt.Printf("Help for %q will be implemented in Phase 6.\n", topic)
 			continue
 		}
 
-		// Send as raw protocol command (no translation yet — the full
-		// command translator will be implemented in Phase 5).
-		//
-		// SendRaw wraps the input as "CMD:<input>\n" and waits for a
-		// response from the server.
-		resp, err := client.SendRaw(line)
-		if err != nil {
-			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		// Symbol definitions are kept locally by the CLI.
+		if lowerLine == ".sym" || strings.HasPrefix(lowerLine, ".sym ") {
+			handleSymbolCommand(symbols, line[len(".sym"):])
 			continue
 		}
 
-		// Display response, expanding multi-line separators.
-		//
-		// GO CONCEPT: Protocol Separator Handling
-		// ----------------------------------------
-		// The CLI text protocol uses ASCII Record Separator (0x1E, \x1E)
-		// to encode multiple lines in a single response. We replace them
-		// with actual newlines for display. This avoids the complexity of
-		// a streaming protocol while still supporting multi-line output
-		// like disassembly listings and memory dumps.
-		//
-		// Compare with Swift: Swift uses the same approach:
-		//   output.replacingOccurrences(of: "\u{1E}", with: "\n")
-		//
-		// Compare with Python: Python string replacement:
-		//   output.replace("\x1e", "\n")
-		if resp.IsOK() {
-			if resp.Data != "" {
-				output := strings.ReplaceAll(resp.Data, atticprotocol.MultiLineSeparator, "\n")
-				fmt.Println(output)
+		// .basic load-host loads a BASIC program from a host file.
+		if lowerLine == ".basic load-host" || strings.HasPrefix(lowerLine, ".basic load-host ") {
+			runBasicLoadHostCommand(client, line[len(".basic load-host"):], opts)
+			continue
+		}
+
+		// .dos check checks the current disk's VTOC and directory.
+		if lowerLine == ".dos check" || strings.HasPrefix(lowerLine, ".dos check ") {
+			runDosCheckCommand(client, line[len(".dos check"):], opts)
+			continue
+		}
+
+		// .importdir imports every file in a host directory onto the disk.
+		if lowerLine == ".importdir" || strings.HasPrefix(lowerLine, ".importdir ") {
+			runImportDirCommand(client, line[len(".importdir"):], opts)
+			continue
+		}
+
+		// .tokens saves the tokenized BASIC program to a host file.
+		if lowerLine == ".tokens" || strings.HasPrefix(lowerLine, ".tokens ") {
+			runTokensCommand(client, line[len(".tokens"):], opts)
+			continue
+		}
+
+		// .loadbin copies a host file into emulator memory.
+		if lowerLine == ".loadbin" || strings.HasPrefix(lowerLine, ".loadbin ") {
+			runLoadBinCommand(client, line[len(".loadbin"):], symbols, &state, opts)
+			continue
+		}
+
+		// .verify compares emulator memory with a host file.
+		if lowerLine == ".verify" || strings.HasPrefix(lowerLine, ".verify ") {
+			runVerifyCommand(client, line[len(".verify"):], symbols, opts)
+			continue
+		}
+
+		// .savebin copies emulator memory into a host file.
+		if strings.HasPrefix(lowerLine, ".savebin ") || lowerLine == ".savebin" {
+			runSaveBinCommand(client, line[len(".savebin"):], symbols, opts)
+			continue
+		}
+
+		// .reset and .warmstart optionally wait for the reset to finish.
+		if lowerLine == ".reset" || strings.HasPrefix(lowerLine, ".reset ") {
+			runResetCommand(client, true, line[len(".reset"):], opts)
+			state.forget() // A reset may leave the emulator running
+			continue
+		}
+		if lowerLine == ".warmstart" || strings.HasPrefix(lowerLine, ".warmstart ") {
+			runResetCommand(client, false, line[len(".warmstart"):], opts)
+			state.forget() // A reset may leave the emulator running
+			continue
+		}
+
+		// .audio mutes or unmutes the emulator at runtime.
+		if lowerLine == ".audio" || strings.HasPrefix(lowerLine, ".audio ") {
+			runAudioCommand(client, line[len(".audio"):], opts)
+			continue
+		}
+
+		// .trace logs executed instructions to a file on the server.
+		if lowerLine == ".trace" || strings.HasPrefix(lowerLine, ".trace ") {
+			runTraceCommand(client, line[len(".trace"):], opts)
+			continue
+		}
+
+		// .video shows or switches between NTSC and PAL.
+		if lowerLine == ".video" || strings.HasPrefix(lowerLine, ".video ") {
+			runVideoCommand(client, line[len(".video"):], opts)
+			continue
+		}
+
+		// .bp saves the breakpoint list to a file or restores it.
+		if lowerLine == ".bp" || strings.HasPrefix(lowerLine, ".bp ") {
+			runBreakpointFileCommand(client, line[len(".bp"):], opts)
+			continue
+		}
+
+		// .regs saves and restores register snapshots.
+		if lowerLine == ".regs" || strings.HasPrefix(lowerLine, ".regs ") {
+			runRegsCommand(client, snapshots, line[len(".regs"):], opts)
+			continue
+		}
+
+		// .cont resumes past a number of breakpoint hits.
+		if lowerLine == ".cont" || strings.HasPrefix(lowerLine, ".cont ") {
+			runContCommand(client, line[len(".cont"):], opts)
+			state.forget() // May have left the emulator running
+			continue
+		}
+
+		// .bt guesses the call stack from return addresses.
+		if lowerLine == ".bt" {
+			runBacktraceCommand(client, opts)
+			continue
+		}
+
+		// .where shows the instruction at the program counter.
+		if lowerLine == ".where" {
+			runWhereCommand(client, opts)
+			continue
+		}
+
+		// .last and .save-last recall the previous response.
+		if lowerLine == ".last" {
+			runLastCommand(&recall)
+			continue
+		}
+		if lowerLine == ".save-last" || strings.HasPrefix(lowerLine, ".save-last ") {
+			runSaveLastCommand(&recall, line[len(".save-last"):])
+			continue
+		}
+
+		// .cycles reads the CPU cycle counter.
+		if lowerLine == ".cycles" {
+			runCyclesCommand(client, &cycles, opts)
+			continue
+		}
+
+		// .swap and .eject change disks without a separate unmount.
+		if lowerLine == ".swap" || strings.HasPrefix(lowerLine, ".swap ") {
+			runSwapCommand(client, line[len(".swap"):], opts)
+			continue
+		}
+		if lowerLine == ".eject" || strings.HasPrefix(lowerLine, ".eject ") {
+			runEjectCommand(client, line[len(".eject"):], opts)
+			continue
+		}
+
+		// .bootinfo shows what the emulator booted from.
+		if lowerLine == ".bootinfo" {
+			runBootInfoCommand(client, opts)
+			continue
+		}
+
+		// .memmap lists the ROM, RAM and I/O regions.
+		if lowerLine == ".memmap" {
+			runMemoryMapCommand(client, opts)
+			continue
+		}
+
+		// .strings shows a string from memory as text.
+		if lowerLine == ".strings" || strings.HasPrefix(lowerLine, ".strings ") {
+			runStringsCommand(client, line[len(".strings"):], symbols, opts)
+			continue
+		}
+
+		// .u8, .u16 and .i16 show memory as an integer.
+		if name, _, _ := strings.Cut(lowerLine, " "); memoryIntTypes[name].size != 0 {
+			runMemoryIntCommand(client, name, line[len(name):], symbols, opts)
+			continue
+		}
+
+		// .pmg shows the player/missile graphics state.
+		if lowerLine == ".pmg" || strings.HasPrefix(lowerLine, ".pmg ") {
+			runPMGCommand(client, line[len(".pmg"):], symbols, opts)
+			continue
+		}
+
+		// .palette shows or changes the color registers.
+		if lowerLine == ".palette" || strings.HasPrefix(lowerLine, ".palette ") {
+			runPaletteCommand(client, line[len(".palette"):], opts)
+			continue
+		}
+
+		// .dlist decodes the ANTIC display list.
+		if lowerLine == ".dlist" || strings.HasPrefix(lowerLine, ".dlist ") {
+			runDisplayListCommand(client, line[len(".dlist"):], symbols, opts)
+			continue
+		}
+
+		// .disasm writes a disassembly listing to a host file.
+		if lowerLine == ".disasm" || strings.HasPrefix(lowerLine, ".disasm ") {
+			runDisasmCommand(client, line[len(".disasm"):], symbols, opts)
+			continue
+		}
+
+		// .connect and .disconnect switch servers without restarting.
+		if lowerLine == ".connect" || strings.HasPrefix(lowerLine, ".connect ") {
+			handleConnectCommand(client, line[len(".connect"):], opts)
+			completer.invalidate()
+			continue
+		}
+		if lowerLine == ".disconnect" {
+			handleDisconnectCommand(client, opts)
+			completer.invalidate()
+			continue
+		}
+
+		// .page toggles paging of long responses.
+		if lowerLine == ".page" || strings.HasPrefix(lowerLine, ".page ") {
+			handlePageCommand(line[len(".page"):])
+			continue
+		}
+
+		// .ping measures round-trip latency to the server.
+		if lowerLine == ".ping" || strings.HasPrefix(lowerLine, ".ping ") {
+			runPingCommand(client, line[len(".ping"):], opts)
+			continue
+		}
+
+		// .set changes display options.
+		if lowerLine == ".set" || strings.HasPrefix(lowerLine, ".set ") {
+			handleSetCommand(line[len(".set"):])
+			continue
+		}
+
+		// .watchmem polls memory until the user presses Enter or Ctrl-C.
+		if lowerLine == ".watchmem" || strings.HasPrefix(lowerLine, ".watchmem ") {
+			runWatchCommand(client, editor, line[len(".watchmem"):], symbols, opts)
+			continue
+		}
+
+		// .screen checks for a text mode before reading the screen.
+		if lowerLine == ".screen" || strings.HasPrefix(lowerLine, ".screen ") {
+			runScreenCommand(client, line[len(".screen"):], opts)
+			continue
+		}
+
+		// Screenshots without a path get a unique generated name so that
+		// repeated captures never overwrite each other.
+		if lowerLine == ".screenshot" || strings.HasPrefix(lowerLine, ".screenshot ") {
+			path := expandPath(strings.TrimSpace(line[len(".screenshot"):]))
+			if path == "" {
+				path = screenshots.next()
+			}
+			sendCommand(client, atticprotocol.NewScreenshotCommand(path), opts)
+			continue
+		}
+
+		// goto runs to an address using a one-shot breakpoint and waits
+		// for it to be hit.
+		if mode == ModeMonitor && (lowerLine == "goto" || strings.HasPrefix(lowerLine, "goto ")) {
+			if !opts.dryRun && !client.IsConnected() {
+				printError("not connected (use .connect <socket>)")
+				continue
+			}
+			runGotoCommand(client, line[len("goto"):], symbols, opts)
+			state.forget() // May have left the emulator running
+			continue
+		}
+
+		// Any other dot-command is a typo; don't type it into the Atari
+		// or send it to the server.
+		if strings.HasPrefix(line, ".") {
+			if _, ok := translateDotCommand(line); !ok {
+				printError("Unknown command: " + line)
+				continue
+			}
+		}
+
+		// In monitor mode, replace known symbol names in the arguments
+		// with their addresses so the server only ever sees numbers.
+		if mode == ModeMonitor {
+			line = symbols.resolve(line)
+		}
+
+		// In dry-run mode, show what the translator would put on the wire
+		// and skip the server entirely.
+		if opts.dryRun {
+			for _, cmd := range translateToProtocol(line, mode, opts.atascii) {
+				expanded, err := expandCommandPaths(cmd)
+				if err != nil {
+					printError(err.Error())
+					break
+				}
+				fmt.Println("CMD:" + expanded)
+			}
+			continue
+		}
+
+		// Without a connection only dot-commands are available.
+		if !client.IsConnected() {
+			printError("not connected (use .connect <socket>)")
+			continue
+		}
+
+		// The server only writes memory while paused; with --auto-pause,
+		// pause a running emulator first.
+		types := lineCommandTypes(line, mode, opts.atascii)
+		autoPaused := false
+		if mode == ModeMonitor && opts.autoPause != autoPauseOff && writesMemory(types) {
+			if autoPaused, err = pauseForWrite(client, &state); err != nil {
+				printError(err.Error())
+				continue
+			}
+			if autoPaused && opts.autoPause == autoPauseOn {
+				fmt.Println("Paused the emulator for the write")
+			}
+		}
+
+		// Translate the line and send the resulting protocol commands in
+		// order, stopping at the first that fails: "g $0600" must not
+		// resume if setting PC was rejected.
+		parser := atticprotocol.NewCommandParser()
+		for _, text := range translateToProtocol(line, mode, opts.atascii) {
+			// Host paths get their leading "~" expanded first, since the
+			// server takes paths literally.
+			text, err = expandCommandPaths(text)
+			if err != nil {
+				results.record(atticprotocol.Response{}, err)
+				printError(err.Error())
+				break
+			}
+			cmd, parseErr := parser.Parse(text)
+
+			// Slow commands such as state save get a spinner while they
+			// run.
+			var stopProgress func()
+			if editor.IsInteractive() && !opts.quiet && parseErr == nil && commandShowsProgress(client, cmd.Type) {
+				stopProgress = startProgress(os.Stderr, text)
+			}
+
+			// SendRawWithTimeout wraps the command as "CMD:<command>\n"
+			// and waits for a response from the server.
+			resp, err := client.SendRawWithTimeout(text, commandTimeout(client, text))
+			if stopProgress != nil {
+				stopProgress()
 			}
-		} else {
-			fmt.Fprintf(os.Stderr, "Error: %s\n", resp.Data)
+			if err == nil && parseErr == nil {
+				state.noteCommand(cmd.Type, resp)
+			}
+			results.record(resp, err)
+			if err != nil {
+				printError(err.Error())
+				break
+			}
+			recall.record(resp)
+
+			if parseErr != nil || !printHexDumpResponse(cmd, resp, opts.atascii) {
+				printResponse(resp)
+			}
+			if resp.IsError() {
+				break
+			}
+		}
+		completer.noteCommand(mode, line) // cd, delete, ... make the cached listing stale
+		if autoPaused && opts.autoPause == autoPauseResume {
+			resumeAfterWrite(client, &state)
+		}
+	}
+}
+
+// runWhereCommand handles ".where": it reads PC from the registers and
+// prints the instruction there, annotated with the other register values.
+// A running emulator is reported instead, since its PC is already stale.
+func runWhereCommand(client *atticprotocol.Client, opts replOptions) {
+	if opts.dryRun {
+		fmt.Println("CMD:" + atticprotocol.NewStatusCommand().Format())
+		fmt.Println("CMD:" + atticprotocol.NewRegistersCommand(nil).Format())
+		fmt.Println("CMD:disassemble $<PC> 1")
+		return
+	}
+	if !client.IsConnected() {
+		printError("not connected (use .connect <socket>)")
+		return
+	}
+
+	resp, err := client.Send(atticprotocol.NewStatusCommand())
+	if err != nil {
+		printError(err.Error())
+		return
+	}
+	if status, err := resp.AsStatus(); err == nil && status.Running {
+		fmt.Println("Emulator is running (use pause first)")
+		return
+	}
+
+	resp, err = client.Send(atticprotocol.NewRegistersCommand(nil))
+	if err == nil {
+		err = resp.Err()
+	}
+	if err != nil {
+		printError(err.Error())
+		return
+	}
+	regs, err := resp.AsRegisters()
+	if err != nil {
+		printError(err.Error())
+		return
+	}
+
+	pc := regs.PC
+	lines := 1
+	resp, err = client.Send(atticprotocol.NewDisassembleCommand(&pc, &lines))
+	if err == nil {
+		err = resp.Err()
+	}
+	if err != nil {
+		printError(err.Error())
+		return
+	}
+	listing, err := resp.AsDisassembly()
+	if err != nil || len(listing) == 0 {
+		printError(fmt.Sprintf("no instruction at $%04X", pc))
+		return
+	}
+
+	insn := listing[0]
+	byteStrings := make([]string, len(insn.Bytes))
+	for i, b := range insn.Bytes {
+		byteStrings[i] = fmt.Sprintf("%02X", b)
+	}
+	fmt.Printf("$%04X  %-8s  %-14s ; A=$%02X X=$%02X Y=$%02X S=$%02X P=$%02X\n",
+		insn.Address, strings.Join(byteStrings, " "), insn.Instruction,
+		regs.A, regs.X, regs.Y, regs.S, regs.P)
+}
+
+// sendCommand sends a typed protocol command and prints the response. In
+// dry-run mode the formatted command is printed instead.
+func sendCommand(client *atticprotocol.Client, cmd atticprotocol.Command, opts replOptions) {
+	if opts.dryRun {
+		fmt.Println("CMD:" + cmd.Format())
+		return
+	}
+	resp, err := client.Send(cmd)
+	if err != nil {
+		printError(err.Error())
+		return
+	}
+	printResponse(resp)
+}
+
+// commandTimeout returns how long to wait for the protocol command text:
+// the client's timeout for its type, so slow commands such as state save
+// get longer, or CommandTimeout if it doesn't parse.
+func commandTimeout(client *atticprotocol.Client, text string) time.Duration {
+	cmd, err := atticprotocol.NewCommandParser().Parse(text)
+	if err != nil {
+		return atticprotocol.CommandTimeout
+	}
+	return client.CommandTimeoutFor(cmd.Type)
+}
+
+// printHexDumpResponse prints the response to a memory read as a hex
+// dump, with an ATASCII gutter when atascii is set. It returns false,
+// printing nothing, for any other command or response.
+func printHexDumpResponse(cmd atticprotocol.Command, resp atticprotocol.Response, atascii bool) bool {
+	if cmd.Type != atticprotocol.CmdRead {
+		return false
+	}
+	data, err := resp.AsBytes()
+	if err != nil || len(data) == 0 {
+		return false
+	}
+	dump := atticprotocol.FormatHexDump(cmd.Address, data)
+	if atascii {
+		dump = atticprotocol.FormatHexDumpATASCII(cmd.Address, data)
+	}
+	printPaged(displayText(strings.TrimSuffix(dump, "\n")))
+	return true
+}
+
+// GO CONCEPT: Protocol Separator Handling
+// ----------------------------------------
+// The CLI text protocol uses ASCII Record Separator (0x1E, \x1E)
+// to encode multiple lines in a single response. We replace them
+// with actual newlines for display. This avoids the complexity of
+// a streaming protocol while still supporting multi-line output
+// like disassembly listings and memory dumps. Response.Text does the
+// replacement, honoring a separator the server negotiated instead.
+//
+// Compare with Swift: Swift uses the same approach:
+//   output.replacingOccurrences(of: "\u{1E}", with: "\n")
+//
+// Compare with Python: Python string replacement:
+//   output.replace("\x1e", "\n")
+
+// printResponse displays a server response, expanding multi-line
+// separators. Long output is paged when paging is enabled and numbered
+// when line numbers are on. Error responses are written to stderr.
+func printResponse(resp atticprotocol.Response) {
+	if resp.IsOK() {
+		if resp.Data != "" {
+			printPaged(displayText(resp.Text()))
 		}
+	} else {
+		printError(resp.Data)
 	}
 }
@@ -156,6 +156,13 @@ func TestREPLModePromptsContainModeName(t *testing.T) {
 // `concurrent.futures.ThreadPoolExecutor` is a higher-level alternative.
 func captureREPL(t *testing.T, input string, handler func(cmd string) string) string {
 	t.Helper()
+	return captureREPLWithOptions(t, input, handler, replOptions{})
+}
+
+// captureREPLWithOptions is like captureREPL but runs the REPL with the
+// given options (e.g. dry-run mode).
+func captureREPLWithOptions(t *testing.T, input string, handler func(cmd string) string, opts replOptions) string {
+	t.Helper()
 
 	// Start mock server.
 	ms := startMockServer(t, handler)
@@ -239,7 +246,7 @@ func captureREPL(t *testing.T, input string, handler func(cmd string) string) st
 	wg.Add(1)
 	go func() {
 		defer wg.Done()
-		runREPL(client, editor, false)
+		runREPL(client, editor, opts)
 		editor.Close()
 		// Close stdout writer so the reader goroutine gets EOF.
 		stdoutWriter.Close()
@@ -348,7 +355,7 @@ func TestREPLSendsCommandToServer(t *testing.T) {
 		}
 	}
 
-	input := "status\n.quit\n"
+	input := ".monitor\nstatus\n.quit\n"
 	output := captureREPL(t, input, handler)
 
 	if !strings.Contains(output, "running PC=$E477") {
@@ -356,6 +363,61 @@ func TestREPLSendsCommandToServer(t *testing.T) {
 	}
 }
 
+// TestREPLSendsTranslatedCommands verifies that live sessions put the
+// translated protocol commands on the wire, not the line as typed, and
+// that a multi-command translation stops at the first error.
+func TestREPLSendsTranslatedCommands(t *testing.T) {
+	var mu sync.Mutex
+	var received []string
+	handler := func(cmd string) string {
+		if cmd == "ping" {
+			return "OK:pong\n"
+		}
+		mu.Lock()
+		received = append(received, cmd)
+		mu.Unlock()
+		if cmd == "registers pc=$ZZZZ" {
+			return "ERR:Invalid value '$ZZZZ'\n"
+		}
+		return "OK:done\n"
+	}
+
+	_ = captureREPL(t, ".monitor\ng $0600\nm $0600 4\ng $ZZZZ\n.basic\n10 PRINT \"HI\"\nfre\n.quit\n", handler)
+
+	mu.Lock()
+	defer mu.Unlock()
+	want := []string{
+		"registers pc=$0600",
+		"resume",
+		"read $0600 4",
+		"registers pc=$ZZZZ",
+		`inject keys 10\sPRINT\s"HI"\n`,
+		"basic fre",
+	}
+	if strings.Join(received, "\n") != strings.Join(want, "\n") {
+		t.Errorf("server received %q, want %q", received, want)
+	}
+}
+
+// TestREPLUnknownDotCommand verifies that an unknown dot-command is
+// rejected instead of being typed into the Atari or sent to the server,
+// while the forwarded dot-commands still reach the server.
+func TestREPLUnknownDotCommand(t *testing.T) {
+	input := ".foo\n.state\n.monitor\n.bogus\n.status\n.state save /tmp/s.attic\n.quit\n"
+	output := captureREPLWithOptions(t, input, nil, replOptions{dryRun: true})
+
+	for _, bad := range []string{"inject keys", "CMD:.bogus", "CMD:.state"} {
+		if strings.Contains(output, bad) {
+			t.Errorf("unknown dot-command was sent as %q, got:\n%s", bad, output)
+		}
+	}
+	for _, want := range []string{"CMD:status\n", "CMD:state save /tmp/s.attic\n"} {
+		if !strings.Contains(output, want) {
+			t.Errorf("expected %q, got:\n%s", want, output)
+		}
+	}
+}
+
 // TestREPLMultiLineResponse verifies that multi-line responses (using
 // the Record Separator character) are properly expanded to separate lines.
 func TestREPLMultiLineResponse(t *testing.T) {
@@ -371,7 +433,7 @@ func TestREPLMultiLineResponse(t *testing.T) {
 		}
 	}
 
-	input := "disassemble\n.quit\n"
+	input := ".monitor\ndisassemble\n.quit\n"
 	output := captureREPL(t, input, handler)
 
 	if !strings.Contains(output, "LDA #$00") {
@@ -423,7 +485,7 @@ func TestREPLServerErrorResponse(t *testing.T) {
 		stderrOutput = string(data)
 	}()
 
-	input := "badcmd\n.quit\n"
+	input := ".monitor\nbadcmd\n.quit\n"
 	_ = captureREPL(t, input, handler)
 
 	stderrWriter.Close()
@@ -940,3 +1002,280 @@ func TestREPLDotCommandsNotSentToServer(t *testing.T) {
 		t.Errorf("dot-commands should not be sent to server, but got: %v", nonPingCmds)
 	}
 }
+
+// TestREPLDryRunMonitorGo verifies that dry-run mode prints the translated
+// protocol commands for "g $0600" (two commands) without sending them.
+func TestREPLDryRunMonitorGo(t *testing.T) {
+	var mu sync.Mutex
+	nonPingCmds := []string{}
+
+	handler := func(cmd string) string {
+		mu.Lock()
+		if cmd != "ping" {
+			nonPingCmds = append(nonPingCmds, cmd)
+		}
+		mu.Unlock()
+		return "OK:pong\n"
+	}
+
+	input := ".monitor\ng $0600\n.quit\n"
+	output := captureREPLWithOptions(t, input, handler, replOptions{dryRun: true})
+
+	if !strings.Contains(output, "CMD:registers pc=$0600") {
+		t.Errorf("expected registers command in output, got:\n%s", output)
+	}
+	if !strings.Contains(output, "CMD:resume") {
+		t.Errorf("expected resume command in output, got:\n%s", output)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if len(nonPingCmds) > 0 {
+		t.Errorf("dry-run should not send commands to server, but got: %v", nonPingCmds)
+	}
+}
+
+// TestREPLDryRunBasicEscaping verifies that a BASIC program line is shown
+// as an escaped "inject keys" command in dry-run mode.
+func TestREPLDryRunBasicEscaping(t *testing.T) {
+	input := "10 PRINT \"HI\"\n.quit\n"
+	output := captureREPLWithOptions(t, input, nil, replOptions{dryRun: true})
+
+	want := `CMD:inject keys 10\sPRINT\s"HI"\n`
+	if !strings.Contains(output, want) {
+		t.Errorf("expected %q in output, got:\n%s", want, output)
+	}
+}
+
+// TestREPLSymbolResolution verifies that a symbol defined with .sym is
+// replaced by its address in monitor-mode commands sent to the server.
+func TestREPLSymbolResolution(t *testing.T) {
+	var mu sync.Mutex
+	var received []string
+
+	handler := func(cmd string) string {
+		if cmd == "ping" {
+			return "OK:pong\n"
+		}
+		mu.Lock()
+		received = append(received, cmd)
+		mu.Unlock()
+		return "OK:done\n"
+	}
+
+	input := ".monitor\n.sym MAIN $2000\nbreakpoint set MAIN\n.quit\n"
+	output := captureREPL(t, input, handler)
+
+	if !strings.Contains(output, "MAIN = $2000") {
+		t.Errorf("expected symbol confirmation in output, got:\n%s", output)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	found := false
+	for _, cmd := range received {
+		if cmd == "breakpoint set $2000" {
+			found = true
+		}
+	}
+	if !found {
+		t.Errorf("expected 'breakpoint set $2000' to be sent, got: %v", received)
+	}
+}
+
+// TestREPLSymbolDryRun verifies symbol resolution before translation.
+func TestREPLSymbolDryRun(t *testing.T) {
+	input := ".sym START $0600\n.monitor\nd START\n.quit\n"
+	output := captureREPLWithOptions(t, input, nil, replOptions{dryRun: true})
+
+	if !strings.Contains(output, "CMD:disassemble $0600") {
+		t.Errorf("expected resolved disassemble command, got:\n%s", output)
+	}
+}
+
+// TestREPLScreenshotAutoName verifies that .screenshot without a path sends
+// a generated path inside the configured screenshot directory.
+func TestREPLScreenshotAutoName(t *testing.T) {
+	input := ".screenshot\n.screenshot\n.quit\n"
+	opts := replOptions{dryRun: true, screenshotDir: "/tmp/shots"}
+	output := captureREPLWithOptions(t, input, nil, opts)
+
+	if !strings.Contains(output, "CMD:screenshot /tmp/shots/Attic-") {
+		t.Errorf("expected generated screenshot path, got:\n%s", output)
+	}
+	if !strings.Contains(output, "-2.png") {
+		t.Errorf("expected second screenshot to use sequence 2, got:\n%s", output)
+	}
+}
+
+// TestREPLConnectSecondServer verifies that .connect switches to another
+// server mid-session and that .disconnect leaves the REPL running.
+func TestREPLConnectSecondServer(t *testing.T) {
+	second := startMockServer(t, func(cmd string) string {
+		if cmd == "ping" {
+			return "OK:pong\n"
+		}
+		return "OK:second " + cmd + "\n"
+	})
+	first := func(cmd string) string {
+		if cmd == "ping" {
+			return "OK:pong\n"
+		}
+		return "OK:first " + cmd + "\n"
+	}
+
+	input := ".monitor\n" +
+		"status\n" +
+		".connect " + second.socketPath + "\n" +
+		"status\n" +
+		".disconnect\n" +
+		"status\n" +
+		".monitor\n" +
+		".quit\n"
+	output := captureREPL(t, input, first)
+
+	for _, want := range []string{
+		"first status",
+		"Connected to " + second.socketPath,
+		"second status",
+		"Disconnected from " + second.socketPath,
+		"Switched to Monitor mode",
+	} {
+		if !strings.Contains(output, want) {
+			t.Errorf("expected %q in output, got:\n%s", want, output)
+		}
+	}
+	if strings.Count(output, "second status") != 1 {
+		t.Errorf("commands should not be sent after .disconnect, got:\n%s", output)
+	}
+}
+
+// whereHandler simulates a server for .where. The emulator reports running
+// or paused depending on running.
+func whereHandler(running bool) func(cmd string) string {
+	return func(cmd string) string {
+		switch cmd {
+		case "ping":
+			return "OK:pong\n"
+		case "status":
+			if running {
+				return "OK:status running PC=$E477 BP=(none)\n"
+			}
+			return "OK:status paused PC=$0602 BP=(none)\n"
+		case "registers":
+			return "OK:A=$4F X=$03 Y=$00 S=$FD P=$30 PC=$0602\n"
+		case "disassemble $0602 1":
+			return "OK:$0602  8D 00 D4  STA DMACTL\n"
+		}
+		return "ERR:unexpected " + cmd + "\n"
+	}
+}
+
+// TestREPLWhere verifies that .where reads PC from the registers and
+// prints the instruction at it.
+func TestREPLWhere(t *testing.T) {
+	output := captureREPL(t, ".where\n.quit\n", whereHandler(false))
+
+	want := "$0602  8D 00 D4  STA DMACTL     ; A=$4F X=$03 Y=$00 S=$FD P=$30"
+	if !strings.Contains(output, want) {
+		t.Errorf("expected %q in output, got:\n%s", want, output)
+	}
+}
+
+// TestREPLWhereRunning verifies that .where reports a running emulator
+// instead of disassembling.
+func TestREPLWhereRunning(t *testing.T) {
+	output := captureREPL(t, ".where\n.quit\n", whereHandler(true))
+
+	if !strings.Contains(output, "Emulator is running") || strings.Contains(output, "STA DMACTL") {
+		t.Errorf("expected running notice only, got:\n%s", output)
+	}
+}
+
+// TestREPLReadHexDump verifies that memory reads are shown as a hex dump
+// and that other responses are printed unchanged.
+func TestREPLReadHexDump(t *testing.T) {
+	output := captureREPL(t, ".monitor\nm $0600 5\nread $0700 2\nstatus\n.quit\n", func(cmd string) string {
+		switch cmd {
+		case "ping":
+			return "OK:pong\n"
+		case "read $0600 5":
+			return "OK:data 48,45,4C,4C,4F\n"
+		case "read $0700 2":
+			return "OK:data A9,00\n"
+		case "status":
+			return "OK:status paused PC=$0600\n"
+		}
+		return "ERR:unexpected " + cmd + "\n"
+	})
+
+	for _, want := range []string{
+		"0600: 48 45 4C 4C 4F" + strings.Repeat("   ", 11) + " |HELLO|",
+		"0700: A9 00",
+		"status paused PC=$0600",
+	} {
+		if !strings.Contains(output, want) {
+			t.Errorf("expected %q in output, got:\n%s", want, output)
+		}
+	}
+	if strings.Contains(output, "data 48") {
+		t.Errorf("raw read response should not be printed, got:\n%s", output)
+	}
+}
+
+// TestCommandTimeout verifies that slow commands get their longer timeout
+// and unparseable ones the default.
+func TestCommandTimeout(t *testing.T) {
+	client := atticprotocol.NewClient()
+	tests := []struct {
+		text string
+		want time.Duration
+	}{
+		{"state save /tmp/game.state", atticprotocol.LongCommandTimeout},
+		{"screenshot", atticprotocol.LongCommandTimeout},
+		{"registers", atticprotocol.CommandTimeout},
+		{"bogus", atticprotocol.CommandTimeout},
+	}
+	for _, tt := range tests {
+		if got := commandTimeout(client, tt.text); got != tt.want {
+			t.Errorf("commandTimeout(%q) = %v, want %v", tt.text, got, tt.want)
+		}
+	}
+
+	client.SetCommandTimeout(atticprotocol.CmdRegisters, time.Minute)
+	if got := commandTimeout(client, "registers"); got != time.Minute {
+		t.Errorf("with an override, commandTimeout() = %v, want 1m", got)
+	}
+}
+
+// TestREPLEcho verifies that --echo prints each piped command before its
+// response, so the output reads as a transcript.
+func TestREPLEcho(t *testing.T) {
+	handler := func(cmd string) string {
+		switch cmd {
+		case "ping":
+			return "OK:pong\n"
+		case "status":
+			return "OK:status paused PC=$E477\n"
+		case "drives":
+			return "OK:D1: (none)\n"
+		}
+		return "ERR:unexpected " + cmd + "\n"
+	}
+
+	output := captureREPLWithOptions(t, ".monitor\nstatus\ndrives\n.quit\n", handler, replOptions{echo: true})
+	want := []string{"+ status", "status paused PC=$E477", "+ drives", "D1: (none)", "+ .quit"}
+	pos := 0
+	for _, w := range want {
+		i := strings.Index(output[pos:], w)
+		if i < 0 {
+			t.Fatalf("expected %q after offset %d in output:\n%s", w, pos, output)
+		}
+		pos += i + len(w)
+	}
+
+	output = captureREPL(t, ".monitor\nstatus\n.quit\n", handler)
+	if strings.Contains(output, "+ status") {
+		t.Errorf("commands should not be echoed without --echo, got:\n%s", output)
+	}
+}
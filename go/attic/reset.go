@@ -0,0 +1,95 @@
+// =============================================================================
+// reset.go - Reset With Optional Wait (.reset / .warmstart)
+// =============================================================================
+//
+// ".reset" cold-starts the emulator and ".warmstart" warm-starts it. Both
+// return as soon as the server accepts the command, while the machine may
+// still be in the middle of its reset. With --wait the CLI instead polls
+// "status" until the emulator reports running again, so that commands
+// typed next don't race against the reset:
+//
+//	.reset --wait
+//	.warmstart --wait
+//
+// =============================================================================
+
+package main
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/attic/atticprotocol"
+)
+
+// resetWaitTimeout is how long ".reset --wait" waits for the emulator to
+// report running. Tests shorten it.
+var resetWaitTimeout = 5 * time.Second
+
+// resetPollInterval is the delay between status polls while waiting.
+const resetPollInterval = 50 * time.Millisecond
+
+// waitForRunning polls status until the emulator reports running, returning
+// the final status. It gives up after timeout.
+func waitForRunning(client *atticprotocol.Client, timeout time.Duration) (atticprotocol.Status, error) {
+	deadline := time.Now().Add(timeout)
+	for {
+		resp, err := client.Send(atticprotocol.NewStatusCommand())
+		if err != nil {
+			return atticprotocol.Status{}, err
+		}
+		// A status that doesn't parse yet (or an error response) just means
+		// the reset hasn't finished; keep polling.
+		if status, err := resp.AsStatus(); err == nil && status.Running {
+			return status, nil
+		}
+		if time.Now().After(deadline) {
+			return atticprotocol.Status{}, fmt.Errorf("emulator not running after %v", timeout)
+		}
+		time.Sleep(resetPollInterval)
+	}
+}
+
+// runResetCommand handles ".reset [--wait]" and ".warmstart [--wait]".
+func runResetCommand(client *atticprotocol.Client, cold bool, args string, opts replOptions) {
+	wait := false
+	switch strings.TrimSpace(args) {
+	case "":
+	case "--wait":
+		wait = true
+	default:
+		if cold {
+			printError("usage: .reset [--wait]")
+		} else {
+			printError("usage: .warmstart [--wait]")
+		}
+		return
+	}
+
+	cmd := atticprotocol.NewResetCommand(cold)
+	if opts.dryRun {
+		fmt.Println("CMD:" + cmd.Format())
+		if wait {
+			fmt.Println("CMD:" + atticprotocol.NewStatusCommand().Format())
+		}
+		return
+	}
+
+	resp, err := client.Send(cmd)
+	if err != nil {
+		printError(err.Error())
+		return
+	}
+	if !wait || resp.IsError() {
+		printResponse(resp)
+		return
+	}
+
+	status, err := waitForRunning(client, resetWaitTimeout)
+	if err != nil {
+		printError(err.Error())
+		return
+	}
+	fmt.Printf("Reset complete, running at $%04X\n", status.PC)
+}
@@ -0,0 +1,79 @@
+// =============================================================================
+// reset_test.go - Tests for Reset With Wait (reset.go)
+// =============================================================================
+
+package main
+
+import (
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// resettingHandler simulates a server whose emulator reports paused for
+// the first few status polls after a reset, then running again.
+func resettingHandler(pollsUntilRunning int) func(cmd string) string {
+	var mu sync.Mutex
+	polls := 0
+	return func(cmd string) string {
+		mu.Lock()
+		defer mu.Unlock()
+		switch cmd {
+		case "ping":
+			return "OK:pong\n"
+		case "reset cold", "reset warm":
+			polls = 0
+			return "OK:" + cmd + "\n"
+		case "status":
+			polls++
+			if polls <= pollsUntilRunning {
+				return "OK:status paused PC=$0000 BP=(none)\n"
+			}
+			return "OK:status running PC=$E477 BP=(none)\n"
+		}
+		return "OK:\n"
+	}
+}
+
+// TestREPLResetWait verifies that .reset --wait returns once the emulator
+// is running again.
+func TestREPLResetWait(t *testing.T) {
+	output := captureREPL(t, ".reset --wait\n.quit\n", resettingHandler(3))
+
+	if !strings.Contains(output, "Reset complete, running at $E477") {
+		t.Errorf("expected reset to complete, got:\n%s", output)
+	}
+}
+
+// TestREPLResetWaitTimeout verifies that waiting gives up when the
+// emulator never reports running.
+func TestREPLResetWaitTimeout(t *testing.T) {
+	oldTimeout := resetWaitTimeout
+	resetWaitTimeout = 100 * time.Millisecond
+	t.Cleanup(func() { resetWaitTimeout = oldTimeout })
+
+	output := captureREPL(t, ".warmstart --wait\n.quit\n", resettingHandler(1000))
+
+	if strings.Contains(output, "Reset complete") {
+		t.Errorf("reset should time out, got:\n%s", output)
+	}
+}
+
+// TestREPLResetWithoutWait verifies that plain .reset doesn't poll.
+func TestREPLResetWithoutWait(t *testing.T) {
+	output := captureREPL(t, ".reset\n.quit\n", resettingHandler(0))
+
+	if !strings.Contains(output, "reset cold") || strings.Contains(output, "Reset complete") {
+		t.Errorf("expected plain reset response, got:\n%s", output)
+	}
+}
+
+// TestREPLResetWaitDryRun verifies the commands .reset --wait would send.
+func TestREPLResetWaitDryRun(t *testing.T) {
+	output := captureREPLWithOptions(t, ".reset --wait\n.quit\n", nil, replOptions{dryRun: true})
+
+	if !strings.Contains(output, "CMD:reset cold\n") || !strings.Contains(output, "CMD:status") {
+		t.Errorf("expected reset and status commands, got:\n%s", output)
+	}
+}
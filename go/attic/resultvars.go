@@ -0,0 +1,58 @@
+// =============================================================================
+// resultvars.go - Previous-Result Variables ($_ and $?)
+// =============================================================================
+//
+// For light scripting, two tokens in a command line are replaced with
+// details of the previous server response before the line is handled:
+//
+//	$_   the first word of the previous response, e.g. "$0600" after
+//	     "OK:$0600  A9 00     LDA #$00"
+//	$?   "OK" or "ERR", whether the previous command succeeded
+//
+// Nothing else is expanded, and until the first response arrives the
+// tokens are left as typed.
+//
+// =============================================================================
+
+package main
+
+import (
+	"strings"
+
+	"github.com/attic/atticprotocol"
+)
+
+// resultVars holds the values of $_ and $? for the next command line.
+type resultVars struct {
+	first  string // $_
+	status string // $?
+	set    bool
+}
+
+// record updates the variables from a command's response. A failed send
+// counts as an error with no result.
+func (v *resultVars) record(resp atticprotocol.Response, err error) {
+	v.set = true
+	if err != nil {
+		v.first, v.status = "", "ERR"
+		return
+	}
+	v.first = ""
+	if lines := resp.Lines(); len(lines) > 0 {
+		if fields := strings.Fields(lines[0]); len(fields) > 0 {
+			v.first = fields[0]
+		}
+	}
+	v.status = "OK"
+	if resp.IsError() {
+		v.status = "ERR"
+	}
+}
+
+// expand replaces $_ and $? in line.
+func (v *resultVars) expand(line string) string {
+	if !v.set {
+		return line
+	}
+	return strings.NewReplacer("$_", v.first, "$?", v.status).Replace(line)
+}
@@ -0,0 +1,60 @@
+// =============================================================================
+// resultvars_test.go - Tests for Previous-Result Variables (resultvars.go)
+// =============================================================================
+
+package main
+
+import (
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/attic/atticprotocol"
+)
+
+// TestREPLResultVars verifies that $_ and $? are replaced with the first
+// word and status of the previous response.
+func TestREPLResultVars(t *testing.T) {
+	var mu sync.Mutex
+	var received []string
+	_ = captureREPL(t, ".monitor\nwhere\nd $_ 4\nbogus\nstatus $?\n.quit\n", func(cmd string) string {
+		if cmd == "ping" {
+			return "OK:pong\n"
+		}
+		mu.Lock()
+		received = append(received, cmd)
+		mu.Unlock()
+		switch {
+		case cmd == "where":
+			return "OK:$E477  A9 00     LDA #$00\n"
+		case strings.HasPrefix(cmd, "disassemble "), strings.HasPrefix(cmd, "status"):
+			return "OK:done\n"
+		}
+		return "ERR:unexpected " + cmd + "\n"
+	})
+
+	mu.Lock()
+	defer mu.Unlock()
+	want := []string{"where", "disassemble $E477 4", "bogus", "status ERR"}
+	if strings.Join(received, "\n") != strings.Join(want, "\n") {
+		t.Errorf("server received %q, want %q", received, want)
+	}
+}
+
+func TestResultVarsExpand(t *testing.T) {
+	var v resultVars
+	if got := v.expand("d $_"); got != "d $_" {
+		t.Errorf("before any response, expand() = %q, want it unchanged", got)
+	}
+
+	v.record(atticprotocol.NewMultiLineResponse([]string{"$0600  A9 00  LDA #$00", "$0602  60  RTS"}), nil)
+	if got := v.expand("d $_ $? $0600"); got != "d $0600 OK $0600" {
+		t.Errorf("expand() = %q", got)
+	}
+
+	v.record(atticprotocol.Response{}, errors.New("timeout"))
+	if got := v.expand("[$_] $?"); got != "[] ERR" {
+		t.Errorf("after a failed send, expand() = %q", got)
+	}
+}
@@ -0,0 +1,123 @@
+// =============================================================================
+// screen.go - Reading the Text Screen (.screen)
+// =============================================================================
+//
+// ".screen" prints the text on the emulator's screen. The server can only
+// read text in GRAPHICS 0, so the CLI asks for the current mode first and
+// explains the problem instead of showing a screen of garbage:
+//
+//	.screen [atascii]
+//	.screen save <path>
+//
+// "save" writes the screen to a host file for documentation or diffing, as
+// 24 lines of 40 columns with short lines padded. In ATASCII mode (--atascii)
+// graphics characters are saved as Unicode glyphs and inverse video as ANSI
+// reverse video, which is not counted as part of the width.
+//
+// A server too old to answer "gfx" gets the screen request anyway.
+//
+// =============================================================================
+
+package main
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/attic/atticprotocol"
+)
+
+// Size of the GRAPHICS 0 text screen.
+const (
+	screenColumns = 40
+	screenRows    = 24
+)
+
+// screenWidth returns the number of columns line takes on screen, not
+// counting ANSI escape sequences.
+func screenWidth(line string) int {
+	width := 0
+	for len(line) > 0 {
+		if strings.HasPrefix(line, "\x1b[") {
+			if end := strings.IndexByte(line, 'm'); end >= 0 {
+				line = line[end+1:]
+				continue
+			}
+		}
+		_, size := utf8.DecodeRuneInString(line)
+		line = line[size:]
+		width++
+	}
+	return width
+}
+
+// formatScreenFile lays out screen lines as a screenColumns by screenRows
+// text file, padding short lines and missing rows with spaces.
+func formatScreenFile(lines []string) string {
+	var sb strings.Builder
+	for row := 0; row < max(screenRows, len(lines)); row++ {
+		var line string
+		if row < len(lines) {
+			line = lines[row]
+		}
+		sb.WriteString(line)
+		sb.WriteString(strings.Repeat(" ", max(0, screenColumns-screenWidth(line))))
+		sb.WriteString("\n")
+	}
+	return sb.String()
+}
+
+// runScreenCommand handles ".screen [atascii]" and ".screen save <path>".
+func runScreenCommand(client *atticprotocol.Client, args string, opts replOptions) {
+	var atascii bool
+	var savePath string
+	fields := strings.Fields(args)
+	switch {
+	case len(fields) == 0:
+	case len(fields) == 1 && strings.EqualFold(fields[0], "atascii"):
+		atascii = true
+	case len(fields) == 2 && strings.EqualFold(fields[0], "save"):
+		atascii = opts.atascii
+		savePath = expandPath(fields[1])
+	default:
+		printError("usage: .screen [atascii] | .screen save <path>")
+		return
+	}
+	cmd := atticprotocol.NewScreenTextCommand(atascii)
+	if opts.dryRun {
+		fmt.Println("CMD:" + cmd.Format())
+		return
+	}
+	if !client.IsConnected() {
+		printError("not connected (use .connect <socket>)")
+		return
+	}
+
+	if resp, err := client.Send(atticprotocol.NewGraphicsModeCommand()); err == nil {
+		if mode, err := resp.AsGraphicsMode(); err == nil && mode != 0 {
+			printError(fmt.Sprintf("screen text needs GRAPHICS 0, but the display is in GRAPHICS %d", mode))
+			return
+		}
+	}
+	if savePath == "" {
+		sendCommand(client, cmd, opts)
+		return
+	}
+
+	resp, err := client.Send(cmd)
+	if err != nil {
+		printError(err.Error())
+		return
+	}
+	if err := resp.Err(); err != nil {
+		printError(err.Error())
+		return
+	}
+	if err := os.WriteFile(savePath, []byte(formatScreenFile(resp.Lines())), 0o644); err != nil {
+		printError(err.Error())
+		return
+	}
+	fmt.Printf("Saved screen to %s\n", savePath)
+}
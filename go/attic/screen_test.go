@@ -0,0 +1,131 @@
+// =============================================================================
+// screen_test.go - Tests for Reading the Text Screen (screen.go)
+// =============================================================================
+
+package main
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// screenHandler answers gfx with the given mode and records the commands
+// it receives.
+func screenHandler(mode string, mu *sync.Mutex, received *[]string) func(string) string {
+	return func(cmd string) string {
+		if cmd == "ping" {
+			return "OK:pong\n"
+		}
+		mu.Lock()
+		*received = append(*received, cmd)
+		mu.Unlock()
+		switch cmd {
+		case "gfx":
+			return "OK:gfx " + mode + "\n"
+		case "screen":
+			return "OK:READY\x1E\n"
+		case "screen atascii":
+			return "OK:\x1b[7mR\x1b[27mEADY \u2665\x1E\n"
+		}
+		return "ERR:unexpected " + cmd + "\n"
+	}
+}
+
+// TestREPLScreenTextMode verifies that the screen is read in GRAPHICS 0.
+func TestREPLScreenTextMode(t *testing.T) {
+	var mu sync.Mutex
+	var received []string
+	output := captureREPL(t, ".screen\n.quit\n", screenHandler("0", &mu, &received))
+
+	mu.Lock()
+	defer mu.Unlock()
+	if got := strings.Join(received, ","); got != "gfx,screen" {
+		t.Errorf("server received %q, want %q", got, "gfx,screen")
+	}
+	if !strings.Contains(output, "READY") {
+		t.Errorf("expected screen text, got:\n%s", output)
+	}
+}
+
+// TestREPLScreenGraphicsMode verifies that the screen is not read when the
+// display is not in a text mode.
+func TestREPLScreenGraphicsMode(t *testing.T) {
+	var mu sync.Mutex
+	var received []string
+
+	// printError writes to stderr, which captureREPL doesn't capture.
+	oldStderr := os.Stderr
+	stderrReader, stderrWriter, err := os.Pipe()
+	if err != nil {
+		t.Fatalf("failed to create stderr pipe: %v", err)
+	}
+	os.Stderr = stderrWriter
+	captureREPL(t, ".screen\n.quit\n", screenHandler("8", &mu, &received))
+	os.Stderr = oldStderr
+	stderrWriter.Close()
+	stderrBytes, _ := io.ReadAll(stderrReader)
+	stderr := string(stderrBytes)
+
+	mu.Lock()
+	defer mu.Unlock()
+	if got := strings.Join(received, ","); got != "gfx" {
+		t.Errorf("server received %q, want only %q", got, "gfx")
+	}
+	if !strings.Contains(stderr, "GRAPHICS 8") {
+		t.Errorf("expected a warning naming the mode, got:\n%s", stderr)
+	}
+}
+
+// TestREPLScreenSave verifies that .screen save writes a 40x24 file.
+func TestREPLScreenSave(t *testing.T) {
+	var mu sync.Mutex
+	var received []string
+	path := filepath.Join(t.TempDir(), "screen.txt")
+	output := captureREPL(t, ".screen save "+path+"\n.quit\n", screenHandler("0", &mu, &received))
+	if !strings.Contains(output, "Saved screen to "+path) {
+		t.Errorf("expected save confirmation, got:\n%s", output)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("screen file not written: %v", err)
+	}
+	lines := strings.Split(strings.TrimSuffix(string(data), "\n"), "\n")
+	if len(lines) != screenRows {
+		t.Fatalf("screen file has %d lines, want %d", len(lines), screenRows)
+	}
+	for i, line := range lines {
+		if len(line) != screenColumns {
+			t.Errorf("line %d is %d columns, want %d: %q", i+1, len(line), screenColumns, line)
+		}
+	}
+	if lines[0] != "READY"+strings.Repeat(" ", 35) {
+		t.Errorf("first line = %q", lines[0])
+	}
+}
+
+// TestREPLScreenSaveATASCII verifies that ATASCII mode saves glyphs and
+// reverse video without counting escape codes as columns.
+func TestREPLScreenSaveATASCII(t *testing.T) {
+	var mu sync.Mutex
+	var received []string
+	path := filepath.Join(t.TempDir(), "screen.txt")
+	captureREPLWithOptions(t, ".screen save "+path+"\n.quit\n", screenHandler("0", &mu, &received), replOptions{atascii: true})
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("screen file not written: %v", err)
+	}
+	first, _, _ := strings.Cut(string(data), "\n")
+	want := "\x1b[7mR\x1b[27mEADY \u2665" + strings.Repeat(" ", 33)
+	if first != want {
+		t.Errorf("first line = %q, want %q", first, want)
+	}
+	if screenWidth(first) != screenColumns {
+		t.Errorf("screenWidth() = %d, want %d", screenWidth(first), screenColumns)
+	}
+}
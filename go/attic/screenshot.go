@@ -0,0 +1,83 @@
+// =============================================================================
+// screenshot.go - Automatic Screenshot File Naming
+// =============================================================================
+//
+// When ".screenshot" is typed without a path, the CLI picks a file name
+// itself rather than letting every screenshot land on the same default
+// path. Names combine a timestamp with a per-session sequence number:
+//
+//	~/Desktop/Attic-20250102-150405-1.png
+//	~/Desktop/Attic-20250102-150405-2.png
+//
+// The sequence number keeps names unique even when several screenshots
+// are taken within the same second. The base directory can be changed
+// with the --screenshot-dir flag.
+//
+// =============================================================================
+
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// GO CONCEPT: Injectable Clocks for Testing
+// ------------------------------------------
+// Code that calls time.Now() directly is hard to test because the result
+// changes on every run. Storing the clock as a function field
+// (now func() time.Time) lets tests substitute a fixed time while
+// production code uses time.Now.
+//
+// Compare with Swift: Swift code often injects a `() -> Date` closure or a
+// Clock protocol for the same reason.
+//
+// Compare with Python: Python tests usually patch the clock instead:
+// `with freezegun.freeze_time("2025-01-02"): ...` or
+// `unittest.mock.patch("time.time")`.
+
+// screenshotTimestampFormat is the Go reference-time layout used in
+// generated screenshot names (YYYYMMDD-HHMMSS).
+const screenshotTimestampFormat = "20060102-150405"
+
+// screenshotNamer generates unique screenshot file paths for a session.
+type screenshotNamer struct {
+	// dir is the directory screenshots are written to.
+	dir string
+
+	// seq is the number of names generated so far in this session.
+	seq int
+
+	// now returns the current time. Tests replace it with a fixed clock.
+	now func() time.Time
+}
+
+// newScreenshotNamer creates a namer that writes into dir. An empty dir
+// selects the default ~/Desktop directory.
+func newScreenshotNamer(dir string) *screenshotNamer {
+	if dir == "" {
+		dir = defaultScreenshotDir()
+	}
+	dir = expandPath(dir)
+	return &screenshotNamer{dir: dir, now: time.Now}
+}
+
+// defaultScreenshotDir returns ~/Desktop, or the current directory if the
+// home directory cannot be determined.
+func defaultScreenshotDir() string {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "."
+	}
+	return filepath.Join(home, "Desktop")
+}
+
+// next returns the next screenshot path, e.g.
+// "<dir>/Attic-20250102-150405-3.png".
+func (n *screenshotNamer) next() string {
+	n.seq++
+	name := fmt.Sprintf("Attic-%s-%d.png", n.now().Format(screenshotTimestampFormat), n.seq)
+	return filepath.Join(n.dir, name)
+}
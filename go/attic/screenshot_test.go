@@ -0,0 +1,57 @@
+// =============================================================================
+// screenshot_test.go - Tests for Screenshot File Naming (screenshot.go)
+// =============================================================================
+
+package main
+
+import (
+	"path/filepath"
+	"regexp"
+	"testing"
+	"time"
+)
+
+// TestScreenshotNamerFormat verifies the generated path layout.
+func TestScreenshotNamerFormat(t *testing.T) {
+	namer := newScreenshotNamer("/tmp/shots")
+	namer.now = func() time.Time {
+		return time.Date(2025, 1, 2, 15, 4, 5, 0, time.Local)
+	}
+
+	got := namer.next()
+	want := filepath.Join("/tmp/shots", "Attic-20250102-150405-1.png")
+	if got != want {
+		t.Errorf("next() = %q, want %q", got, want)
+	}
+}
+
+// TestScreenshotNamerUnique verifies that names generated within the same
+// second differ by their sequence number.
+func TestScreenshotNamerUnique(t *testing.T) {
+	namer := newScreenshotNamer(t.TempDir())
+	fixed := time.Date(2025, 1, 2, 15, 4, 5, 0, time.Local)
+	namer.now = func() time.Time { return fixed }
+
+	seen := make(map[string]bool)
+	for i := 0; i < 10; i++ {
+		name := namer.next()
+		if seen[name] {
+			t.Fatalf("duplicate screenshot name %q", name)
+		}
+		seen[name] = true
+	}
+}
+
+// TestScreenshotNamerDefaultDir verifies the default directory and the
+// overall file name pattern.
+func TestScreenshotNamerDefaultDir(t *testing.T) {
+	namer := newScreenshotNamer("")
+	if namer.dir != defaultScreenshotDir() {
+		t.Errorf("dir = %q, want %q", namer.dir, defaultScreenshotDir())
+	}
+
+	pattern := regexp.MustCompile(`^Attic-\d{8}-\d{6}-1\.png$`)
+	if name := filepath.Base(namer.next()); !pattern.MatchString(name) {
+		t.Errorf("name %q does not match %s", name, pattern)
+	}
+}
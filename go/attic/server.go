@@ -3,8 +3,8 @@
 // =============================================================================
 //
 // Handles finding and launching the AtticServer process. The CLI can either
-// connect to an already-running server (discovered via socket files in /tmp)
-// or launch a new one as a subprocess.
+// connect to an already-running server (discovered via socket files in /tmp,
+// or in $ATTIC_SOCKET_DIR if set) or launch a new one as a subprocess.
 //
 // The server search order for the AtticServer executable:
 //   1. Same directory as the CLI binary
@@ -31,19 +31,10 @@ package main
 //   - "os/exec"       — running external commands (subprocess management)
 //   - "path/filepath" — cross-platform file path manipulation
 //   - "time"          — time operations (durations, timers, sleep)
-<<<<<<< HEAD
-<<<<<<< HEAD
-=======
->>>>>>> f994c51 (docs(go-cli): Add Python comparisons to all GO CONCEPT tutorial blocks)
 //
 // Compare with Python: Python's standard library is similarly organized:
 // `import os`, `import subprocess`, `from pathlib import Path`,
 // `import time`. Python sub-packages use dot notation for imports.
-<<<<<<< HEAD
-=======
->>>>>>> 810cc4d (docs(go-cli): Add comprehensive Go tutorial comments for learning)
-=======
->>>>>>> f994c51 (docs(go-cli): Add Python comparisons to all GO CONCEPT tutorial blocks)
 import (
 	"fmt"
 	"os"
@@ -58,20 +49,14 @@ import (
 // -----------------------------------------------
 // Go's time package uses a Duration type (which is really an int64 counting
 // nanoseconds). You create durations by multiplying a number with a time unit:
-<<<<<<< HEAD
 //
 //	4 * time.Second       → 4 seconds (4,000,000,000 nanoseconds)
 //	100 * time.Millisecond → 100 milliseconds
-=======
-//   4 * time.Second       → 4 seconds (4,000,000,000 nanoseconds)
-//   100 * time.Millisecond → 100 milliseconds
->>>>>>> 810cc4d (docs(go-cli): Add comprehensive Go tutorial comments for learning)
 //
 // This is type-safe: you can't accidentally mix seconds and milliseconds
 // because the compiler enforces the Duration type.
 //
 // Compare to Swift:
-<<<<<<< HEAD
 //
 //	Swift: TimeInterval is a Double (seconds), so 4.0 means 4 seconds
 //	Go:    time.Duration is an int64 (nanoseconds), written as 4 * time.Second
@@ -80,18 +65,6 @@ import (
 // `timedelta(seconds=4)`, `timedelta(milliseconds=100)`. These support
 // arithmetic and comparisons. Unlike Go's nanosecond int64, Python's
 // timedelta stores days, seconds, and microseconds internally.
-=======
-//   Swift: TimeInterval is a Double (seconds), so 4.0 means 4 seconds
-//   Go:    time.Duration is an int64 (nanoseconds), written as 4 * time.Second
-<<<<<<< HEAD
->>>>>>> 810cc4d (docs(go-cli): Add comprehensive Go tutorial comments for learning)
-=======
-//
-// Compare with Python: Python uses `datetime.timedelta` for durations:
-// `timedelta(seconds=4)`, `timedelta(milliseconds=100)`. These support
-// arithmetic and comparisons. Unlike Go's nanosecond int64, Python's
-// timedelta stores days, seconds, and microseconds internally.
->>>>>>> f994c51 (docs(go-cli): Add Python comparisons to all GO CONCEPT tutorial blocks)
 const (
 	// serverExecutableName is the name of the AtticServer binary.
 	serverExecutableName = "AtticServer"
@@ -118,19 +91,10 @@ const (
 // the named variables, but most Go developers prefer explicit returns for
 // clarity. We use named returns here purely for documentation.
 //
-<<<<<<< HEAD
-<<<<<<< HEAD
-=======
->>>>>>> f994c51 (docs(go-cli): Add Python comparisons to all GO CONCEPT tutorial blocks)
 // Compare with Python: Python has no named return values. Functions
 // return tuples, and callers unpack them. Type hints document the return:
 //   `def launch_server(silent: bool) -> tuple[str, int, Exception | None]:`
 //
-<<<<<<< HEAD
-=======
->>>>>>> 810cc4d (docs(go-cli): Add comprehensive Go tutorial comments for learning)
-=======
->>>>>>> f994c51 (docs(go-cli): Add Python comparisons to all GO CONCEPT tutorial blocks)
 // GO CONCEPT: The error Interface
 // --------------------------------
 // Go's error handling is based on a simple interface:
@@ -149,10 +113,6 @@ const (
 // Compare to Swift:
 //   Swift: func launch() throws -> String  (uses throw/catch)
 //   Go:    func launch() (string, error)    (returns error as a value)
-<<<<<<< HEAD
-<<<<<<< HEAD
-=======
->>>>>>> f994c51 (docs(go-cli): Add Python comparisons to all GO CONCEPT tutorial blocks)
 //
 // Compare with Python: Python uses an exception hierarchy:
 //   `class LaunchError(Exception): pass`
@@ -161,11 +121,6 @@ const (
 // `raise ... from err`. Python's approach is more implicit — you don't
 // check return values, but you can't see which exceptions a function
 // might raise without reading its source or documentation.
-<<<<<<< HEAD
-=======
->>>>>>> 810cc4d (docs(go-cli): Add comprehensive Go tutorial comments for learning)
-=======
->>>>>>> f994c51 (docs(go-cli): Add Python comparisons to all GO CONCEPT tutorial blocks)
 
 // launchServer launches a new AtticServer subprocess and waits for its socket
 // to become available. Returns the socket path, the server's PID, and any error.
@@ -193,20 +148,11 @@ func launchServer(silent bool) (socketPath string, pid int, err error) {
 	//
 	// append() returns a new slice header (possibly pointing to new memory
 	// if the old capacity was exceeded), so you must assign the result back.
-<<<<<<< HEAD
-<<<<<<< HEAD
-=======
->>>>>>> f994c51 (docs(go-cli): Add Python comparisons to all GO CONCEPT tutorial blocks)
 	//
 	// Compare with Python: Python lists work similarly: `cmd_args = []`,
 	// `cmd_args.append("--silent")`. Python lists grow automatically.
 	// Unlike Go, `append()` is a method that modifies the list in place
 	// (no need to reassign the result).
-<<<<<<< HEAD
-=======
->>>>>>> 810cc4d (docs(go-cli): Add comprehensive Go tutorial comments for learning)
-=======
->>>>>>> f994c51 (docs(go-cli): Add Python comparisons to all GO CONCEPT tutorial blocks)
 	cmdArgs := []string{}
 	if silent {
 		cmdArgs = append(cmdArgs, "--silent")
@@ -227,20 +173,11 @@ func launchServer(silent bool) (socketPath string, pid int, err error) {
 	//   - nil means output is discarded (like redirecting to /dev/null)
 	//   - os.Stdout would forward to our own stdout
 	//   - &bytes.Buffer would capture it in memory
-<<<<<<< HEAD
-<<<<<<< HEAD
-=======
->>>>>>> f994c51 (docs(go-cli): Add Python comparisons to all GO CONCEPT tutorial blocks)
 	//
 	// Compare with Python: `subprocess.Popen(["AtticServer", "--silent"])`
 	// for async launch, `subprocess.run(["AtticServer"])` for blocking.
 	// `proc.pid` gives the PID. Output control: `stdout=subprocess.DEVNULL`
 	// to discard, `stdout=subprocess.PIPE` to capture in memory.
-<<<<<<< HEAD
-=======
->>>>>>> 810cc4d (docs(go-cli): Add comprehensive Go tutorial comments for learning)
-=======
->>>>>>> f994c51 (docs(go-cli): Add Python comparisons to all GO CONCEPT tutorial blocks)
 
 	// Launch the server as a subprocess
 	cmd := exec.Command(exePath, cmdArgs...)
@@ -284,20 +221,11 @@ func launchServer(silent bool) (socketPath string, pid int, err error) {
 //
 // The pattern is verbose but explicit — you always know exactly what's
 // being returned on every path.
-<<<<<<< HEAD
-<<<<<<< HEAD
-=======
->>>>>>> f994c51 (docs(go-cli): Add Python comparisons to all GO CONCEPT tutorial blocks)
 //
 // Compare with Python: Python uses the same early-return pattern, but
 // often with exceptions instead of error returns:
 //   `path = find_executable()`
 //   `if path is None: raise FileNotFoundError(...)`
-<<<<<<< HEAD
-=======
->>>>>>> 810cc4d (docs(go-cli): Add comprehensive Go tutorial comments for learning)
-=======
->>>>>>> f994c51 (docs(go-cli): Add Python comparisons to all GO CONCEPT tutorial blocks)
 
 // findServerExecutable searches for the AtticServer binary in standard
 // locations. Returns the full path to the executable.
@@ -327,20 +255,11 @@ func findServerExecutable() (string, error) {
 	// extensively instead of builder patterns or constructor overloads.
 	//
 	// Compare to Swift: ["a", "b", "c"] (array literal)
-<<<<<<< HEAD
-<<<<<<< HEAD
-=======
->>>>>>> f994c51 (docs(go-cli): Add Python comparisons to all GO CONCEPT tutorial blocks)
 	//
 	// Compare with Python: Python list literals are identical in concept:
 	//   `common_paths = ["/usr/local/bin", "/opt/homebrew/bin",
 	//    os.path.join(home, ".local", "bin")]`
 	// Python also has tuple `(a, b)`, set `{a, b}`, and dict `{"key": val}`.
-<<<<<<< HEAD
-=======
->>>>>>> 810cc4d (docs(go-cli): Add comprehensive Go tutorial comments for learning)
-=======
->>>>>>> f994c51 (docs(go-cli): Add Python comparisons to all GO CONCEPT tutorial blocks)
 	commonPaths := []string{
 		"/usr/local/bin",
 		"/opt/homebrew/bin",
@@ -364,20 +283,11 @@ func findServerExecutable() (string, error) {
 	// Compare to Swift:
 	//   for dir in commonPaths { ... }           — value only (most common)
 	//   for (i, dir) in commonPaths.enumerated() — both index and value
-<<<<<<< HEAD
-<<<<<<< HEAD
-=======
->>>>>>> f994c51 (docs(go-cli): Add Python comparisons to all GO CONCEPT tutorial blocks)
 	//
 	// Compare with Python: `for dir in common_paths:` gives values directly
 	// (no index). For index+value: `for i, dir in enumerate(common_paths):`.
 	// The `_` convention for unused variables is the same:
 	// `for _, dir in enumerate(common_paths):`.
-<<<<<<< HEAD
-=======
->>>>>>> 810cc4d (docs(go-cli): Add comprehensive Go tutorial comments for learning)
-=======
->>>>>>> f994c51 (docs(go-cli): Add Python comparisons to all GO CONCEPT tutorial blocks)
 	for _, dir := range commonPaths {
 		candidate := filepath.Join(dir, serverExecutableName)
 		if isExecutable(candidate) {
@@ -391,8 +301,10 @@ func findServerExecutable() (string, error) {
 // waitForSocket polls for the server's Unix socket to appear after launch.
 // Returns the socket path once found, or an error if the timeout is reached.
 func waitForSocket(pid int) (string, error) {
-	// atticprotocol.SocketPath constructs the expected path: /tmp/attic-<PID>.sock
-	expectedPath := atticprotocol.SocketPath(pid)
+	// AtticServer, started without --socket-path, always creates
+	// /tmp/attic-<PID>.sock; it does not read $ATTIC_SOCKET_DIR, which
+	// only changes where discovery looks.
+	expectedPath := fmt.Sprintf("%s%d%s", atticprotocol.SocketPathPrefix, pid, atticprotocol.SocketPathSuffix)
 
 	// time.Now().Add(duration) computes a future time point.
 	// We poll in a loop until we either find the socket or hit the deadline.
@@ -408,19 +320,10 @@ func waitForSocket(pid int) (string, error) {
 	// You can't use < or > with time.Time (Go doesn't have operator
 	// overloading). This is different from Swift where Date conforms to
 	// Comparable and you can write "date1 < date2".
-<<<<<<< HEAD
-<<<<<<< HEAD
-=======
->>>>>>> f994c51 (docs(go-cli): Add Python comparisons to all GO CONCEPT tutorial blocks)
 	//
 	// Compare with Python: Python's `datetime` objects support comparison
 	// operators directly: `now < deadline`. This is more natural than Go's
 	// method-based approach. `time.sleep(0.1)` takes seconds as a float.
-<<<<<<< HEAD
-=======
->>>>>>> 810cc4d (docs(go-cli): Add comprehensive Go tutorial comments for learning)
-=======
->>>>>>> f994c51 (docs(go-cli): Add Python comparisons to all GO CONCEPT tutorial blocks)
 	for time.Now().Before(deadline) {
 		// os.Stat checks if a file exists and returns its metadata.
 		// If the error is nil, the file exists.
@@ -456,20 +359,11 @@ func waitForSocket(pid int) (string, error) {
 //
 // Go uses the 0-prefix for octal literals (same as C). Go 1.13+ also
 // supports 0o111 for clarity, but 0111 is still common.
-<<<<<<< HEAD
-<<<<<<< HEAD
-=======
->>>>>>> f994c51 (docs(go-cli): Add Python comparisons to all GO CONCEPT tutorial blocks)
 //
 // Compare with Python: Python uses the same bitwise operators: `&`, `|`,
 // `^`, `~` (NOT), `<<`, `>>`. Go's `&^` (AND NOT) is `& ~` in Python.
 // Octal literals use `0o` prefix: `0o755`, `0o111`. Python also has
 // `os.access(path, os.X_OK)` as a higher-level executable check.
-<<<<<<< HEAD
-=======
->>>>>>> 810cc4d (docs(go-cli): Add comprehensive Go tutorial comments for learning)
-=======
->>>>>>> f994c51 (docs(go-cli): Add Python comparisons to all GO CONCEPT tutorial blocks)
 
 // isExecutable checks if a file exists and is executable.
 func isExecutable(path string) bool {
@@ -18,6 +18,8 @@ import (
 	"path/filepath"
 	"testing"
 	"time"
+
+	"github.com/attic/atticprotocol"
 )
 
 // =============================================================================
@@ -143,9 +145,8 @@ func TestWaitForSocketFindsExistingSocket(t *testing.T) {
 	tmpDir := t.TempDir()
 
 	// We need to match the expected socket path format.
-	// waitForSocket uses atticprotocol.SocketPath(pid) which returns
-	// /tmp/attic-<PID>.sock. Instead of fighting that, we'll test the
-	// timeout behavior directly.
+	// waitForSocket looks for /tmp/attic-<PID>.sock, where AtticServer
+	// creates its socket.
 
 	// Create a file that looks like a socket at the expected path.
 	fakePid := 99999
@@ -176,6 +177,27 @@ func TestWaitForSocketFindsExistingSocket(t *testing.T) {
 	_ = tmpDir // used for t.TempDir() lifecycle only
 }
 
+// TestWaitForSocketIgnoresSocketDir verifies that a launched server is
+// found at its real /tmp path even when ATTIC_SOCKET_DIR points
+// elsewhere, since AtticServer does not read the variable.
+func TestWaitForSocketIgnoresSocketDir(t *testing.T) {
+	t.Setenv(atticprotocol.SocketDirEnv, t.TempDir())
+
+	socketPath := filepath.Join("/tmp", "attic-99998.sock")
+	if err := os.WriteFile(socketPath, []byte{}, 0644); err != nil {
+		t.Fatalf("failed to create fake socket: %v", err)
+	}
+	t.Cleanup(func() { os.Remove(socketPath) })
+
+	path, err := waitForSocket(99998)
+	if err != nil {
+		t.Fatalf("waitForSocket() returned error: %v", err)
+	}
+	if path != socketPath {
+		t.Errorf("waitForSocket() = %q, want %q", path, socketPath)
+	}
+}
+
 // TestWaitForSocketTimesOut verifies that waitForSocket returns an error
 // when the socket doesn't appear within the timeout.
 func TestWaitForSocketTimesOut(t *testing.T) {
@@ -0,0 +1,245 @@
+// =============================================================================
+// symbols.go - Symbol Table for Named Addresses
+// =============================================================================
+//
+// This file implements a REPL-side symbol table that maps label names to
+// 16-bit addresses. Symbols let the user write "d START" or "b set MAIN"
+// in monitor mode instead of remembering hex addresses.
+//
+// Symbols are resolved by the CLI before a command is translated or sent,
+// so the protocol itself stays purely numeric — the server never sees a
+// label name.
+//
+// Symbols are defined with dot-commands:
+//
+//	.sym                   List all defined symbols
+//	.sym <name> <$addr>    Define (or redefine) a symbol
+//	.sym load <file>       Load symbols from a label file
+//	.sym clear             Remove all symbols
+//
+// =============================================================================
+
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// GO CONCEPT: Maps as Lookup Tables
+// ----------------------------------
+// A map[K]V is Go's built-in hash table. Looking up a missing key returns
+// the zero value, so the "comma ok" form distinguishes "absent" from "zero":
+//
+//	addr, ok := table["START"]
+//
+// Maps must be created with make() (or a literal) before use — writing to
+// a nil map panics, though reading from one is fine.
+//
+// Compare with Swift: Swift's Dictionary returns an optional on lookup:
+//   if let addr = table["START"] { ... }
+//
+// Compare with Python: Python dicts raise KeyError for missing keys;
+// `table.get("START")` returns None instead, similar to Go's comma ok.
+
+// symbolTable maps upper-cased symbol names to addresses.
+type symbolTable struct {
+	symbols map[string]uint16
+}
+
+// newSymbolTable creates an empty symbol table.
+func newSymbolTable() *symbolTable {
+	return &symbolTable{symbols: make(map[string]uint16)}
+}
+
+// define adds or replaces a symbol. Names are case-insensitive.
+func (st *symbolTable) define(name string, addr uint16) {
+	st.symbols[strings.ToUpper(name)] = addr
+}
+
+// lookup returns the address for a symbol name, if defined.
+func (st *symbolTable) lookup(name string) (uint16, bool) {
+	addr, ok := st.symbols[strings.ToUpper(name)]
+	return addr, ok
+}
+
+// clear removes all symbols.
+func (st *symbolTable) clear() {
+	st.symbols = make(map[string]uint16)
+}
+
+// len returns the number of defined symbols.
+func (st *symbolTable) len() int {
+	return len(st.symbols)
+}
+
+// isSymbolName reports whether s is a valid symbol name: a letter or
+// underscore followed by letters, digits, or underscores.
+func isSymbolName(s string) bool {
+	if s == "" {
+		return false
+	}
+	for i, r := range s {
+		switch {
+		case r == '_', r >= 'A' && r <= 'Z', r >= 'a' && r <= 'z':
+		case i > 0 && r >= '0' && r <= '9':
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+// parseAddressArg parses an address argument typed at the REPL. It accepts
+// the same forms as the protocol parser: $hex, 0xhex, or decimal.
+func parseAddressArg(s string) (uint16, bool) {
+	s = strings.TrimSpace(s)
+	base := 10
+	switch {
+	case strings.HasPrefix(s, "$"):
+		s, base = s[1:], 16
+	case strings.HasPrefix(strings.ToLower(s), "0x"):
+		s, base = s[2:], 16
+	}
+	val, err := strconv.ParseUint(s, base, 16)
+	if err != nil {
+		return 0, false
+	}
+	return uint16(val), true
+}
+
+// resolveAddress parses an address argument that may be either a symbol
+// name or a numeric address ($hex, 0xhex, or decimal).
+func (st *symbolTable) resolveAddress(s string) (uint16, bool) {
+	if addr, ok := st.lookup(s); ok {
+		return addr, true
+	}
+	return parseAddressArg(s)
+}
+
+// resolve replaces symbol names in the arguments of a command line with
+// their "$XXXX" addresses. The first word (the command itself) is never
+// substituted, and "name=VALUE" arguments (e.g. "pc=START") have their
+// value resolved. Unknown words are left untouched.
+func (st *symbolTable) resolve(line string) string {
+	if len(st.symbols) == 0 {
+		return line
+	}
+	fields := strings.Fields(line)
+	for i := 1; i < len(fields); i++ {
+		word := fields[i]
+		prefix := ""
+		if eq := strings.IndexByte(word, '='); eq >= 0 {
+			prefix, word = word[:eq+1], word[eq+1:]
+		}
+		if !isSymbolName(word) {
+			continue
+		}
+		if addr, ok := st.lookup(word); ok {
+			fields[i] = fmt.Sprintf("%s$%04X", prefix, addr)
+		}
+	}
+	return strings.Join(fields, " ")
+}
+
+// load reads symbols from a label file and returns how many were defined.
+//
+// Each non-blank line holds a name and an address separated by whitespace
+// and/or "=", for example "START $0600" or "MAIN = $2000". Lines starting
+// with ";" or "#" are comments.
+func (st *symbolTable) load(path string) (int, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return 0, err
+	}
+	defer file.Close()
+
+	count := 0
+	lineNum := 0
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		lineNum++
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" || strings.HasPrefix(text, ";") || strings.HasPrefix(text, "#") {
+			continue
+		}
+		fields := strings.Fields(strings.ReplaceAll(text, "=", " "))
+		if len(fields) < 2 || !isSymbolName(fields[0]) {
+			return count, fmt.Errorf("%s:%d: expected '<name> <address>'", path, lineNum)
+		}
+		addr, ok := parseAddressArg(fields[1])
+		if !ok {
+			return count, fmt.Errorf("%s:%d: invalid address %q", path, lineNum, fields[1])
+		}
+		st.define(fields[0], addr)
+		count++
+	}
+	if err := scanner.Err(); err != nil {
+		return count, err
+	}
+	return count, nil
+}
+
+// list returns the symbols as display lines sorted by address, then name.
+func (st *symbolTable) list() []string {
+	names := make([]string, 0, len(st.symbols))
+	for name := range st.symbols {
+		names = append(names, name)
+	}
+	sort.Slice(names, func(i, j int) bool {
+		ai, aj := st.symbols[names[i]], st.symbols[names[j]]
+		if ai != aj {
+			return ai < aj
+		}
+		return names[i] < names[j]
+	})
+
+	lines := make([]string, len(names))
+	for i, name := range names {
+		lines[i] = fmt.Sprintf("$%04X  %s", st.symbols[name], name)
+	}
+	return lines
+}
+
+// handleSymbolCommand processes the arguments of a ".sym" dot-command.
+func handleSymbolCommand(st *symbolTable, args string) {
+	fields := strings.Fields(args)
+	switch {
+	case len(fields) == 0:
+		if st.len() == 0 {
+			fmt.Println("No symbols defined")
+			return
+		}
+		for _, line := range st.list() {
+			fmt.Println(line)
+		}
+	case len(fields) == 1 && strings.ToLower(fields[0]) == "clear":
+		st.clear()
+		fmt.Println("Symbols cleared")
+	case len(fields) == 2 && strings.ToLower(fields[0]) == "load":
+		count, err := st.load(expandPath(fields[1]))
+		if err != nil {
+			printError(err.Error())
+			return
+		}
+		fmt.Printf("Loaded %d symbol(s)\n", count)
+	case len(fields) == 2:
+		if !isSymbolName(fields[0]) {
+			printError(fmt.Sprintf("invalid symbol name %q", fields[0]))
+			return
+		}
+		addr, ok := parseAddressArg(fields[1])
+		if !ok {
+			printError(fmt.Sprintf("invalid address %q", fields[1]))
+			return
+		}
+		st.define(fields[0], addr)
+		fmt.Printf("%s = $%04X\n", strings.ToUpper(fields[0]), addr)
+	default:
+		fmt.Fprintln(os.Stderr, "Usage: .sym [<name> <$addr> | load <file> | clear]")
+	}
+}
@@ -0,0 +1,85 @@
+// =============================================================================
+// symbols_test.go - Tests for the Symbol Table (symbols.go)
+// =============================================================================
+
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// TestSymbolTableDefineAndLookup verifies case-insensitive definition.
+func TestSymbolTableDefineAndLookup(t *testing.T) {
+	st := newSymbolTable()
+	st.define("start", 0x0600)
+
+	addr, ok := st.lookup("START")
+	if !ok || addr != 0x0600 {
+		t.Errorf("lookup(START) = $%04X, %v; want $0600, true", addr, ok)
+	}
+	if _, ok := st.lookup("MAIN"); ok {
+		t.Error("lookup(MAIN) should fail for undefined symbol")
+	}
+}
+
+// TestSymbolTableResolve verifies substitution of symbols in command lines.
+func TestSymbolTableResolve(t *testing.T) {
+	st := newSymbolTable()
+	st.define("START", 0x0600)
+	st.define("MAIN", 0x2000)
+
+	tests := []struct {
+		line string
+		want string
+	}{
+		{"d START", "d $0600"},
+		{"b set main", "b set $2000"},
+		{"disassemble START 10", "disassemble $0600 10"},
+		{"registers pc=START", "registers pc=$0600"},
+		{"m $0600 16", "m $0600 16"},
+		{"d UNKNOWN", "d UNKNOWN"},
+		{"START", "START"}, // the command word is never substituted
+	}
+
+	for _, tc := range tests {
+		got := st.resolve(tc.line)
+		if got != tc.want {
+			t.Errorf("resolve(%q) = %q, want %q", tc.line, got, tc.want)
+		}
+	}
+}
+
+// TestSymbolTableLoad verifies reading a label file.
+func TestSymbolTableLoad(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "labels.sym")
+	content := "; comment\nSTART $0600\nMAIN = $2000\n\nloop 0x0610\n"
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	st := newSymbolTable()
+	count, err := st.load(path)
+	if err != nil {
+		t.Fatalf("load failed: %v", err)
+	}
+	if count != 3 {
+		t.Errorf("load count = %d, want 3", count)
+	}
+	if addr, _ := st.lookup("LOOP"); addr != 0x0610 {
+		t.Errorf("LOOP = $%04X, want $0610", addr)
+	}
+}
+
+// TestSymbolTableLoadInvalid verifies that malformed lines are reported.
+func TestSymbolTableLoadInvalid(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "bad.sym")
+	if err := os.WriteFile(path, []byte("START $ZZZZ\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := newSymbolTable().load(path); err == nil {
+		t.Error("expected error for invalid address")
+	}
+}
@@ -0,0 +1,58 @@
+// =============================================================================
+// tokens.go - Saving the Tokenized BASIC Program (.tokens)
+// =============================================================================
+//
+// ".tokens" saves the BASIC program's tokenized bytes, as they sit in
+// emulator memory, to a host file for a hex editor or tokenizer tools:
+//
+//	.tokens <path>
+//
+// The bytes run from the variable name table to the end of the program,
+// the same layout SAVE writes after its 14-byte header.
+//
+// =============================================================================
+
+package main
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/attic/atticprotocol"
+)
+
+// runTokensCommand handles ".tokens <path>".
+func runTokensCommand(client *atticprotocol.Client, args string, opts replOptions) {
+	path := expandPath(strings.TrimSpace(args))
+	if path == "" {
+		printError("usage: .tokens <path>")
+		return
+	}
+
+	cmd := atticprotocol.NewBasicTokensCommand()
+	if opts.dryRun {
+		fmt.Println("CMD:" + cmd.Format())
+		return
+	}
+	if !client.IsConnected() {
+		printError("not connected (use .connect <socket>)")
+		return
+	}
+
+	resp, err := client.Send(cmd)
+	if err != nil {
+		printError(err.Error())
+		return
+	}
+	data, err := resp.AsBytes()
+	if err != nil {
+		printError(err.Error())
+		return
+	}
+	if err := os.WriteFile(path, data, 0o644); err != nil {
+		printError(err.Error())
+		return
+	}
+	fmt.Printf("Saved %d token bytes to %s\n", len(data), path)
+}
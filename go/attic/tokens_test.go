@@ -0,0 +1,46 @@
+// =============================================================================
+// tokens_test.go - Tests for Saving the Tokenized Program (tokens.go)
+// =============================================================================
+
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// TestREPLTokens verifies that .tokens writes the server's bytes to disk.
+func TestREPLTokens(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "prog.tok")
+	output := captureREPL(t, ".tokens "+path+"\n.quit\n", func(cmd string) string {
+		switch cmd {
+		case "ping":
+			return "OK:pong\n"
+		case "basic TOKENS":
+			return "OK:data 00,0A,00,05,16\n"
+		}
+		return "ERR:unexpected " + cmd + "\n"
+	})
+
+	if want := "Saved 5 token bytes to " + path; !strings.Contains(output, want) {
+		t.Errorf("expected %q, got:\n%s", want, output)
+	}
+	got, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("failed to read saved file: %v", err)
+	}
+	if want := []byte{0x00, 0x0A, 0x00, 0x05, 0x16}; !bytes.Equal(got, want) {
+		t.Errorf("saved bytes = %X, want %X", got, want)
+	}
+}
+
+// TestREPLTokensUsage verifies that a missing path sends nothing.
+func TestREPLTokensUsage(t *testing.T) {
+	output := captureREPLWithOptions(t, ".tokens\n.quit\n", nil, replOptions{dryRun: true})
+	if strings.Contains(output, "CMD:") {
+		t.Errorf("should send nothing, got:\n%s", output)
+	}
+}
@@ -0,0 +1,46 @@
+// =============================================================================
+// trace.go - Logging Executed Instructions to a File (.trace)
+// =============================================================================
+//
+// ".trace" asks the server to log every instruction the 6502 executes, for
+// working out afterwards how a program got somewhere:
+//
+//	.trace on ~/traces/boot.log
+//	.trace off
+//
+// The file is written by the server, so the path is on the server's host.
+// Tracing slows the emulator down and the log grows quickly; turn it off
+// as soon as the interesting part has run.
+//
+// =============================================================================
+
+package main
+
+import (
+	"strings"
+
+	"github.com/attic/atticprotocol"
+)
+
+// runTraceCommand handles ".trace on <path>" and ".trace off".
+func runTraceCommand(client *atticprotocol.Client, args string, opts replOptions) {
+	state, pathArg, _ := strings.Cut(strings.TrimSpace(args), " ")
+	path := expandPath(strings.TrimSpace(pathArg))
+
+	var cmd atticprotocol.Command
+	switch {
+	case strings.EqualFold(state, "on") && path != "":
+		cmd = atticprotocol.NewTraceCommand(true, path)
+	case strings.EqualFold(state, "off") && path == "":
+		cmd = atticprotocol.NewTraceCommand(false, "")
+	default:
+		printError("usage: .trace on <path> | .trace off")
+		return
+	}
+
+	if !opts.dryRun && !client.IsConnected() {
+		printError("not connected (use .connect <socket>)")
+		return
+	}
+	sendCommand(client, cmd, opts)
+}
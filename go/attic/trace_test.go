@@ -0,0 +1,36 @@
+// =============================================================================
+// trace_test.go - Tests for Logging Executed Instructions (trace.go)
+// =============================================================================
+
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// TestREPLTrace verifies the commands .trace sends, with "~" expanded.
+func TestREPLTrace(t *testing.T) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		t.Skip("no home directory")
+	}
+	output := captureREPLWithOptions(t, ".trace on ~/boot.log\n.trace OFF\n.quit\n", nil, replOptions{dryRun: true})
+	for _, want := range []string{"CMD:trace on " + filepath.Join(home, "boot.log") + "\n", "CMD:trace off\n"} {
+		if !strings.Contains(output, want) {
+			t.Errorf("expected %q in output, got:\n%s", want, output)
+		}
+	}
+}
+
+// TestREPLTraceUsage verifies that bad arguments send nothing.
+func TestREPLTraceUsage(t *testing.T) {
+	for _, input := range []string{".trace\n", ".trace on\n", ".trace off /tmp/t.log\n", ".trace maybe\n"} {
+		output := captureREPLWithOptions(t, input+".quit\n", nil, replOptions{dryRun: true})
+		if strings.Contains(output, "CMD:") {
+			t.Errorf("%q: should send nothing, got:\n%s", strings.TrimSpace(input), output)
+		}
+	}
+}
@@ -0,0 +1,363 @@
+// =============================================================================
+// translate.go - REPL Command Translation (monitor/basic/dos → protocol)
+// =============================================================================
+//
+// This file converts what the user types at the REPL prompt into one or more
+// CLI protocol command strings. It is a port of translateToProtocol() and its
+// three mode-specific helpers from the Swift CLI (AtticCLI.swift).
+//
+// Each mode has its own short-hand vocabulary:
+//   - Monitor: "g $0600", "s 5", "m $0600 16", "b set $0600", ...
+//   - BASIC:   "list", "del 10-50", "renum", and numbered program lines
+//   - DOS:     "cd 2", "dir *.BAS", "copy SRC DST", ...
+//
+// Most inputs map to exactly one protocol command. A few expand to a
+// sequence — for example "g $0600" becomes "registers pc=$0600" followed
+// by "resume" — so translateToProtocol always returns a slice.
+//
+// =============================================================================
+
+package main
+
+import (
+	"strings"
+
+	"github.com/attic/atticprotocol"
+)
+
+// GO CONCEPT: Returning Slices for "One or More" Results
+// -------------------------------------------------------
+// When a function usually returns one value but sometimes several, returning
+// a slice ([]string) keeps the call site uniform: the caller always loops.
+// A single-element slice literal is written []string{"resume"}.
+//
+// Compare with Swift: The Swift CLI returns [String] for the same reason.
+//
+// Compare with Python: Python would return a list: `return ["resume"]`.
+// Generators (`yield`) are another option when results are produced lazily.
+
+// translateToProtocol translates a REPL input line into one or more CLI
+// protocol command strings (without the CMD: prefix) for the given mode.
+//
+// Global dot-commands that map to server commands (.status, .reset, ...) are
+// translated the same way in every mode. Everything else is handled by the
+// mode-specific translator. atascii controls whether BASIC listings request
+// rich ATASCII rendering.
+func translateToProtocol(line string, mode REPLMode, atascii bool) []string {
+	trimmed := strings.TrimSpace(line)
+
+	// Handle dot-commands that forward to the server.
+	if cmd, ok := translateDotCommand(trimmed); ok {
+		return []string{cmd}
+	}
+
+	switch mode {
+	case ModeMonitor:
+		return translateMonitorCommand(trimmed)
+	case ModeBasic:
+		return []string{translateBASICCommand(trimmed, atascii)}
+	case ModeDOS:
+		return []string{translateDOSCommand(trimmed)}
+	default:
+		return []string{trimmed}
+	}
+}
+
+// translateDotCommand translates the global dot-commands that forward to
+// the server. It returns false for any other line.
+func translateDotCommand(trimmed string) (string, bool) {
+	lower := strings.ToLower(trimmed)
+	switch {
+	case lower == ".status":
+		return "status", true
+	case lower == ".screen":
+		return "screen", true
+	case lower == ".reset":
+		return "reset cold", true
+	case lower == ".warmstart":
+		return "reset warm", true
+	case lower == ".screenshot":
+		return "screenshot", true
+	case strings.HasPrefix(lower, ".screenshot "):
+		return "screenshot " + trimmed[len(".screenshot "):], true
+	case strings.HasPrefix(lower, ".state save "):
+		return "state save " + trimmed[len(".state save "):], true
+	case strings.HasPrefix(lower, ".state load "):
+		return "state load " + trimmed[len(".state load "):], true
+	case lower == ".boot":
+		return "boot", true
+	case strings.HasPrefix(lower, ".boot "):
+		return "boot " + trimmed[len(".boot "):], true
+	}
+	return "", false
+}
+
+// splitCommand splits an input line into its lowercase command word and the
+// remaining argument text (trimmed). An empty line yields two empty strings.
+func splitCommand(line string) (command, args string) {
+	parts := strings.SplitN(strings.TrimSpace(line), " ", 2)
+	command = strings.ToLower(parts[0])
+	if len(parts) > 1 {
+		args = strings.TrimSpace(parts[1])
+	}
+	return command, args
+}
+
+// translateMonitorCommand translates a monitor mode command.
+//
+// Most commands produce a single protocol string, but "g $addr" expands to
+// two commands: set the program counter, then resume execution.
+func translateMonitorCommand(cmd string) []string {
+	command, args := splitCommand(cmd)
+	if command == "" {
+		return []string{cmd}
+	}
+
+	switch command {
+	case "g":
+		if args == "" {
+			return []string{"resume"}
+		}
+		// g $addr -> set PC first, then resume
+		return []string{"registers pc=" + args, "resume"}
+	case "s", "step":
+		// Steps whole video frames; "si" steps single instructions.
+		if args == "" {
+			return []string{"step"}
+		}
+		return []string{"step " + args}
+	case "fr", "frame":
+		// Advances to the next vertical blank, unlike "s".
+		if args == "" {
+			return []string{"frame 1"}
+		}
+		return []string{"frame " + args}
+	case "si", "stepi":
+		if args == "" {
+			return []string{"stepi"}
+		}
+		return []string{"stepi " + args}
+	case "so", "stepover":
+		return []string{"stepover"}
+	case "sr", "stepout":
+		return []string{"stepout"}
+	case "p", "pause":
+		return []string{"pause"}
+	case "r", "registers":
+		if args == "" {
+			return []string{"registers"}
+		}
+		return []string{"registers " + args}
+	case "m", "memory":
+		// m $0600 16 -> read $0600 16
+		return []string{"read " + args}
+	case ">":
+		// > $0600 A9,00 -> write $0600 A9,00
+		return []string{"write " + args}
+	case "f", "fill":
+		return []string{"fill " + args}
+	case "d", "disassemble":
+		if args == "" {
+			return []string{"disassemble"}
+		}
+		return []string{"disassemble " + args}
+	case "a", "assemble":
+		return []string{"assemble " + args}
+	case "b", "breakpoint":
+		return []string{"breakpoint " + args}
+	case "bp":
+		return []string{"breakpoint set " + args}
+	case "tbp":
+		// Temporary breakpoint, removed by the server after it first stops.
+		return []string{"breakpoint set " + args + " once"}
+	case "bc":
+		return []string{"breakpoint clear " + args}
+	case "be":
+		return []string{"breakpoint enable " + args}
+	case "bd":
+		return []string{"breakpoint disable " + args}
+	case "until":
+		return []string{"until " + args}
+	default:
+		return []string{cmd}
+	}
+}
+
+// translateBASICCommand translates a BASIC mode command.
+//
+// Recognized editing commands (list, del, run, ...) are routed to the
+// "basic" protocol command. Anything else — typically a numbered program
+// line — is typed into the emulator as keystrokes followed by RETURN.
+func translateBASICCommand(cmd string, atascii bool) string {
+	command, args := splitCommand(cmd)
+
+	switch strings.ToUpper(command) {
+	case "LIST":
+		// Forward optional range arguments (e.g. "10", "10-50") along with
+		// the ATASCII flag to the server.
+		result := "basic list"
+		if args != "" {
+			result += " " + args
+		}
+		if atascii {
+			result += " atascii"
+		}
+		return result
+	case "DEL", "DELETE":
+		if args == "" {
+			return "basic del" // let the server report the error
+		}
+		return "basic del " + args
+	case "NEW":
+		return "basic new"
+	case "RUN":
+		return "basic run"
+	case "STOP":
+		return "basic stop"
+	case "CONT":
+		return "basic cont"
+	case "VARS":
+		return "basic vars"
+	case "VAR":
+		if args == "" {
+			return "basic var"
+		}
+		return "basic var " + args
+	case "INFO":
+		return "basic info"
+	case "FRE":
+		return "basic fre"
+	case "STEP":
+		return "basic step"
+	case "FIND":
+		if args == "" {
+			return "basic find" // let the server report the error
+		}
+		return atticprotocol.NewBasicFindCommand(args).Format()
+	case "REFS":
+		if args == "" {
+			return "basic refs" // let the server report the error
+		}
+		return "basic refs " + args
+	case "TRACE":
+		if args == "" {
+			return "basic trace" // let the server report the error
+		}
+		return "basic trace " + strings.ToLower(args)
+	case "EXPORT":
+		if args == "" {
+			return "basic export"
+		}
+		return "basic export " + args
+	case "IMPORT":
+		if args == "" {
+			return "basic import"
+		}
+		return "basic import " + args
+	case "DIR":
+		if args == "" {
+			return "basic dir"
+		}
+		return "basic dir " + args
+	case "RENUM", "RENUMBER":
+		if args == "" {
+			return "basic renum"
+		}
+		return "basic renum " + args
+	case "SAVE":
+		if args == "" {
+			return "basic save"
+		}
+		return "basic save " + args
+	case "LOAD":
+		if args == "" {
+			return "basic load"
+		}
+		return "basic load " + args
+	default:
+		// A line with ATASCII graphics or inverse video, e.g. pasted from
+		// "LIST" output in ATASCII mode, is typed as raw key codes.
+		if hasNonASCII(cmd) {
+			if codes, ok := atticprotocol.EncodeATASCII(cmd); ok {
+				codes = append(codes, atticprotocol.ATASCIIEOL)
+				return atticprotocol.NewInjectKeyCodesCommand(codes).Format()
+			}
+		}
+		// Type the line into the emulator. Escape the characters the
+		// protocol treats specially and finish with RETURN.
+		escaped := strings.ReplaceAll(cmd, "\\", "\\\\")
+		escaped = strings.ReplaceAll(escaped, " ", "\\s")
+		escaped = strings.ReplaceAll(escaped, "\t", "\\t")
+		return "inject keys " + escaped + "\\n"
+	}
+}
+
+// hasNonASCII reports whether s contains characters beyond printable
+// ASCII, such as Unicode graphics glyphs or ANSI escape sequences.
+func hasNonASCII(s string) bool {
+	for _, r := range s {
+		if r >= 0x7F || r == 0x1B {
+			return true
+		}
+	}
+	return false
+}
+
+// translateDOSCommand translates a DOS mode command.
+//
+// DOS commands are prefixed with "dos " for the protocol, mirroring how
+// BASIC commands are prefixed with "basic ". The disk commands shared by
+// all modes (mount, unmount, drives) stay at the top level.
+func translateDOSCommand(cmd string) string {
+	command, args := splitCommand(cmd)
+
+	switch command {
+	case "":
+		return cmd
+	case "mount":
+		return "mount " + args
+	case "unmount", "umount":
+		return "unmount " + args
+	case "drives":
+		return "drives"
+	case "cd":
+		return "dos cd " + args
+	case "dir":
+		if args == "" {
+			return "dos dir"
+		}
+		return "dos dir " + args
+	case "info":
+		return "dos info " + args
+	case "type":
+		return "dos type " + args
+	case "dump":
+		return "dos dump " + args
+	case "copy", "cp":
+		return "dos copy " + args
+	case "diff":
+		return "dos diff " + args
+	case "rename", "ren":
+		return "dos rename " + args
+	case "delete", "del":
+		return "dos delete " + args
+	case "lock":
+		return "dos lock " + args
+	case "unlock":
+		return "dos unlock " + args
+	case "export":
+		return "dos export " + args
+	case "import":
+		return "dos import " + args
+	case "newdisk":
+		return "dos newdisk " + args
+	case "format":
+		return "dos format"
+	case "check":
+		return "dos check"
+	case "protect":
+		return "dos protect " + strings.ToLower(args)
+	default:
+		return cmd
+	}
+}
@@ -0,0 +1,91 @@
+// =============================================================================
+// translate_test.go - Tests for REPL Command Translation (translate.go)
+// =============================================================================
+//
+// Table-driven tests for translateToProtocol and the mode-specific helpers.
+//
+// =============================================================================
+
+package main
+
+import (
+	"reflect"
+	"testing"
+)
+
+// TestTranslateToProtocol verifies translation of input lines in each mode.
+func TestTranslateToProtocol(t *testing.T) {
+	tests := []struct {
+		name    string
+		line    string
+		mode    REPLMode
+		atascii bool
+		want    []string
+	}{
+		// Global dot-commands
+		{"status", ".status", ModeBasic, false, []string{"status"}},
+		{"reset", ".reset", ModeMonitor, false, []string{"reset cold"}},
+		{"warmstart", ".warmstart", ModeDOS, false, []string{"reset warm"}},
+		{"screenshot path", ".screenshot /tmp/a.png", ModeBasic, false, []string{"screenshot /tmp/a.png"}},
+		{"state save", ".state save /tmp/s.state", ModeBasic, false, []string{"state save /tmp/s.state"}},
+		{"boot", ".boot /tmp/game.atr", ModeBasic, false, []string{"boot /tmp/game.atr"}},
+
+		// Monitor mode
+		{"go address", "g $0600", ModeMonitor, false, []string{"registers pc=$0600", "resume"}},
+		{"go", "g", ModeMonitor, false, []string{"resume"}},
+		{"step count", "s 5", ModeMonitor, false, []string{"step 5"}},
+		{"frame", "fr", ModeMonitor, false, []string{"frame 1"}},
+		{"frame count", "fr 3", ModeMonitor, false, []string{"frame 3"}},
+		{"frame long form", "frame 2", ModeMonitor, false, []string{"frame 2"}},
+		{"step instruction", "si", ModeMonitor, false, []string{"stepi"}},
+		{"step instruction count", "si 5", ModeMonitor, false, []string{"stepi 5"}},
+		{"stepi long form", "stepi 2", ModeMonitor, false, []string{"stepi 2"}},
+		{"step out", "sr", ModeMonitor, false, []string{"stepout"}},
+		{"memory", "m $0600 16", ModeMonitor, false, []string{"read $0600 16"}},
+		{"write", "> $0600 A9,00", ModeMonitor, false, []string{"write $0600 A9,00"}},
+		{"disassemble", "d $E000", ModeMonitor, false, []string{"disassemble $E000"}},
+		{"breakpoint", "b set $0600", ModeMonitor, false, []string{"breakpoint set $0600"}},
+		{"temporary breakpoint", "tbp $0600", ModeMonitor, false, []string{"breakpoint set $0600 once"}},
+		{"temporary conditional breakpoint", "tbp $0600 if A==$00", ModeMonitor, false, []string{"breakpoint set $0600 if A==$00 once"}},
+		{"enable breakpoint", "be $0600", ModeMonitor, false, []string{"breakpoint enable $0600"}},
+		{"disable breakpoint", "bd $0600", ModeMonitor, false, []string{"breakpoint disable $0600"}},
+		{"monitor passthrough", "status", ModeMonitor, false, []string{"status"}},
+
+		// BASIC mode
+		{"list", "LIST", ModeBasic, false, []string{"basic list"}},
+		{"list atascii", "list 10-50", ModeBasic, true, []string{"basic list 10-50 atascii"}},
+		{"delete", "DEL 10", ModeBasic, false, []string{"basic del 10"}},
+		{"renumber", "RENUMBER 100 10", ModeBasic, false, []string{"basic renum 100 10"}},
+		{"free memory", "fre", ModeBasic, false, []string{"basic fre"}},
+		{"step", "STEP", ModeBasic, false, []string{"basic step"}},
+		{"refs", "refs 100", ModeBasic, false, []string{"basic refs 100"}},
+		{"find", `find PRINT "HI`, ModeBasic, false, []string{`basic FIND PRINT\s"HI`}},
+		{"monitor step", "step", ModeMonitor, false, []string{"step"}},
+		{"trace on", "trace on", ModeBasic, false, []string{"basic trace on"}},
+		{"trace off", "TRACE OFF", ModeBasic, false, []string{"basic trace off"}},
+		{"free memory in a line", "PRINT FRE(0)", ModeBasic, false, []string{`inject keys PRINT\sFRE(0)\n`}},
+		{"program line", `10 PRINT "HI"`, ModeBasic, false, []string{`inject keys 10\sPRINT\s"HI"\n`}},
+		{"backslash", `PRINT "\"`, ModeBasic, false, []string{`inject keys PRINT\s"\\"\n`}},
+		{"graphics line", `1 ?"┌─┐"`, ModeBasic, false, []string{"inject codes 31,20,3F,22,11,12,05,22,9B"}},
+		{"inverse line", "2 ?\"\x1b[7mHI\x1b[27m\"", ModeBasic, false, []string{"inject codes 32,20,3F,22,C8,C9,22,9B"}},
+
+		// DOS mode
+		{"mount", "mount 1 /tmp/d.atr", ModeDOS, false, []string{"mount 1 /tmp/d.atr"}},
+		{"umount alias", "umount 1", ModeDOS, false, []string{"unmount 1"}},
+		{"dir", "dir", ModeDOS, false, []string{"dos dir"}},
+		{"copy alias", "cp A.BAS B.BAS", ModeDOS, false, []string{"dos copy A.BAS B.BAS"}},
+		{"diff", "diff D1:A.BAS D2:A.BAS", ModeDOS, false, []string{"dos diff D1:A.BAS D2:A.BAS"}},
+		{"check", "check", ModeDOS, false, []string{"dos check"}},
+		{"protect", "protect ON", ModeDOS, false, []string{"dos protect on"}},
+		{"delete alias", "del A.BAS", ModeDOS, false, []string{"dos delete A.BAS"}},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			got := translateToProtocol(tc.line, tc.mode, tc.atascii)
+			if !reflect.DeepEqual(got, tc.want) {
+				t.Errorf("translateToProtocol(%q) = %q, want %q", tc.line, got, tc.want)
+			}
+		})
+	}
+}
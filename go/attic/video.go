@@ -0,0 +1,38 @@
+// =============================================================================
+// video.go - Switching Between NTSC and PAL (.video)
+// =============================================================================
+//
+// ".video" shows or switches the emulated video system, for testing
+// software that behaves differently in NTSC (60 Hz) and PAL (50 Hz)
+// regions:
+//
+//	.video
+//	.video pal
+//	.video ntsc
+//
+// =============================================================================
+
+package main
+
+import (
+	"strings"
+
+	"github.com/attic/atticprotocol"
+)
+
+// runVideoCommand handles ".video [ntsc|pal]".
+func runVideoCommand(client *atticprotocol.Client, args string, opts replOptions) {
+	system := strings.ToLower(strings.TrimSpace(args))
+	switch system {
+	case "", "ntsc", "pal":
+	default:
+		printError("usage: .video [ntsc|pal]")
+		return
+	}
+
+	if !opts.dryRun && !client.IsConnected() {
+		printError("not connected (use .connect <socket>)")
+		return
+	}
+	sendCommand(client, atticprotocol.NewVideoSystemCommand(system), opts)
+}
@@ -0,0 +1,40 @@
+// =============================================================================
+// video_test.go - Tests for Switching Between NTSC and PAL (video.go)
+// =============================================================================
+
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+// TestREPLVideo verifies that .video reads and sets the video system.
+func TestREPLVideo(t *testing.T) {
+	var received []string
+	output := captureREPL(t, ".video\n.video PAL\n.quit\n", func(cmd string) string {
+		if cmd == "ping" {
+			return "OK:pong\n"
+		}
+		received = append(received, cmd)
+		if cmd == "video" {
+			return "OK:video ntsc\n"
+		}
+		return "OK:" + cmd + "\n"
+	})
+
+	if want := []string{"video", "video pal"}; strings.Join(received, "|") != strings.Join(want, "|") {
+		t.Errorf("commands = %q, want %q", received, want)
+	}
+	if !strings.Contains(output, "video ntsc") {
+		t.Errorf("expected current system in output, got:\n%s", output)
+	}
+}
+
+// TestREPLVideoInvalid verifies that an unknown system sends nothing.
+func TestREPLVideoInvalid(t *testing.T) {
+	output := captureREPLWithOptions(t, ".video secam\n.quit\n", nil, replOptions{dryRun: true})
+	if strings.Contains(output, "CMD:") {
+		t.Errorf("expected no command, got:\n%s", output)
+	}
+}
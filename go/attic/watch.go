@@ -0,0 +1,202 @@
+// =============================================================================
+// watch.go - Poll-Based Memory Watch (.watchmem)
+// =============================================================================
+//
+// This file implements the ".watchmem" dot-command, a lightweight live
+// memory monitor. It repeatedly reads a block of emulator memory and prints
+// the bytes only when they differ from the previous poll, so the output
+// stays quiet while memory is stable.
+//
+//	.watchmem <addr> <len> [interval]
+//
+// The interval is a Go duration ("250ms", "2s") or a plain number of
+// milliseconds, and defaults to 500ms. The watch stops when the user
+// presses Enter or Ctrl-C. With piped input, where there is no one to press
+// Enter, it stops on Ctrl-C or after pipedWatchDuration, and leaves the
+// following script lines alone.
+//
+// =============================================================================
+
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/attic/atticprotocol"
+)
+
+// defaultWatchInterval is the polling interval when none is given.
+const defaultWatchInterval = 500 * time.Millisecond
+
+// pipedWatchDuration is how long a watch runs when input is piped. It is a
+// variable so tests can shorten it.
+var pipedWatchDuration = 10 * time.Second
+
+// GO CONCEPT: select with time.Ticker
+// ------------------------------------
+// A time.Ticker delivers the current time on its channel C at a fixed
+// interval. Combined with select, a goroutine can wait for "the next tick
+// OR a stop signal", whichever comes first:
+//
+//	select {
+//	case <-stop:        // stop requested
+//	    return
+//	case <-ticker.C:    // time for the next poll
+//	}
+//
+// Always call ticker.Stop() (usually via defer) to release its resources.
+//
+// Compare with Swift: Swift would use a Timer or an async loop with
+// Task.sleep(for:) and check Task.isCancelled.
+//
+// Compare with Python: Python would use `threading.Event().wait(interval)`,
+// which returns True early if the event is set — a compact stop-or-timeout.
+
+// parseWatchArgs parses the arguments of ".watchmem": an address (or
+// symbol name), a byte count, and an optional interval.
+func parseWatchArgs(args string, symbols *symbolTable) (addr uint16, length uint16, interval time.Duration, err error) {
+	fields := strings.Fields(args)
+	if len(fields) < 2 || len(fields) > 3 {
+		return 0, 0, 0, fmt.Errorf("usage: .watchmem <addr> <len> [interval]")
+	}
+
+	addr, ok := symbols.resolveAddress(fields[0])
+	if !ok {
+		return 0, 0, 0, fmt.Errorf("invalid address %q", fields[0])
+	}
+
+	n, convErr := strconv.ParseUint(fields[1], 10, 16)
+	if convErr != nil || n == 0 {
+		return 0, 0, 0, fmt.Errorf("invalid length %q", fields[1])
+	}
+	length = uint16(n)
+
+	interval = defaultWatchInterval
+	if len(fields) == 3 {
+		if ms, convErr := strconv.Atoi(fields[2]); convErr == nil {
+			interval = time.Duration(ms) * time.Millisecond
+		} else if interval, convErr = time.ParseDuration(fields[2]); convErr != nil {
+			return 0, 0, 0, fmt.Errorf("invalid interval %q", fields[2])
+		}
+		if interval <= 0 {
+			return 0, 0, 0, fmt.Errorf("invalid interval %q", fields[2])
+		}
+	}
+
+	return addr, length, interval, nil
+}
+
+// formatWatchBytes formats a memory block as hex dump lines of 16 bytes,
+// each prefixed with its address.
+func formatWatchBytes(addr uint16, data []byte) string {
+	var b strings.Builder
+	for offset := 0; offset < len(data); offset += 16 {
+		end := offset + 16
+		if end > len(data) {
+			end = len(data)
+		}
+		if offset > 0 {
+			b.WriteString("\n")
+		}
+		fmt.Fprintf(&b, "$%04X:", uint16(int(addr)+offset))
+		for _, v := range data[offset:end] {
+			fmt.Fprintf(&b, " %02X", v)
+		}
+	}
+	return b.String()
+}
+
+// watchMemory polls addr..addr+length-1 every interval and writes the bytes
+// to out whenever they change (and once at the start). It returns when
+// stop is closed, or with an error if a read fails.
+func watchMemory(client *atticprotocol.Client, addr, length uint16, interval time.Duration, stop <-chan struct{}, out io.Writer) error {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	var last []byte
+	for {
+		// Long blocks are read in chunks that fit the protocol's line
+		// length.
+		data, err := readMemory(client, addr, int(length))
+		if err != nil {
+			return err
+		}
+		if last == nil || !bytes.Equal(data, last) {
+			fmt.Fprintln(out, formatWatchBytes(addr, data))
+			last = data
+		}
+
+		select {
+		case <-stop:
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// runWatchCommand runs ".watchmem" from the REPL until the user presses
+// Enter or Ctrl-C, or for pipedWatchDuration with piped input.
+func runWatchCommand(client *atticprotocol.Client, editor *LineEditor, args string, symbols *symbolTable, opts replOptions) {
+	addr, length, interval, err := parseWatchArgs(args, symbols)
+	if err != nil {
+		printError(err.Error())
+		return
+	}
+	if opts.dryRun {
+		for _, cmd := range readChunkCommands(addr, int(length)) {
+			fmt.Println("CMD:" + cmd.Format())
+		}
+		return
+	}
+
+	// Ctrl-C, Enter and the piped time limit all stop the watch; sync.Once
+	// makes sure the stop channel is closed only once, whichever happens
+	// first.
+	stop := make(chan struct{})
+	var once sync.Once
+	stopWatch := func() { once.Do(func() { close(stop) }) }
+	setInterruptHandler(stopWatch)
+	defer setInterruptHandler(nil)
+
+	// Piped input holds the rest of the script, not a line meant to end
+	// the watch, so it is not read.
+	if !editor.IsInteractive() {
+		fmt.Printf("Watching $%04X (%d bytes) every %v for %v.\n", addr, length, interval, pipedWatchDuration)
+		timer := time.AfterFunc(pipedWatchDuration, stopWatch)
+		defer timer.Stop()
+		if err := watchMemory(client, addr, length, interval, stop, os.Stdout); err != nil {
+			printError(err.Error())
+		}
+		return
+	}
+
+	fmt.Printf("Watching $%04X (%d bytes) every %v. Press Enter or Ctrl-C to stop.\n", addr, length, interval)
+
+	// Read the line that ends the watch in the background. The editor is
+	// not used by anything else while the watch runs.
+	lineDone := make(chan struct{})
+	go func() {
+		_, _ = editor.GetLine("")
+		close(lineDone)
+		stopWatch()
+	}()
+
+	if err := watchMemory(client, addr, length, interval, stop, os.Stdout); err != nil {
+		printError(err.Error())
+	}
+
+	// Wait for the terminating line so the next prompt owns the input.
+	select {
+	case <-lineDone:
+	default:
+		fmt.Println("Watch stopped. Press Enter to continue.")
+		<-lineDone
+	}
+}
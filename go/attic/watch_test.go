@@ -0,0 +1,174 @@
+// =============================================================================
+// watch_test.go - Tests for the Memory Watch (watch.go)
+// =============================================================================
+
+package main
+
+import (
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/attic/atticprotocol"
+)
+
+// TestParseWatchArgs verifies argument parsing for .watchmem.
+func TestParseWatchArgs(t *testing.T) {
+	symbols := newSymbolTable()
+	symbols.define("SCREEN", 0xBC40)
+
+	tests := []struct {
+		args     string
+		addr     uint16
+		length   uint16
+		interval time.Duration
+		wantErr  bool
+	}{
+		{"$0600 16", 0x0600, 16, defaultWatchInterval, false},
+		{"$0600 4 100", 0x0600, 4, 100 * time.Millisecond, false},
+		{"1536 4 2s", 0x0600, 4, 2 * time.Second, false},
+		{"SCREEN 40", 0xBC40, 40, defaultWatchInterval, false},
+		{"$0600", 0, 0, 0, true},
+		{"$GGGG 4", 0, 0, 0, true},
+		{"$0600 0", 0, 0, 0, true},
+		{"$0600 4 soon", 0, 0, 0, true},
+	}
+
+	for _, tc := range tests {
+		addr, length, interval, err := parseWatchArgs(tc.args, symbols)
+		if (err != nil) != tc.wantErr {
+			t.Errorf("parseWatchArgs(%q) error = %v, wantErr %v", tc.args, err, tc.wantErr)
+			continue
+		}
+		if tc.wantErr {
+			continue
+		}
+		if addr != tc.addr || length != tc.length || interval != tc.interval {
+			t.Errorf("parseWatchArgs(%q) = $%04X, %d, %v; want $%04X, %d, %v",
+				tc.args, addr, length, interval, tc.addr, tc.length, tc.interval)
+		}
+	}
+}
+
+// TestWatchMemoryPrintsChanges verifies that memory is printed on the first
+// poll and then only when it changes between polls.
+func TestWatchMemoryPrintsChanges(t *testing.T) {
+	// The mock memory changes on the third read and then stays stable.
+	responses := []string{"data 00,01", "data 00,01", "data 00,02"}
+
+	var mu sync.Mutex
+	reads := 0
+	stop := make(chan struct{})
+
+	handler := func(cmd string) string {
+		if cmd == "ping" {
+			return "OK:pong\n"
+		}
+		mu.Lock()
+		defer mu.Unlock()
+		resp := responses[len(responses)-1]
+		if reads < len(responses) {
+			resp = responses[reads]
+		}
+		reads++
+		if reads == 5 {
+			close(stop)
+		}
+		return "OK:" + resp + "\n"
+	}
+
+	ms := startMockServer(t, handler)
+	client := atticprotocol.NewClient()
+	if err := client.Connect(ms.socketPath); err != nil {
+		t.Fatalf("failed to connect to mock server: %v", err)
+	}
+	t.Cleanup(func() { client.Disconnect() })
+
+	var out strings.Builder
+	if err := watchMemory(client, 0x0600, 2, time.Millisecond, stop, &out); err != nil {
+		t.Fatalf("watchMemory failed: %v", err)
+	}
+
+	want := "$0600: 00 01\n$0600: 00 02\n"
+	if out.String() != want {
+		t.Errorf("watch output = %q, want %q", out.String(), want)
+	}
+}
+
+// TestWatchMemoryReadError verifies that a server error ends the watch.
+func TestWatchMemoryReadError(t *testing.T) {
+	handler := func(cmd string) string {
+		if cmd == "ping" {
+			return "OK:pong\n"
+		}
+		return "ERR:Invalid address\n"
+	}
+
+	ms := startMockServer(t, handler)
+	client := atticprotocol.NewClient()
+	if err := client.Connect(ms.socketPath); err != nil {
+		t.Fatalf("failed to connect to mock server: %v", err)
+	}
+	t.Cleanup(func() { client.Disconnect() })
+
+	var out strings.Builder
+	if err := watchMemory(client, 0x0600, 2, time.Millisecond, make(chan struct{}), &out); err == nil {
+		t.Error("expected error from failing read")
+	}
+}
+
+// TestFormatWatchBytes verifies hex dump line splitting.
+func TestFormatWatchBytes(t *testing.T) {
+	data := make([]byte, 18)
+	data[16] = 0xFF
+	got := formatWatchBytes(0x0600, data)
+	lines := strings.Split(got, "\n")
+	if len(lines) != 2 {
+		t.Fatalf("expected 2 lines, got %d:\n%s", len(lines), got)
+	}
+	if lines[1] != "$0610: FF 00" {
+		t.Errorf("second line = %q, want %q", lines[1], "$0610: FF 00")
+	}
+}
+
+// TestREPLWatchPiped verifies that a piped watch stops on its own and
+// leaves the following script lines to the REPL.
+func TestREPLWatchPiped(t *testing.T) {
+	defer func(d time.Duration) { pipedWatchDuration = d }(pipedWatchDuration)
+	pipedWatchDuration = 50 * time.Millisecond
+
+	var mu sync.Mutex
+	var received []string
+	output := captureREPL(t, ".monitor\n.watchmem $0600 2 10ms\nstatus\n.quit\n", func(cmd string) string {
+		if cmd == "ping" {
+			return "OK:pong\n"
+		}
+		mu.Lock()
+		received = append(received, cmd)
+		mu.Unlock()
+		if cmd == "status" {
+			return "OK:status paused PC=$0600\n"
+		}
+		return "OK:data 00,01\n"
+	})
+
+	mu.Lock()
+	defer mu.Unlock()
+	if len(received) == 0 || received[len(received)-1] != "status" {
+		t.Errorf("the line after .watchmem should still run, server received %q", received)
+	}
+	if !strings.Contains(output, "$0600: 00 01") || !strings.Contains(output, "status paused") {
+		t.Errorf("expected the watch and the status response, got:\n%s", output)
+	}
+}
+
+// TestREPLWatchChunked verifies that a long watch is read in chunks that
+// fit the protocol's line length.
+func TestREPLWatchChunked(t *testing.T) {
+	output := captureREPLWithOptions(t, ".watchmem $2000 2048\n.quit\n", nil, replOptions{dryRun: true})
+	want := "CMD:read $2000 1024\nCMD:read $2400 1024\n"
+	if !strings.Contains(output, want) {
+		t.Errorf("expected %q, got:\n%s", want, output)
+	}
+}
@@ -0,0 +1,153 @@
+package atticprotocol
+
+import (
+	"strings"
+	"sync"
+	"unicode/utf8"
+)
+
+// ATASCIIEOL is the ATASCII end-of-line character.
+const ATASCIIEOL = 0x9B
+
+// ANSI reverse video on and off, used by DecodeATASCII for inverse video.
+const (
+	ansiReverseOn  = "\x1b[7m"
+	ansiReverseOff = "\x1b[27m"
+)
+
+// atasciiGraphics holds the closest Unicode glyphs for the ATASCII graphics
+// characters $00-$1F. It matches the server's table for "basic LIST ATASCII".
+var atasciiGraphics = [32]rune{
+	'♥', '├', '⎹', '┘', '┤', '┐', '╱', '╲', // $00-$07
+	'◢', '▗', '◣', '▝', '▘', '⎺', '⎽', '▖', // $08-$0F
+	'♣', '┌', '─', '┼', '•', '▄', '⎸', '┬', // $10-$17
+	'┴', '▌', '└', '␛', '↑', '↓', '←', '→', // $18-$1F
+}
+
+// atasciiInverseGlyphs maps glyphs to their exact Unicode complement, for
+// the few ATASCII characters whose inverse video form exists as a glyph.
+var atasciiInverseGlyphs = map[rune]rune{
+	' ': '█',
+	'▄': '▀',
+	'▌': '▐',
+	'▗': '▛',
+	'▖': '▜',
+	'▝': '▙',
+	'▘': '▟',
+	'◢': '◤',
+	'◣': '◥',
+}
+
+// ATASCIIToUnicode returns the Unicode glyph for an ATASCII character. Bit
+// 7 of b selects inverse video on the Atari and is ignored here; pass
+// inverse to get the inverse glyph instead, which differs from the normal
+// one only where Unicode has an exact complement (space becomes █, ▄
+// becomes ▀, and so on). Other inverse characters need a marker such as
+// ANSI reverse video, which DecodeATASCII adds.
+func ATASCIIToUnicode(b byte, inverse bool) rune {
+	b &= 0x7F
+	var r rune
+	switch {
+	case b < 0x20:
+		r = atasciiGraphics[b]
+	case b == 0x60:
+		r = '♦'
+	case b == 0x7B:
+		r = '♠'
+	case b == 0x7D:
+		r = '▸'
+	case b == 0x7E:
+		r = '◀'
+	case b == 0x7F:
+		r = '▶'
+	default:
+		r = rune(b)
+	}
+	if inverse {
+		if complement, ok := atasciiInverseGlyphs[r]; ok {
+			return complement
+		}
+	}
+	return r
+}
+
+// DecodeATASCII converts ATASCII text to a displayable string, in the same
+// form the server uses for "basic LIST ATASCII": EOL becomes a newline and
+// inverse video characters are wrapped in ANSI reverse video.
+func DecodeATASCII(data []byte) string {
+	var sb strings.Builder
+	for _, b := range data {
+		switch {
+		case b == ATASCIIEOL:
+			sb.WriteByte('\n')
+		case b&0x80 != 0:
+			sb.WriteString(ansiReverseOn)
+			sb.WriteRune(ATASCIIToUnicode(b, false))
+			sb.WriteString(ansiReverseOff)
+		default:
+			sb.WriteRune(ATASCIIToUnicode(b, false))
+		}
+	}
+	return sb.String()
+}
+
+// unicodeToATASCII is the inverse of ATASCIIToUnicode, built on first use.
+var unicodeToATASCII = sync.OnceValue(func() map[rune]byte {
+	m := make(map[rune]byte)
+	for b := 0; b < 0x80; b++ {
+		m[ATASCIIToUnicode(byte(b), false)] = byte(b)
+		if inv := ATASCIIToUnicode(byte(b), true); inv != ATASCIIToUnicode(byte(b), false) {
+			m[inv] = byte(b) | 0x80
+		}
+	}
+	// The characters ATASCII draws as glyphs still type as themselves.
+	for _, c := range "`{}~" {
+		m[c] = byte(c)
+	}
+	return m
+})
+
+// UnicodeToATASCII returns the ATASCII character for r, reversing
+// ATASCIIToUnicode: graphics glyphs map to $00-$1F and exact inverse glyphs
+// such as █ map to their inverse video code. A newline maps to EOL. Control
+// characters and runes with no ATASCII equivalent return (0, false).
+func UnicodeToATASCII(r rune) (byte, bool) {
+	if r == '\n' {
+		return ATASCIIEOL, true
+	}
+	if r < 0x20 || r == 0x7F {
+		return 0, false
+	}
+	b, ok := unicodeToATASCII()[r]
+	return b, ok
+}
+
+// EncodeATASCII converts text in the form DecodeATASCII produces back to
+// ATASCII, so listings can be pasted back into the emulator. ANSI reverse
+// video spans set bit 7 on the characters inside them. It returns false if
+// any rune has no ATASCII equivalent.
+func EncodeATASCII(s string) ([]byte, bool) {
+	var data []byte
+	inverse := false
+	for len(s) > 0 {
+		if strings.HasPrefix(s, ansiReverseOn) {
+			inverse, s = true, s[len(ansiReverseOn):]
+			continue
+		}
+		if strings.HasPrefix(s, ansiReverseOff) {
+			inverse, s = false, s[len(ansiReverseOff):]
+			continue
+		}
+		r, size := utf8.DecodeRuneInString(s)
+		s = s[size:]
+		b, ok := UnicodeToATASCII(r)
+		if !ok {
+			return nil, false
+		}
+		if inverse && b != ATASCIIEOL {
+			b |= 0x80
+		}
+		data = append(data, b)
+	}
+	return data, true
+}
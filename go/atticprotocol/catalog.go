@@ -0,0 +1,100 @@
+package atticprotocol
+
+import "strings"
+
+// CommandInfo describes a protocol command keyword for help and completion.
+type CommandInfo struct {
+	Name    string   // Canonical keyword sent on the wire
+	Aliases []string // Alternative keywords accepted by the parser
+	Summary string   // One-line description
+}
+
+// commandCatalog lists every top-level command keyword. The parser resolves
+// aliases through this table, so it is the single source of truth for which
+// keywords exist.
+var commandCatalog = []CommandInfo{
+	// Connection
+	{Name: "ping", Summary: "Check that the server is alive"},
+	{Name: "version", Summary: "Show the server protocol version"},
+	{Name: "capabilities", Summary: "List optional features the server supports"},
+	{Name: "log", Summary: "Show or clear the server's recent command log"},
+	{Name: "quit", Summary: "Close this client connection"},
+	{Name: "shutdown", Summary: "Stop the server"},
+
+	// Emulator control
+	{Name: "pause", Summary: "Pause emulation"},
+	{Name: "resume", Summary: "Resume emulation"},
+	{Name: "step", Summary: "Run one or more video frames"},
+	{Name: "frame", Aliases: []string{"fr"}, Summary: "Advance to the next vertical blank, one or more times"},
+	{Name: "reset", Summary: "Cold or warm reset"},
+	{Name: "status", Summary: "Show emulator status"},
+	{Name: "audio", Summary: "Turn sound output on or off"},
+	{Name: "cycles", Summary: "Show the CPU cycle counter"},
+	{Name: "video", Summary: "Show or switch the video system (NTSC/PAL)"},
+
+	// Memory
+	{Name: "read", Summary: "Read bytes from memory"},
+	{Name: "write", Summary: "Write bytes to memory"},
+	{Name: "registers", Summary: "Show or set CPU registers"},
+	{Name: "memmap", Summary: "Describe the ROM, RAM and I/O regions"},
+	{Name: "checksum", Aliases: []string{"crc"}, Summary: "Checksum a memory range"},
+
+	// Breakpoints
+	{Name: "breakpoint", Summary: "Set, clear, enable, disable or list breakpoints"},
+
+	// Assembly
+	{Name: "disassemble", Aliases: []string{"disasm", "d"}, Summary: "Disassemble memory"},
+	{Name: "assemble", Aliases: []string{"asm", "a"}, Summary: "Assemble instructions into memory"},
+
+	// Monitor
+	{Name: "stepi", Aliases: []string{"si"}, Summary: "Execute one or more 6502 instructions"},
+	{Name: "stepover", Aliases: []string{"so"}, Summary: "Step over a subroutine call"},
+	{Name: "stepout", Aliases: []string{"sr"}, Summary: "Run until the current subroutine returns"},
+	{Name: "until", Aliases: []string{"rununtil"}, Summary: "Run until an address (or 'ret') is reached"},
+	{Name: "fill", Summary: "Fill a memory range with a value"},
+	{Name: "pattern", Summary: "Fill a memory range with a repeating byte pattern"},
+	{Name: "trace", Summary: "Log executed instructions to a file"},
+
+	// Disks and files
+	{Name: "mount", Summary: "Mount a disk image in a drive"},
+	{Name: "unmount", Summary: "Unmount a drive"},
+	{Name: "drives", Summary: "List mounted drives"},
+	{Name: "boot", Summary: "Load and boot a file"},
+	{Name: "bootinfo", Summary: "Show what the emulator booted from"},
+	{Name: "state", Summary: "Save or load emulator state"},
+
+	// Display and input
+	{Name: "screenshot", Summary: "Save a screenshot"},
+	{Name: "screen", Summary: "Read the text on screen"},
+	{Name: "gfx", Summary: "Show or set the GRAPHICS mode"},
+	{Name: "inject", Summary: "Inject keystrokes or a BASIC program"},
+
+	// Subsystems
+	{Name: "basic", Summary: "BASIC program commands"},
+	{Name: "dos", Summary: "DOS disk commands"},
+}
+
+// AllCommands returns information about every top-level protocol command,
+// in catalog order.
+func AllCommands() []CommandInfo {
+	commands := make([]CommandInfo, len(commandCatalog))
+	copy(commands, commandCatalog)
+	return commands
+}
+
+// LookupCommand finds a command by its keyword or one of its aliases.
+// Matching is case-insensitive.
+func LookupCommand(keyword string) (CommandInfo, bool) {
+	keyword = strings.ToLower(keyword)
+	for _, info := range commandCatalog {
+		if info.Name == keyword {
+			return info, true
+		}
+		for _, alias := range info.Aliases {
+			if alias == keyword {
+				return info, true
+			}
+		}
+	}
+	return CommandInfo{}, false
+}
@@ -3,8 +3,12 @@ package atticprotocol
 import (
 	"bufio"
 	"context"
+	"errors"
 	"fmt"
+	"io"
 	"net"
+	"strconv"
+	"strings"
 	"sync"
 	"sync/atomic"
 	"time"
@@ -28,26 +32,84 @@ type DisconnectHandler func(err error)
 type Client struct {
 	mu sync.Mutex
 
-	conn          net.Conn
-	connectedPath string
-	isConnected   bool
+	conn             net.Conn
+	connectedNetwork string
+	connectedPath    string
+	isConnected      bool
 
-	reader *bufio.Reader
+	reader         *bufio.Reader
+	readBufferSize int // Size of reader's buffer, 0 for DefaultReadBufferSize
 
 	// Response channel for pending requests
 	pendingResponse chan responseResult
 	requestID       atomic.Uint64
 
+	// Requests waiting for a response on the current connection
+	inFlight *sync.WaitGroup
+
 	// Handlers for async events
 	eventHandler      EventHandler
 	disconnectHandler DisconnectHandler
 
+	// Events received while no handler is set, oldest first
+	pendingEvents []Event
+
 	// Parsers
 	responseParser *ResponseParser
 
 	// Cancellation for the reader goroutine
 	cancelReader context.CancelFunc
 	readerDone   chan struct{}
+
+	// Version reported by the server, cached by serverVersionInfo (nil until queried)
+	serverVersion *ServerVersionInfo
+
+	// Feature set reported by the server, cached by Supports (nil until queried)
+	capabilities map[string]bool
+
+	// Retry policy for DiscoverAndConnect
+	discoverAttempts int
+	discoverInterval time.Duration
+
+	// Directory DiscoverAndConnect searches; "" means SocketDir()
+	socketDir string
+
+	// Commands sent while SendWithRetry reconnects wait in queue, oldest
+	// first, when queueLimit is above zero
+	queueLimit   int
+	reconnecting bool
+	queue        []*queuedCommand
+
+	// Per-command-type timeouts used by Send, overriding CommandTimeout
+	commandTimeouts map[CommandType]time.Duration
+
+	// Multi-line separator set with SetSeparator, and the one the server
+	// advertised in its capabilities, which takes precedence. "" means
+	// MultiLineSeparator.
+	separator           string
+	negotiatedSeparator string
+
+	// Protocol trace, nil when off. traceMu serializes writes from callers
+	// and the reader goroutine without holding mu during I/O.
+	traceMu     sync.Mutex
+	traceWriter io.Writer
+}
+
+// defaultCommandTimeouts lists the command types whose default timeout
+// differs from CommandTimeout: quick queries fail fast, file-writing
+// commands get longer.
+var defaultCommandTimeouts = map[CommandType]time.Duration{
+	CmdPing:         PingTimeout,
+	CmdVersion:      PingTimeout,
+	CmdCapabilities: PingTimeout,
+	CmdScreenshot:   LongCommandTimeout,
+	CmdStateSave:    LongCommandTimeout,
+	CmdStateLoad:    LongCommandTimeout,
+	CmdBoot:         LongCommandTimeout,
+	CmdDosNewDisk:   LongCommandTimeout,
+	CmdDosFormat:    LongCommandTimeout,
+	CmdDosImport:    LongCommandTimeout,
+	CmdDosExport:    LongCommandTimeout,
 }
 
 // responseResult wraps a response or error from the server.
@@ -56,10 +118,19 @@ type responseResult struct {
 	err      error
 }
 
+// queuedCommand is a command line waiting for a reconnect to finish.
+type queuedCommand struct {
+	ctx    context.Context
+	line   string
+	result chan responseResult // Buffered, so flushing never blocks
+}
+
 // NewClient creates a new CLI socket client.
 func NewClient() *Client {
 	return &Client{
-		responseParser: NewResponseParser(),
+		responseParser:   NewResponseParser(),
+		discoverAttempts: DefaultDiscoverAttempts,
+		discoverInterval: DefaultDiscoverRetryInterval,
 	}
 }
 
@@ -70,6 +141,48 @@ func (c *Client) SetEventHandler(handler EventHandler) {
 	c.eventHandler = handler
 }
 
+// EventHandler returns the current event callback, or nil if none is set.
+// Callers that temporarily replace the handler can use it to restore or
+// chain to the previous one.
+func (c *Client) EventHandler() EventHandler {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.eventHandler
+}
+
+// TryReceiveEvent returns the oldest buffered event without blocking, or
+// (Event{}, false) if none is pending. Events are buffered only while no
+// event handler is set; a handler receives them directly instead.
+func (c *Client) TryReceiveEvent() (Event, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if len(c.pendingEvents) == 0 {
+		return Event{}, false
+	}
+	event := c.pendingEvents[0]
+	c.pendingEvents = c.pendingEvents[1:]
+	return event, true
+}
+
+// SetTraceWriter logs the protocol conversation to w, one line per
+// message: commands sent are prefixed "> ", responses "< " and async events
+// "!! ", so events arriving between requests are visible in order. A nil w
+// turns tracing off. Writes to w are serialized.
+func (c *Client) SetTraceWriter(w io.Writer) {
+	c.traceMu.Lock()
+	defer c.traceMu.Unlock()
+	c.traceWriter = w
+}
+
+// trace writes one line of the protocol conversation if tracing is on.
+func (c *Client) trace(prefix, line string) {
+	c.traceMu.Lock()
+	defer c.traceMu.Unlock()
+	if c.traceWriter != nil {
+		fmt.Fprintf(c.traceWriter, "%s%s\n", prefix, strings.TrimRight(line, "\r\n"))
+	}
+}
+
 // SetDisconnectHandler sets the callback for disconnection events.
 func (c *Client) SetDisconnectHandler(handler DisconnectHandler) {
 	c.mu.Lock()
@@ -84,7 +197,7 @@ func (c *Client) IsConnected() bool {
 	return c.isConnected
 }
 
-// ConnectedPath returns the path of the currently connected socket.
+// ConnectedPath returns the socket path (or TCP address) of the current connection.
 // Returns empty string if not connected.
 func (c *Client) ConnectedPath() string {
 	c.mu.Lock()
@@ -99,10 +212,41 @@ func (c *Client) Connect(path string) error {
 
 // ConnectWithContext connects to an AtticServer socket with a context for cancellation.
 func (c *Client) ConnectWithContext(ctx context.Context, path string) error {
+	return c.connect(ctx, "unix", path)
+}
+
+// ConnectTCP connects to an AtticServer listening on a TCP address
+// ("host:port"), for example one reached through an SSH tunnel.
+func (c *Client) ConnectTCP(address string) error {
+	return c.ConnectTCPWithContext(context.Background(), address)
+}
+
+// ConnectTCPWithContext connects to a TCP address with a context for cancellation.
+func (c *Client) ConnectTCPWithContext(ctx context.Context, address string) error {
+	return c.connect(ctx, "tcp", address)
+}
+
+// connect dials the server on the given network ("unix" or "tcp"), starts
+// the reader goroutine, and verifies the connection with a ping.
+//
+// Connecting again to the server the client is already connected to is a
+// no-op. Connecting to a different one while connected fails with an error
+// wrapping ErrAlreadyConnected; call Disconnect first.
+func (c *Client) connect(ctx context.Context, network, path string) error {
 	c.mu.Lock()
 	if c.isConnected {
+		current, currentNetwork := c.connectedPath, c.connectedNetwork
 		c.mu.Unlock()
-		return ErrAlreadyConnected
+		if current == path && currentNetwork == network {
+			return nil
+		}
+		return fmt.Errorf("%w to %s; call Disconnect before connecting to %s", ErrAlreadyConnected, current, path)
+	}
+	// The server may have dropped the previous connection, in which case
+	// the reader has stopped but the socket is still open.
+	if c.conn != nil {
+		c.conn.Close()
+		c.conn = nil
 	}
 	c.mu.Unlock()
 
@@ -110,34 +254,42 @@ func (c *Client) ConnectWithContext(ctx context.Context, path string) error {
 	connectCtx, cancel := context.WithTimeout(ctx, ConnectionTimeout)
 	defer cancel()
 
-	// Dial the Unix socket
+	// Dial the socket
 	var d net.Dialer
-	conn, err := d.DialContext(connectCtx, "unix", path)
+	conn, err := d.DialContext(connectCtx, network, path)
 	if err != nil {
 		return NewConnectionError("failed to connect", err)
 	}
 
 	c.mu.Lock()
 	c.conn = conn
+	c.connectedNetwork = network
 	c.connectedPath = path
 	c.isConnected = true
-	c.reader = bufio.NewReader(conn)
+	bufferSize := c.readBufferSize
+	if bufferSize <= 0 {
+		bufferSize = DefaultReadBufferSize
+	}
+	c.reader = bufio.NewReaderSize(conn, bufferSize)
 	c.pendingResponse = make(chan responseResult, 1)
+	c.inFlight = &sync.WaitGroup{}
 
 	// Create cancellation context for reader
 	readerCtx, cancelReader := context.WithCancel(context.Background())
 	c.cancelReader = cancelReader
-	c.readerDone = make(chan struct{})
+	readerDone := make(chan struct{})
+	c.readerDone = readerDone
 
 	// Start reader goroutine
-	go c.readerLoop(readerCtx)
+	go c.readerLoop(readerCtx, readerDone)
 	c.mu.Unlock()
 
 	// Verify connection with ping
 	pingCtx, pingCancel := context.WithTimeout(ctx, PingTimeout)
 	defer pingCancel()
 
-	resp, err := c.SendWithContext(pingCtx, NewPingCommand())
+	// The ping bypasses the reconnect queue, which waits on this connect.
+	resp, err := c.sendLine(pingCtx, NewPingCommand().FormatLine(), false)
 	if err != nil {
 		c.Disconnect()
 		return NewConnectionError("ping failed", err)
@@ -151,6 +303,10 @@ func (c *Client) ConnectWithContext(ctx context.Context, path string) error {
 }
 
 // Disconnect disconnects from the server.
+//
+// New commands are rejected immediately. A command already waiting for its
+// response gets up to DisconnectGracePeriod to complete; after that it is
+// failed with ErrDisconnected and the socket is closed.
 func (c *Client) Disconnect() {
 	c.mu.Lock()
 	if !c.isConnected {
@@ -159,16 +315,33 @@ func (c *Client) Disconnect() {
 	}
 
 	c.isConnected = false
+	inFlight := c.inFlight
+	c.mu.Unlock()
+
+	// Let in-flight commands finish while the reader is still running.
+	if inFlight != nil {
+		done := make(chan struct{})
+		go func() {
+			inFlight.Wait()
+			close(done)
+		}()
+		select {
+		case <-done:
+		case <-time.After(DisconnectGracePeriod):
+		}
+	}
 
 	// Cancel reader goroutine
+	c.mu.Lock()
 	if c.cancelReader != nil {
 		c.cancelReader()
 	}
+	readerDone := c.readerDone
 	c.mu.Unlock()
 
 	// Wait for reader to finish (outside lock to avoid deadlock)
-	if c.readerDone != nil {
-		<-c.readerDone
+	if readerDone != nil {
+		<-readerDone
 	}
 
 	c.mu.Lock()
@@ -189,17 +362,190 @@ func (c *Client) Disconnect() {
 		c.pendingResponse = nil
 	}
 
+	c.connectedNetwork = ""
 	c.connectedPath = ""
+	c.inFlight = nil
+	c.serverVersion = nil
+	c.capabilities = nil
+	c.negotiatedSeparator = ""
 	c.reader = nil
 	c.cancelReader = nil
 	c.readerDone = nil
 	c.mu.Unlock()
 }
 
-// Send sends a command to the server and waits for a response.
-// Uses the default CommandTimeout.
+// ServerVersion returns the protocol version reported by the connected
+// server (e.g. "1.0"). The server is queried with the version command the
+// first time; the result is cached until the client disconnects.
+// Returns an empty string if the version could not be determined.
+func (c *Client) ServerVersion() string {
+	info, ok := c.serverVersionInfo()
+	if !ok {
+		return ""
+	}
+	return info.Version
+}
+
+// serverVersionInfo queries and caches the server's parsed version. It
+// reports false if the server could not be asked or the response did not
+// contain a version.
+func (c *Client) serverVersionInfo() (ServerVersionInfo, bool) {
+	c.mu.Lock()
+	cached := c.serverVersion
+	c.mu.Unlock()
+	if cached != nil {
+		return *cached, true
+	}
+
+	resp, err := c.SendWithTimeout(NewVersionCommand(), PingTimeout)
+	if err != nil {
+		return ServerVersionInfo{}, false
+	}
+	info, err := resp.AsVersion()
+	if err != nil {
+		return ServerVersionInfo{}, false
+	}
+
+	c.mu.Lock()
+	c.serverVersion = &info
+	c.mu.Unlock()
+	return info, true
+}
+
+// CheckServerVersion verifies that the server's protocol version is
+// compatible with this client. It returns a *VersionMismatchError if the
+// version is incompatible or could not be determined. Callers typically
+// report this as a warning rather than disconnecting.
+func (c *Client) CheckServerVersion() error {
+	info, ok := c.serverVersionInfo()
+	if !ok || !info.IsCompatible() {
+		return &VersionMismatchError{ServerVersion: info.Version, ClientVersion: ProtocolVersion}
+	}
+	return nil
+}
+
+// Supports reports whether the connected server advertises the given
+// feature in its capabilities response. The server is queried once per
+// connection; servers that predate the capabilities command support no
+// optional features, so new commands can be skipped gracefully.
+func (c *Client) Supports(feature string) bool {
+	return c.loadCapabilities()[strings.ToLower(feature)]
+}
+
+// loadCapabilities returns the server's capabilities, querying them on
+// first use, and adopts a separator the server advertises. It returns nil
+// if the server could not be asked.
+func (c *Client) loadCapabilities() map[string]bool {
+	c.mu.Lock()
+	capabilities := c.capabilities
+	c.mu.Unlock()
+	if capabilities != nil {
+		return capabilities
+	}
+
+	resp, err := c.SendWithTimeout(NewCapabilitiesCommand(), PingTimeout)
+	if err != nil {
+		return nil // Not connected or no answer; try again next time
+	}
+	capabilities, err = resp.AsCapabilities()
+	if err != nil {
+		capabilities = map[string]bool{}
+	}
+	c.mu.Lock()
+	c.capabilities = capabilities
+	c.negotiatedSeparator = separatorCapability(capabilities)
+	c.mu.Unlock()
+	return capabilities
+}
+
+// separatorCapability returns the separator advertised as "separator=XX",
+// XX being the character code in hex, or "" if there is none.
+func separatorCapability(capabilities map[string]bool) string {
+	for name := range capabilities {
+		code, ok := strings.CutPrefix(name, "separator=")
+		if !ok {
+			continue
+		}
+		if b, err := strconv.ParseUint(code, 16, 8); err == nil && b != '\n' {
+			return string(rune(b))
+		}
+	}
+	return ""
+}
+
+// SetSeparator sets the string that separates lines in multi-line
+// responses; "" restores MultiLineSeparator. Responses received afterwards
+// split on it in Response.Lines. A separator the server advertises in its
+// capabilities (see NegotiateSeparator) takes precedence.
+func (c *Client) SetSeparator(sep string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.separator = sep
+}
+
+// Separator returns the multi-line separator in effect.
+func (c *Client) Separator() string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.separatorLocked()
+}
+
+// separatorLocked returns the separator in effect. c.mu must be held.
+func (c *Client) separatorLocked() string {
+	switch {
+	case c.negotiatedSeparator != "":
+		return c.negotiatedSeparator
+	case c.separator != "":
+		return c.separator
+	}
+	return MultiLineSeparator
+}
+
+// NegotiateSeparator queries the server's capabilities, if not already
+// known, and adopts the separator it advertises as "separator=XX" (the
+// character code in hex). Servers that advertise none, or predate the
+// capabilities command, keep the configured separator. It returns the
+// separator in effect.
+func (c *Client) NegotiateSeparator() string {
+	c.loadCapabilities()
+	return c.Separator()
+}
+
+// SetCommandTimeout overrides the timeout Send uses for commands of type t.
+// A duration of zero or less restores the default.
+func (c *Client) SetCommandTimeout(t CommandType, d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if d <= 0 {
+		delete(c.commandTimeouts, t)
+		return
+	}
+	if c.commandTimeouts == nil {
+		c.commandTimeouts = make(map[CommandType]time.Duration)
+	}
+	c.commandTimeouts[t] = d
+}
+
+// CommandTimeoutFor returns the timeout Send uses for commands of type t:
+// an override set with SetCommandTimeout, else the built-in default for
+// the type, else CommandTimeout.
+func (c *Client) CommandTimeoutFor(t CommandType) time.Duration {
+	c.mu.Lock()
+	d, ok := c.commandTimeouts[t]
+	c.mu.Unlock()
+	if ok {
+		return d
+	}
+	if d, ok := defaultCommandTimeouts[t]; ok {
+		return d
+	}
+	return CommandTimeout
+}
+
+// Send sends a command to the server and waits for a response, using the
+// timeout for the command's type (see CommandTimeoutFor).
 func (c *Client) Send(cmd Command) (Response, error) {
-	return c.SendWithTimeout(cmd, CommandTimeout)
+	return c.SendWithContext(context.Background(), cmd)
 }
 
 // SendWithTimeout sends a command with a custom timeout.
@@ -210,31 +556,22 @@ func (c *Client) SendWithTimeout(cmd Command, timeout time.Duration) (Response,
 }
 
 // SendWithContext sends a command with a context for cancellation/timeout.
+// If ctx has no deadline, the timeout for the command's type applies.
 func (c *Client) SendWithContext(ctx context.Context, cmd Command) (Response, error) {
-	c.mu.Lock()
-	if !c.isConnected {
-		c.mu.Unlock()
-		return Response{}, ErrNotConnected
-	}
-
-	conn := c.conn
-	pendingChan := c.pendingResponse
-	c.mu.Unlock()
-
-	// Format and send command
-	line := cmd.FormatLine()
-	_, err := conn.Write([]byte(line))
-	if err != nil {
-		return Response{}, NewConnectionError("failed to send command", err)
+	if _, ok := ctx.Deadline(); !ok {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, c.CommandTimeoutFor(cmd.Type))
+		defer cancel()
 	}
+	return c.sendLine(ctx, cmd.FormatLine(), !cmd.NoQueue)
+}
 
-	// Wait for response or timeout
-	select {
-	case result := <-pendingChan:
-		return result.response, result.err
-	case <-ctx.Done():
-		return Response{}, ErrTimeout
-	}
+// SendTimed sends a command like Send and also returns the round-trip
+// time, from writing the command to receiving its response.
+func (c *Client) SendTimed(cmd Command) (Response, time.Duration, error) {
+	start := time.Now()
+	resp, err := c.Send(cmd)
+	return resp, time.Since(start), err
 }
 
 // SendRaw sends a raw command string to the server.
@@ -252,6 +589,39 @@ func (c *Client) SendRawWithTimeout(commandLine string, timeout time.Duration) (
 
 // SendRawWithContext sends a raw command string with a context.
 func (c *Client) SendRawWithContext(ctx context.Context, commandLine string) (Response, error) {
+	return c.sendLine(ctx, fmt.Sprintf("%s%s\n", CommandPrefix, commandLine), true)
+}
+
+// sendLine sends a formatted command line and waits for its response. If
+// queue is set and the client is reconnecting with queueing enabled, the
+// line waits in the reconnect queue first.
+func (c *Client) sendLine(ctx context.Context, line string, queue bool) (Response, error) {
+	c.mu.Lock()
+	if !queue || !c.reconnecting || c.queueLimit <= 0 {
+		c.mu.Unlock()
+		return c.roundTrip(ctx, line)
+	}
+	if len(c.queue) >= c.queueLimit {
+		c.mu.Unlock()
+		return Response{}, ErrQueueFull
+	}
+	q := &queuedCommand{ctx: ctx, line: line, result: make(chan responseResult, 1)}
+	c.queue = append(c.queue, q)
+	c.mu.Unlock()
+
+	select {
+	case result := <-q.result:
+		return result.response, result.err
+	case <-ctx.Done():
+		return Response{}, ErrTimeout
+	}
+}
+
+// roundTrip writes a formatted command line and waits for its response.
+// The whole line is written before waiting, even if the connection takes
+// it in pieces, so the server never sees a truncated command. The request
+// is counted as in flight so Disconnect can let it finish.
+func (c *Client) roundTrip(ctx context.Context, line string) (Response, error) {
 	c.mu.Lock()
 	if !c.isConnected {
 		c.mu.Unlock()
@@ -260,34 +630,240 @@ func (c *Client) SendRawWithContext(ctx context.Context, commandLine string) (Re
 
 	conn := c.conn
 	pendingChan := c.pendingResponse
+	inFlight := c.inFlight
+	inFlight.Add(1)
 	c.mu.Unlock()
+	defer inFlight.Done()
 
-	// Format and send command
-	line := fmt.Sprintf("%s%s\n", CommandPrefix, commandLine)
-	_, err := conn.Write([]byte(line))
-	if err != nil {
+	// Trace before writing so the request always precedes its response.
+	c.trace("> ", line)
+	if err := writeFull(conn, []byte(line)); err != nil {
 		return Response{}, NewConnectionError("failed to send command", err)
 	}
 
-	// Wait for response or timeout
+	// Wait for response or timeout. Disconnect closes the channel if the
+	// response doesn't arrive within its grace period.
 	select {
-	case result := <-pendingChan:
+	case result, ok := <-pendingChan:
+		if !ok {
+			return Response{}, ErrDisconnected
+		}
 		return result.response, result.err
 	case <-ctx.Done():
 		return Response{}, ErrTimeout
 	}
 }
 
-// readerLoop continuously reads from the socket and dispatches responses/events.
-func (c *Client) readerLoop(ctx context.Context) {
-	defer func() {
+// writeFull writes all of data to w, calling Write again after a short
+// write. A Write that makes no progress without an error fails with
+// io.ErrShortWrite rather than looping forever.
+func writeFull(w io.Writer, data []byte) error {
+	for len(data) > 0 {
+		n, err := w.Write(data)
+		if err != nil {
+			return err
+		}
+		if n <= 0 {
+			return io.ErrShortWrite
+		}
+		data = data[n:]
+	}
+	return nil
+}
+
+// SendWithRetry sends a command, retrying up to attempts times in total if
+// it fails with a transport error (lost connection or timeout). Between
+// attempts the client reconnects to the server it was connected to. Only
+// idempotent commands (see Command.IsIdempotent) are retried; anything that
+// changes emulator state is sent exactly once, since a failed write may
+// still have reached the server. Error responses are returned as-is and
+// never retried.
+func (c *Client) SendWithRetry(cmd Command, attempts int) (Response, error) {
+	if attempts < 1 || !cmd.IsIdempotent() {
+		attempts = 1
+	}
+
+	var resp Response
+	var err error
+	for attempt := 1; attempt <= attempts; attempt++ {
+		resp, err = c.Send(cmd)
+		if err == nil || !isTransientError(err) || attempt == attempts {
+			break
+		}
+		if reconnectErr := c.reconnect(); reconnectErr != nil {
+			err = reconnectErr
+		}
+	}
+	return resp, err
+}
+
+// isTransientError reports whether a Send error is worth a retry after
+// reconnecting.
+func isTransientError(err error) bool {
+	var connErr *ConnectionError
+	return errors.Is(err, ErrDisconnected) || errors.Is(err, ErrTimeout) ||
+		errors.As(err, &connErr)
+}
+
+// reconnect drops the current connection, including one the server already
+// closed, and dials the same network and path again. It fails with
+// ErrNotConnected if the client was explicitly disconnected. Commands
+// queued meanwhile are sent once it is done.
+func (c *Client) reconnect() error {
+	c.mu.Lock()
+	network, path := c.connectedNetwork, c.connectedPath
+	readerDone := c.readerDone
+	if path != "" {
+		c.reconnecting = true
+	}
+	c.mu.Unlock()
+	if path == "" {
+		return ErrNotConnected
+	}
+
+	// A connection that timed out is still live; drop it so the retry
+	// doesn't pick up the late response. connect closes one the server
+	// already dropped, for which Disconnect returns at once; the old reader
+	// may still be running the disconnect handler, so wait for it before
+	// starting a new one.
+	c.Disconnect()
+	if readerDone != nil {
+		<-readerDone
+	}
+	err := c.connect(context.Background(), network, path)
+	c.flushQueue(err)
+	return err
+}
+
+// flushQueue sends the commands queued during a reconnect in order, or
+// fails them with ErrQueuedThenFailed if reconnecting failed with err.
+// Commands sent meanwhile keep joining the queue until it is empty, so
+// they can't overtake older ones.
+func (c *Client) flushQueue(err error) {
+	for {
 		c.mu.Lock()
-		if c.readerDone != nil {
-			close(c.readerDone)
+		if len(c.queue) == 0 {
+			c.reconnecting = false
+			c.mu.Unlock()
+			return
 		}
+		q := c.queue[0]
+		c.queue = c.queue[1:]
 		c.mu.Unlock()
-	}()
 
+		switch {
+		case err != nil:
+			q.result <- responseResult{err: fmt.Errorf("%w: %v", ErrQueuedThenFailed, err)}
+		case q.ctx.Err() != nil:
+			// The caller has given up waiting; don't send it late.
+		default:
+			resp, sendErr := c.roundTrip(q.ctx, q.line)
+			q.result <- responseResult{response: resp, err: sendErr}
+		}
+	}
+}
+
+// SetReconnectQueue makes commands sent while SendWithRetry is
+// reconnecting wait for the new connection instead of failing with
+// ErrNotConnected. They are sent in order once it is up; if reconnecting
+// fails they fail with ErrQueuedThenFailed. At most limit commands wait at
+// a time; more fail with ErrQueueFull. A limit of zero or less, the
+// default, turns queueing off. Commands with NoQueue set never wait.
+func (c *Client) SetReconnectQueue(limit int) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.queueLimit = limit
+}
+
+// Clone returns a new client with its own connection to the same server,
+// for tools that want independent sessions, such as one goroutine waiting
+// on events while another sends commands. The clone has its own lock,
+// request queue and event buffer, and starts with no event or disconnect
+// handler; settings such as timeouts and the read buffer size are copied.
+//
+// The server must accept more than one connection at a time. If the second
+// connection fails, Clone returns the error and no client. Cloning a client
+// that is not connected gives an unconnected client with the same settings.
+func (c *Client) Clone() (*Client, error) {
+	c.mu.Lock()
+	clone := NewClient()
+	clone.readBufferSize = c.readBufferSize
+	clone.discoverAttempts = c.discoverAttempts
+	clone.discoverInterval = c.discoverInterval
+	clone.socketDir = c.socketDir
+	clone.queueLimit = c.queueLimit
+	clone.separator = c.separator
+	if c.commandTimeouts != nil {
+		clone.commandTimeouts = make(map[CommandType]time.Duration, len(c.commandTimeouts))
+		for t, d := range c.commandTimeouts {
+			clone.commandTimeouts[t] = d
+		}
+	}
+	network, path, connected := c.connectedNetwork, c.connectedPath, c.isConnected
+	c.mu.Unlock()
+
+	if connected {
+		if err := clone.connect(context.Background(), network, path); err != nil {
+			return nil, err
+		}
+	}
+	return clone, nil
+}
+
+// SetReadBufferSize sets the size of the socket read buffer used from the
+// next Connect on. A larger buffer means fewer reads for very large
+// responses such as long disassembly listings or memory dumps; it never
+// limits the response size. A size of zero or less restores
+// DefaultReadBufferSize.
+func (c *Client) SetReadBufferSize(size int) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.readBufferSize = size
+}
+
+// SendLines sends a logical sequence of raw command lines (e.g. the lines
+// of an assembly listing), one at a time, and returns the responses
+// received. Each line waits as long as Send would for its command type.
+// If a line gets an error response and continueOnError is false, the
+// remaining lines are skipped and the response's *ProtocolError is
+// returned along with the responses so far, including the failing one;
+// with continueOnError set, every line is sent and callers inspect the
+// individual responses. Transport errors always stop the sequence.
+func (c *Client) SendLines(cmds []string, continueOnError bool) ([]Response, error) {
+	parser := NewCommandParser()
+	responses := make([]Response, 0, len(cmds))
+	for _, line := range cmds {
+		timeout := CommandTimeout
+		if cmd, err := parser.Parse(line); err == nil {
+			timeout = c.CommandTimeoutFor(cmd.Type)
+		}
+		resp, err := c.SendRawWithTimeout(line, timeout)
+		if err != nil {
+			return responses, err
+		}
+		responses = append(responses, resp)
+		if err := resp.Err(); err != nil && !continueOnError {
+			return responses, err
+		}
+	}
+	return responses, nil
+}
+
+// readerLoop continuously reads from the socket and dispatches responses/events.
+//
+// A large response can arrive in several chunks, and a chunk boundary may
+// fall on the short read deadline used to check for cancellation. The text
+// read before the deadline is kept in partial and completed by later reads,
+// so each line, including every record-separated part of a multi-line
+// response, is delivered as one Response.
+//
+// done is closed when the loop returns. It is the channel connect created
+// for this reader, not c.readerDone, which a reconnect may already have
+// replaced.
+func (c *Client) readerLoop(ctx context.Context, done chan struct{}) {
+	defer close(done)
+
+	var partial strings.Builder
 	for {
 		select {
 		case <-ctx.Done():
@@ -305,7 +881,8 @@ func (c *Client) readerLoop(ctx context.Context) {
 		reader := c.reader
 		c.mu.Unlock()
 
-		line, err := reader.ReadString('\n')
+		chunk, err := reader.ReadString('\n')
+		partial.WriteString(chunk)
 		if err != nil {
 			// Check if it's a timeout (expected for responsive cancellation)
 			if netErr, ok := err.(net.Error); ok && netErr.Timeout() {
@@ -317,6 +894,8 @@ func (c *Client) readerLoop(ctx context.Context) {
 			return
 		}
 
+		line := partial.String()
+		partial.Reset()
 		c.processLine(line)
 	}
 }
@@ -324,6 +903,11 @@ func (c *Client) readerLoop(ctx context.Context) {
 // processLine handles a received line from the server.
 func (c *Client) processLine(line string) {
 	parsed, err := c.responseParser.Parse(line)
+	if err == nil && parsed.IsEvent {
+		c.trace("!! ", line)
+	} else {
+		c.trace("< ", line)
+	}
 	if err != nil {
 		// Log parse error but don't fail
 		fmt.Printf("[CLIClient] Failed to parse response: %v\n", err)
@@ -331,9 +915,15 @@ func (c *Client) processLine(line string) {
 	}
 
 	if parsed.IsEvent {
-		// Dispatch event to handler
+		// Dispatch event to handler, or buffer it for TryReceiveEvent
 		c.mu.Lock()
 		handler := c.eventHandler
+		if handler == nil {
+			if len(c.pendingEvents) == EventBufferSize {
+				c.pendingEvents = c.pendingEvents[1:]
+			}
+			c.pendingEvents = append(c.pendingEvents, parsed.Event)
+		}
 		c.mu.Unlock()
 
 		if handler != nil {
@@ -343,6 +933,9 @@ func (c *Client) processLine(line string) {
 		// Send response to pending request
 		c.mu.Lock()
 		pendingChan := c.pendingResponse
+		if sep := c.separatorLocked(); sep != MultiLineSeparator {
+			parsed.Response.separator = sep
+		}
 		c.mu.Unlock()
 
 		if pendingChan != nil {
@@ -382,17 +975,81 @@ func (c *Client) handleDisconnect(err error) {
 	}
 }
 
+// SetDiscoverRetry configures how DiscoverAndConnect retries. attempts is the
+// total number of tries (values below 1 mean a single try), and interval is
+// the delay before the first retry; it doubles after each failed attempt up
+// to MaxDiscoverRetryInterval.
+func (c *Client) SetDiscoverRetry(attempts int, interval time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.discoverAttempts = attempts
+	c.discoverInterval = interval
+}
+
+// SetSocketDir sets the directory DiscoverAndConnect looks for server
+// sockets in. An empty dir restores the default, SocketDir.
+func (c *Client) SetSocketDir(dir string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.socketDir = dir
+}
+
 // DiscoverAndConnect attempts to discover a running AtticServer and connect to it.
-// Returns nil if successful, or an error if no server was found or connection failed.
+// If no server is found or the connection fails, it retries with exponential
+// backoff (see SetDiscoverRetry), so a server that is still starting up is
+// picked up once its socket appears.
+// Returns nil if successful, or the last error if all attempts failed.
 func (c *Client) DiscoverAndConnect() error {
 	return c.DiscoverAndConnectWithContext(context.Background())
 }
 
-// DiscoverAndConnectWithContext attempts to discover and connect with a context.
+// DiscoverAndConnectWithContext is DiscoverAndConnect with a context for
+// cancelling the retry loop.
 func (c *Client) DiscoverAndConnectWithContext(ctx context.Context) error {
-	socketPath := DiscoverSocket()
-	if socketPath == "" {
-		return ErrSocketNotFound
+	c.mu.Lock()
+	attempts, delay := c.discoverAttempts, c.discoverInterval
+	c.mu.Unlock()
+	if attempts < 1 {
+		attempts = 1
+	}
+
+	var err error
+	for attempt := 1; ; attempt++ {
+		err = c.DiscoverAndConnectOnceWithContext(ctx)
+		if err == nil || errors.Is(err, ErrAlreadyConnected) || attempt >= attempts {
+			return err
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(delay):
+		}
+		delay *= 2
+		if delay > MaxDiscoverRetryInterval {
+			delay = MaxDiscoverRetryInterval
+		}
+	}
+}
+
+// DiscoverAndConnectOnce makes a single attempt to discover a running
+// AtticServer and connect to it, without retrying.
+func (c *Client) DiscoverAndConnectOnce() error {
+	return c.DiscoverAndConnectOnceWithContext(context.Background())
+}
+
+// DiscoverAndConnectOnceWithContext makes a single discover-and-connect
+// attempt with a context.
+func (c *Client) DiscoverAndConnectOnceWithContext(ctx context.Context) error {
+	c.mu.Lock()
+	dir := c.socketDir
+	c.mu.Unlock()
+	if dir == "" {
+		dir = SocketDir()
+	}
+	socketPath, err := discoverSocketErrIn(dir)
+	if err != nil {
+		return err
 	}
 	return c.ConnectWithContext(ctx, socketPath)
 }
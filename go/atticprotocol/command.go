@@ -12,6 +12,9 @@ const (
 	// Connection commands
 	CmdPing CommandType = iota
 	CmdVersion
+	CmdCapabilities
+	CmdServerLog
+	CmdServerLogClear
 	CmdQuit
 	CmdShutdown
 
@@ -19,19 +22,26 @@ const (
 	CmdPause
 	CmdResume
 	CmdStep
+	CmdFrameStep
 	CmdReset
 	CmdStatus
+	CmdAudio
+	CmdCycles
+	CmdVideoSystem
 
 	// Memory operations
 	CmdRead
 	CmdWrite
 	CmdRegisters
+	CmdMemoryMap
+	CmdMemoryChecksum
 
 	// Breakpoints
 	CmdBreakpointSet
 	CmdBreakpointClear
 	CmdBreakpointClearAll
 	CmdBreakpointList
+	CmdBreakpointEnable
 
 	// Assembly
 	CmdAssemble
@@ -41,9 +51,13 @@ const (
 	CmdDisassemble
 
 	// Monitor
+	CmdStepInstruction
 	CmdStepOver
+	CmdStepOut
 	CmdRunUntil
 	CmdMemoryFill
+	CmdMemoryPattern
+	CmdTrace
 
 	// Disk operations
 	CmdMount
@@ -52,6 +66,7 @@ const (
 
 	// Boot with file
 	CmdBoot
+	CmdBootInfo
 
 	// State management
 	CmdStateSave
@@ -60,10 +75,13 @@ const (
 	// Display
 	CmdScreenshot
 	CmdScreenText // Read GRAPHICS 0 screen text
+	CmdGraphicsMode
+	CmdSetGraphicsMode
 
 	// Injection
 	CmdInjectBasic
 	CmdInjectKeys
+	CmdInjectKeyCodes
 
 	// BASIC commands
 	CmdBasicLine
@@ -77,7 +95,14 @@ const (
 	CmdBasicCont
 	CmdBasicVars
 	CmdBasicVar
+	CmdBasicSet
 	CmdBasicInfo
+	CmdBasicFree
+	CmdBasicTrace
+	CmdBasicStep
+	CmdBasicRefs
+	CmdBasicFind
+	CmdBasicTokens
 	CmdBasicExport
 	CmdBasicImport
 	CmdBasicDir
@@ -92,6 +117,7 @@ const (
 	CmdDosType
 	CmdDosDump
 	CmdDosCopy
+	CmdDosDiff
 	CmdDosRename
 	CmdDosDelete
 	CmdDosLock
@@ -100,6 +126,8 @@ const (
 	CmdDosImport
 	CmdDosNewDisk
 	CmdDosFormat
+	CmdDosCheck
+	CmdDosProtect
 )
 
 // RegisterModification represents a register name and value pair for modification.
@@ -115,35 +143,49 @@ type Command struct {
 	Type CommandType
 
 	// Fields used by various commands (only relevant fields are populated)
-	Count         int                    // For step, read, disassemble
+	Count         int                    // For step, frameStep, stepInstruction, read, disassemble
 	Cold          bool                   // For reset
+	Enabled       bool                   // For audio, breakpointEnable, trace, basicTrace, dosProtect
 	Address       uint16                 // For read, write, breakpoints, assemble, etc.
 	AddressSet    bool                   // Whether Address was explicitly provided
-	EndAddress    uint16                 // For memoryFill
-	Data          []byte                 // For write
+	EndAddress    uint16                 // For memoryFill, memoryPattern, memoryChecksum
+	UntilReturn   bool                   // For runUntil: stop at RTS instead of Address
+	HitCount      int                    // For breakpointSet: break on the Nth hit (0 = every hit)
+	Once          bool                   // For breakpointSet: remove after the first stop
+	Condition     string                 // For breakpointSet: only stop while this expression holds
+	Detailed      bool                   // For breakpointList: one breakpoint per line with attributes
+	Data          []byte                 // For write, injectKeyCodes, memoryPattern
 	Modifications []RegisterModification // For registers
 	Drive         int                    // For mount, unmount
-	Path          string                 // For mount, state operations, screenshot
+	Path          string                 // For mount, state operations, screenshot, trace
 	Base64Data    string                 // For injectBasic
-	Text          string                 // For injectKeys
+	Text          string                 // For injectKeys, basicFind
 	Instruction   string                 // For assembleLine
 	Line          string                 // For basicLine
 	Value         byte                   // For memoryFill
 	Lines         int                    // For disassemble
 	LinesSet      bool                   // Whether Lines was explicitly provided
 	LineOrRange   string                 // For basicDelete (e.g., "10" or "10-50")
-	VarName       string                 // For basicVar
+	LineNumber    int                    // For basicRefs
+	VarName       string                 // For basicVar, basicSet
+	VarValue      string                 // For basicSet (a number, or a string variable's text unquoted)
 	Atascii       bool                   // For basicList
 	Start         *int                   // For basicRenumber
 	Step          *int                   // For basicRenumber
 	Filename      string                 // For basicSave, basicLoad, DOS commands
 	Pattern       string                 // For dosDirectory
-	Source        string                 // For dosCopy
-	Destination   string                 // For dosCopy
+	Source        string                 // For dosCopy, dosDiff
+	Destination   string                 // For dosCopy, dosDiff
 	OldName       string                 // For dosRename
 	NewName       string                 // For dosRename
 	HostPath      string                 // For dosExport, dosImport
 	DiskType      string                 // For dosNewDisk (sd, ed, dd)
+	GraphicsMode  int                    // For setGraphicsMode (0-15)
+	VideoSystem   string                 // For videoSystem ("ntsc" or "pal"; "" reads it)
+
+	// NoQueue makes Client.Send fail at once instead of waiting in the
+	// reconnect queue, for time-sensitive commands. It is not sent.
+	NoQueue bool
 }
 
 // Command constructors - these provide a clean API for creating commands.
@@ -158,6 +200,24 @@ func NewVersionCommand() Command {
 	return Command{Type: CmdVersion}
 }
 
+// NewCapabilitiesCommand creates a command that asks the server which
+// optional features it supports.
+func NewCapabilitiesCommand() Command {
+	return Command{Type: CmdCapabilities}
+}
+
+// NewServerLogCommand creates a command to list the server's recent
+// command log, oldest first. Parse the response with Response.AsServerLog.
+func NewServerLogCommand() Command {
+	return Command{Type: CmdServerLog}
+}
+
+// NewServerLogClearCommand creates a command to empty the server's
+// command log.
+func NewServerLogClearCommand() Command {
+	return Command{Type: CmdServerLogClear}
+}
+
 // NewQuitCommand creates a quit command.
 func NewQuitCommand() Command {
 	return Command{Type: CmdQuit}
@@ -178,8 +238,9 @@ func NewResumeCommand() Command {
 	return Command{Type: CmdResume}
 }
 
-// NewStepCommand creates a step command with the given count.
-// If count is 0 or 1, a single step is performed.
+// NewStepCommand creates a command that runs the emulator for count video
+// frames. If count is 0 or 1, a single frame is run. Use
+// NewStepInstructionCommand to step individual 6502 instructions.
 func NewStepCommand(count int) Command {
 	if count <= 0 {
 		count = 1
@@ -187,6 +248,17 @@ func NewStepCommand(count int) Command {
 	return Command{Type: CmdStep, Count: count}
 }
 
+// NewFrameStepCommand creates a command that runs the emulator until the
+// start of the count-th next vertical blank, so it always stops on a frame
+// boundary. NewStepCommand instead runs count frames' worth of cycles from
+// wherever execution is. If count is 0 or 1, one frame is advanced.
+func NewFrameStepCommand(count int) Command {
+	if count <= 0 {
+		count = 1
+	}
+	return Command{Type: CmdFrameStep, Count: count}
+}
+
 // NewResetCommand creates a reset command.
 // If cold is true, performs a cold reset; otherwise, a warm reset.
 func NewResetCommand(cold bool) Command {
@@ -198,6 +270,26 @@ func NewStatusCommand() Command {
 	return Command{Type: CmdStatus}
 }
 
+// NewAudioCommand creates a command to turn the emulator's sound output on
+// or off while it is running.
+func NewAudioCommand(enabled bool) Command {
+	return Command{Type: CmdAudio, Enabled: enabled}
+}
+
+// NewCyclesCommand creates a command to read the CPU cycle counter. Parse
+// the response with Response.AsCycles; the difference between two reads
+// is the time a routine took.
+func NewCyclesCommand() Command {
+	return Command{Type: CmdCycles}
+}
+
+// NewVideoSystemCommand creates a command to switch the emulated video
+// system to "ntsc" or "pal". An empty system reads the current one
+// instead. Switching changes the frame rate and CPU timing.
+func NewVideoSystemCommand(system string) Command {
+	return Command{Type: CmdVideoSystem, VideoSystem: strings.ToLower(system)}
+}
+
 // NewReadCommand creates a read command for the given address and byte count.
 func NewReadCommand(address, count uint16) Command {
 	return Command{Type: CmdRead, Address: address, AddressSet: true, Count: int(count)}
@@ -215,11 +307,45 @@ func NewRegistersCommand(modifications []RegisterModification) Command {
 	return Command{Type: CmdRegisters, Modifications: modifications}
 }
 
+// NewMemoryMapCommand creates a command to describe the ROM, RAM and I/O
+// regions of the address space. Parse the response with
+// Response.AsMemoryMap.
+func NewMemoryMapCommand() Command {
+	return Command{Type: CmdMemoryMap}
+}
+
+// NewMemoryChecksumCommand creates a command to checksum the inclusive
+// range start-end on the server, so a region can be compared without
+// reading it. Parse the response with Response.AsChecksum.
+func NewMemoryChecksumCommand(start, end uint16) Command {
+	return Command{Type: CmdMemoryChecksum, Address: start, AddressSet: true, EndAddress: end}
+}
+
 // NewBreakpointSetCommand creates a command to set a breakpoint at the given address.
 func NewBreakpointSetCommand(address uint16) Command {
 	return Command{Type: CmdBreakpointSet, Address: address, AddressSet: true}
 }
 
+// NewBreakpointSetHitsCommand creates a command to set a breakpoint that only
+// stops execution on the given hit (e.g. the 5th pass through a loop).
+// hits must be at least 1.
+func NewBreakpointSetHitsCommand(address uint16, hits int) Command {
+	return Command{Type: CmdBreakpointSet, Address: address, AddressSet: true, HitCount: hits}
+}
+
+// NewBreakpointSetOnceCommand creates a command to set a temporary
+// breakpoint. The server removes it the first time it stops execution.
+func NewBreakpointSetOnceCommand(address uint16) Command {
+	return Command{Type: CmdBreakpointSet, Address: address, AddressSet: true, Once: true}
+}
+
+// NewBreakpointSetConditionCommand creates a command to set a breakpoint
+// that only stops execution while condition holds, e.g. "A==$00". Set Once
+// on the result for a temporary conditional breakpoint.
+func NewBreakpointSetConditionCommand(address uint16, condition string) Command {
+	return Command{Type: CmdBreakpointSet, Address: address, AddressSet: true, Condition: condition}
+}
+
 // NewBreakpointClearCommand creates a command to clear a breakpoint at the given address.
 func NewBreakpointClearCommand(address uint16) Command {
 	return Command{Type: CmdBreakpointClear, Address: address, AddressSet: true}
@@ -235,6 +361,20 @@ func NewBreakpointListCommand() Command {
 	return Command{Type: CmdBreakpointList}
 }
 
+// NewBreakpointEnableCommand creates a command to enable or disable the
+// breakpoint at the given address. A disabled breakpoint keeps its hit
+// count and condition but does not stop execution.
+func NewBreakpointEnableCommand(address uint16, enabled bool) Command {
+	return Command{Type: CmdBreakpointEnable, Address: address, AddressSet: true, Enabled: enabled}
+}
+
+// NewBreakpointListDetailedCommand creates a command to list all breakpoints
+// with their hit counts, conditions and flags. Parse the response with
+// Response.AsBreakpointsDetailed.
+func NewBreakpointListDetailedCommand() Command {
+	return Command{Type: CmdBreakpointList, Detailed: true}
+}
+
 // NewAssembleCommand creates an assemble command for interactive assembly mode.
 func NewAssembleCommand(address uint16) Command {
 	return Command{Type: CmdAssemble, Address: address, AddressSet: true}
@@ -271,21 +411,62 @@ func NewDisassembleCommand(address *uint16, lines *int) Command {
 	return cmd
 }
 
+// NewStepInstructionCommand creates a command that executes count 6502
+// instructions. If count is 0 or 1, a single instruction is executed.
+func NewStepInstructionCommand(count int) Command {
+	if count <= 0 {
+		count = 1
+	}
+	return Command{Type: CmdStepInstruction, Count: count}
+}
+
 // NewStepOverCommand creates a step-over command.
 func NewStepOverCommand() Command {
 	return Command{Type: CmdStepOver}
 }
 
+// NewStepOutCommand creates a step-out command. The server runs until the
+// current subroutine returns: execution stops after the RTS that pops the
+// stack back above its depth when the command was issued, so nested JSRs
+// run to completion. If no subroutine is active, it stops at the next RTS.
+func NewStepOutCommand() Command {
+	return Command{Type: CmdStepOut}
+}
+
 // NewRunUntilCommand creates a run-until command for the given address.
 func NewRunUntilCommand(address uint16) Command {
 	return Command{Type: CmdRunUntil, Address: address, AddressSet: true}
 }
 
+// NewRunUntilReturnCommand creates a run-until command that stops when the
+// current subroutine returns ("until ret"). It is the run-until form of
+// NewStepOutCommand.
+func NewRunUntilReturnCommand() Command {
+	return Command{Type: CmdRunUntil, UntilReturn: true}
+}
+
 // NewMemoryFillCommand creates a command to fill memory with a value.
 func NewMemoryFillCommand(start, end uint16, value byte) Command {
 	return Command{Type: CmdMemoryFill, Address: start, AddressSet: true, EndAddress: end, Value: value}
 }
 
+// NewMemoryPatternCommand creates a command to fill memory with a
+// repeating multi-byte pattern. The last copy is cut short if the range
+// length is not a multiple of the pattern length.
+func NewMemoryPatternCommand(start, end uint16, pattern []byte) Command {
+	return Command{Type: CmdMemoryPattern, Address: start, AddressSet: true, EndAddress: end, Data: pattern}
+}
+
+// NewTraceCommand creates a command to start logging every executed
+// instruction to a file on the server's host, or, with enabled false, to
+// stop. path is ignored when stopping.
+func NewTraceCommand(enabled bool, path string) Command {
+	if !enabled {
+		return Command{Type: CmdTrace}
+	}
+	return Command{Type: CmdTrace, Enabled: true, Path: path}
+}
+
 // NewMountCommand creates a command to mount a disk image.
 func NewMountCommand(drive int, path string) Command {
 	return Command{Type: CmdMount, Drive: drive, Path: path}
@@ -307,6 +488,12 @@ func NewBootCommand(path string) Command {
 	return Command{Type: CmdBoot, Path: path}
 }
 
+// NewBootInfoCommand creates a command to ask what the emulator booted
+// from. Parse the response with Response.AsBootInfo.
+func NewBootInfoCommand() Command {
+	return Command{Type: CmdBootInfo}
+}
+
 // NewStateSaveCommand creates a command to save emulator state.
 func NewStateSaveCommand(path string) Command {
 	return Command{Type: CmdStateSave, Path: path}
@@ -330,6 +517,18 @@ func NewScreenTextCommand(atascii bool) Command {
 	return Command{Type: CmdScreenText, Atascii: atascii}
 }
 
+// NewGraphicsModeCommand creates a command to query the current BASIC
+// GRAPHICS mode. Parse the response with Response.AsGraphicsMode.
+func NewGraphicsModeCommand() Command {
+	return Command{Type: CmdGraphicsMode}
+}
+
+// NewSetGraphicsModeCommand creates a command to switch the display to
+// GRAPHICS mode (0-15), as BASIC's GRAPHICS statement does.
+func NewSetGraphicsModeCommand(mode int) Command {
+	return Command{Type: CmdSetGraphicsMode, GraphicsMode: mode}
+}
+
 // NewInjectBasicCommand creates a command to inject BASIC data.
 func NewInjectBasicCommand(base64Data string) Command {
 	return Command{Type: CmdInjectBasic, Base64Data: base64Data}
@@ -340,6 +539,13 @@ func NewInjectKeysCommand(text string) Command {
 	return Command{Type: CmdInjectKeys, Text: text}
 }
 
+// NewInjectKeyCodesCommand creates a command to inject raw ATASCII key
+// codes, for control characters (cursor movement, clear screen, ...) that
+// are awkward to express as escaped text.
+func NewInjectKeyCodesCommand(codes []byte) Command {
+	return Command{Type: CmdInjectKeyCodes, Data: codes}
+}
+
 // NewBasicLineCommand creates a command to enter a BASIC line.
 func NewBasicLineCommand(line string) Command {
 	return Command{Type: CmdBasicLine, Line: line}
@@ -388,11 +594,58 @@ func NewBasicVarCommand(name string) Command {
 	return Command{Type: CmdBasicVar, VarName: name}
 }
 
+// NewBasicSetCommand creates a command to assign a BASIC variable. For a
+// string variable (a name ending in "$") value is the text to store, without
+// quotes; otherwise it is a number such as "42" or "-1.5".
+func NewBasicSetCommand(name, value string) Command {
+	return Command{Type: CmdBasicSet, VarName: name, VarValue: value}
+}
+
 // NewBasicInfoCommand creates a command to show BASIC program information.
 func NewBasicInfoCommand() Command {
 	return Command{Type: CmdBasicInfo}
 }
 
+// NewBasicFreeCommand creates a command to show how much memory is free
+// for the BASIC program, like FRE(0). Parse the response with
+// Response.AsBasicFree.
+func NewBasicFreeCommand() Command {
+	return Command{Type: CmdBasicFree}
+}
+
+// NewBasicTraceCommand creates a command to turn BASIC line tracing on or
+// off. While it is on, the server reports each line number as RUN
+// executes it.
+func NewBasicTraceCommand(enabled bool) Command {
+	return Command{Type: CmdBasicTrace, Enabled: enabled}
+}
+
+// NewBasicStepCommand creates a command to run the current BASIC line and
+// stop before the next one. Parse the response with Response.AsBasicStep.
+func NewBasicStepCommand() Command {
+	return Command{Type: CmdBasicStep}
+}
+
+// NewBasicRefsCommand creates a command to list the BASIC lines that jump
+// to line with GOTO, GOSUB or THEN, for checking callers before moving or
+// deleting it. Parse the response with Response.AsLineRefs.
+func NewBasicRefsCommand(line int) Command {
+	return Command{Type: CmdBasicRefs, LineNumber: line}
+}
+
+// NewBasicFindCommand creates a command to list the BASIC lines whose text
+// contains text. Parse the response with Response.AsFindResults.
+func NewBasicFindCommand(text string) Command {
+	return Command{Type: CmdBasicFind, Text: text}
+}
+
+// NewBasicTokensCommand creates a command to read the tokenized program as
+// stored in memory, from the start of the variable name table to the end
+// of the program. Parse the response with Response.AsBytes.
+func NewBasicTokensCommand() Command {
+	return Command{Type: CmdBasicTokens}
+}
+
 // NewBasicExportCommand creates a command to export a BASIC program to a file.
 func NewBasicExportCommand(path string) Command {
 	return Command{Type: CmdBasicExport, Path: path}
@@ -479,6 +732,13 @@ func NewDosCopyCommand(source, destination string) Command {
 	return Command{Type: CmdDosCopy, Source: source, Destination: destination}
 }
 
+// NewDosDiffCommand creates a command to compare two files, which may be on
+// different drives ("D1:GAME.BAS" and "D2:GAME.BAS"), for example to check a
+// copy. Parse the response with Response.AsDiffResult.
+func NewDosDiffCommand(first, second string) Command {
+	return Command{Type: CmdDosDiff, Source: first, Destination: second}
+}
+
 // NewDosRenameCommand creates a command to rename a file.
 func NewDosRenameCommand(oldName, newName string) Command {
 	return Command{Type: CmdDosRename, OldName: oldName, NewName: newName}
@@ -524,6 +784,18 @@ func NewDosFormatCommand() Command {
 	return Command{Type: CmdDosFormat}
 }
 
+// NewDosCheckCommand creates a command to check the current disk's VTOC and
+// directory for consistency.
+func NewDosCheckCommand() Command {
+	return Command{Type: CmdDosCheck}
+}
+
+// NewDosProtectCommand creates a command to turn write protection on the
+// current drive on or off.
+func NewDosProtectCommand(enabled bool) Command {
+	return Command{Type: CmdDosProtect, Enabled: enabled}
+}
+
 // Format returns the command formatted for transmission over the protocol.
 // This does not include the CMD: prefix or trailing newline.
 func (c Command) Format() string {
@@ -532,6 +804,12 @@ func (c Command) Format() string {
 		return "ping"
 	case CmdVersion:
 		return "version"
+	case CmdCapabilities:
+		return "capabilities"
+	case CmdServerLog:
+		return "log"
+	case CmdServerLogClear:
+		return "log clear"
 	case CmdQuit:
 		return "quit"
 	case CmdShutdown:
@@ -545,6 +823,8 @@ func (c Command) Format() string {
 			return "step"
 		}
 		return fmt.Sprintf("step %d", c.Count)
+	case CmdFrameStep:
+		return fmt.Sprintf("frame %d", c.Count)
 	case CmdReset:
 		if c.Cold {
 			return "reset cold"
@@ -552,14 +832,22 @@ func (c Command) Format() string {
 		return "reset warm"
 	case CmdStatus:
 		return "status"
+	case CmdCycles:
+		return "cycles"
+	case CmdVideoSystem:
+		if c.VideoSystem == "" {
+			return "video"
+		}
+		return "video " + c.VideoSystem
+	case CmdAudio:
+		if c.Enabled {
+			return "audio on"
+		}
+		return "audio off"
 	case CmdRead:
 		return fmt.Sprintf("read $%04X %d", c.Address, c.Count)
 	case CmdWrite:
-		hexBytes := make([]string, len(c.Data))
-		for i, b := range c.Data {
-			hexBytes[i] = fmt.Sprintf("%02X", b)
-		}
-		return fmt.Sprintf("write $%04X %s", c.Address, strings.Join(hexBytes, ","))
+		return fmt.Sprintf("write $%04X %s", c.Address, formatHexBytes(c.Data))
 	case CmdRegisters:
 		if c.Modifications == nil || len(c.Modifications) == 0 {
 			return "registers"
@@ -569,13 +857,35 @@ func (c Command) Format() string {
 			mods[i] = fmt.Sprintf("%s=$%04X", m.Name, m.Value)
 		}
 		return "registers " + strings.Join(mods, " ")
+	case CmdMemoryMap:
+		return "memmap"
+	case CmdMemoryChecksum:
+		return fmt.Sprintf("checksum $%04X $%04X", c.Address, c.EndAddress)
 	case CmdBreakpointSet:
-		return fmt.Sprintf("breakpoint set $%04X", c.Address)
+		s := fmt.Sprintf("breakpoint set $%04X", c.Address)
+		if c.HitCount > 0 {
+			s += fmt.Sprintf(" hits %d", c.HitCount)
+		}
+		if c.Condition != "" {
+			s += " if " + c.Condition
+		}
+		if c.Once {
+			s += " once"
+		}
+		return s
 	case CmdBreakpointClear:
 		return fmt.Sprintf("breakpoint clear $%04X", c.Address)
 	case CmdBreakpointClearAll:
 		return "breakpoint clearall"
+	case CmdBreakpointEnable:
+		if c.Enabled {
+			return fmt.Sprintf("breakpoint enable $%04X", c.Address)
+		}
+		return fmt.Sprintf("breakpoint disable $%04X", c.Address)
 	case CmdBreakpointList:
+		if c.Detailed {
+			return "breakpoint list detailed"
+		}
 		return "breakpoint list"
 	case CmdAssemble:
 		return fmt.Sprintf("assemble $%04X", c.Address)
@@ -597,45 +907,63 @@ func (c Command) Format() string {
 			cmd += fmt.Sprintf(" %d", c.Lines)
 		}
 		return cmd
+	case CmdStepInstruction:
+		if c.Count <= 1 {
+			return "stepi"
+		}
+		return fmt.Sprintf("stepi %d", c.Count)
 	case CmdStepOver:
 		return "stepover"
+	case CmdStepOut:
+		return "stepout"
 	case CmdRunUntil:
+		if c.UntilReturn {
+			return "until ret"
+		}
 		return fmt.Sprintf("until $%04X", c.Address)
 	case CmdMemoryFill:
 		return fmt.Sprintf("fill $%04X $%04X $%02X", c.Address, c.EndAddress, c.Value)
+	case CmdMemoryPattern:
+		return fmt.Sprintf("pattern $%04X $%04X %s", c.Address, c.EndAddress, formatHexBytes(c.Data))
+	case CmdTrace:
+		if c.Enabled {
+			return "trace on " + escapePath(c.Path)
+		}
+		return "trace off"
 	case CmdMount:
-		return fmt.Sprintf("mount %d %s", c.Drive, c.Path)
+		return fmt.Sprintf("mount %d %s", c.Drive, escapePath(c.Path))
 	case CmdUnmount:
 		return fmt.Sprintf("unmount %d", c.Drive)
 	case CmdDrives:
 		return "drives"
 	case CmdBoot:
-		return fmt.Sprintf("boot %s", c.Path)
+		return fmt.Sprintf("boot %s", escapePath(c.Path))
+	case CmdBootInfo:
+		return "bootinfo"
 	case CmdStateSave:
-		return fmt.Sprintf("state save %s", c.Path)
+		return fmt.Sprintf("state save %s", escapePath(c.Path))
 	case CmdStateLoad:
-		return fmt.Sprintf("state load %s", c.Path)
+		return fmt.Sprintf("state load %s", escapePath(c.Path))
 	case CmdScreenshot:
 		if c.Path == "" {
 			return "screenshot"
 		}
-		return fmt.Sprintf("screenshot %s", c.Path)
+		return fmt.Sprintf("screenshot %s", escapePath(c.Path))
 	case CmdScreenText:
 		if c.Atascii {
 			return "screen atascii"
 		}
 		return "screen"
+	case CmdGraphicsMode:
+		return "gfx"
+	case CmdSetGraphicsMode:
+		return fmt.Sprintf("gfx %d", c.GraphicsMode)
 	case CmdInjectBasic:
 		return fmt.Sprintf("inject basic %s", c.Base64Data)
+	case CmdInjectKeyCodes:
+		return "inject codes " + formatHexBytes(c.Data)
 	case CmdInjectKeys:
-		// Escape special characters (including space to prevent parser issues)
-		escaped := c.Text
-		escaped = strings.ReplaceAll(escaped, "\\", "\\\\")
-		escaped = strings.ReplaceAll(escaped, "\n", "\\n")
-		escaped = strings.ReplaceAll(escaped, "\t", "\\t")
-		escaped = strings.ReplaceAll(escaped, "\r", "\\r")
-		escaped = strings.ReplaceAll(escaped, " ", "\\s")
-		return fmt.Sprintf("inject keys %s", escaped)
+		return fmt.Sprintf("inject keys %s", escapeText(c.Text))
 	case CmdBasicLine:
 		return fmt.Sprintf("basic %s", c.Line)
 	case CmdBasicNew:
@@ -657,12 +985,32 @@ func (c Command) Format() string {
 		return "basic VARS"
 	case CmdBasicVar:
 		return fmt.Sprintf("basic VAR %s", c.VarName)
+	case CmdBasicSet:
+		if strings.HasSuffix(c.VarName, "$") {
+			return fmt.Sprintf("basic SET %s \"%s\"", c.VarName, escapeText(c.VarValue))
+		}
+		return fmt.Sprintf("basic SET %s %s", c.VarName, c.VarValue)
 	case CmdBasicInfo:
 		return "basic INFO"
+	case CmdBasicFree:
+		return "basic FRE"
+	case CmdBasicStep:
+		return "basic STEP"
+	case CmdBasicRefs:
+		return fmt.Sprintf("basic REFS %d", c.LineNumber)
+	case CmdBasicFind:
+		return "basic FIND " + escapeText(c.Text)
+	case CmdBasicTokens:
+		return "basic TOKENS"
+	case CmdBasicTrace:
+		if c.Enabled {
+			return "basic TRACE on"
+		}
+		return "basic TRACE off"
 	case CmdBasicExport:
-		return fmt.Sprintf("basic EXPORT %s", c.Path)
+		return fmt.Sprintf("basic EXPORT %s", escapePath(c.Path))
 	case CmdBasicImport:
-		return fmt.Sprintf("basic IMPORT %s", c.Path)
+		return fmt.Sprintf("basic IMPORT %s", escapePath(c.Path))
 	case CmdBasicDir:
 		if c.AddressSet { // AddressSet is reused to indicate drive was set
 			return fmt.Sprintf("basic DIR %d", c.Drive)
@@ -704,6 +1052,8 @@ func (c Command) Format() string {
 		return fmt.Sprintf("dos dump %s", c.Filename)
 	case CmdDosCopy:
 		return fmt.Sprintf("dos copy %s %s", c.Source, c.Destination)
+	case CmdDosDiff:
+		return fmt.Sprintf("dos diff %s %s", c.Source, c.Destination)
 	case CmdDosRename:
 		return fmt.Sprintf("dos rename %s %s", c.OldName, c.NewName)
 	case CmdDosDelete:
@@ -713,16 +1063,23 @@ func (c Command) Format() string {
 	case CmdDosUnlock:
 		return fmt.Sprintf("dos unlock %s", c.Filename)
 	case CmdDosExport:
-		return fmt.Sprintf("dos export %s %s", c.Filename, c.HostPath)
+		return fmt.Sprintf("dos export %s %s", c.Filename, escapePath(c.HostPath))
 	case CmdDosImport:
-		return fmt.Sprintf("dos import %s %s", c.HostPath, c.Filename)
+		return fmt.Sprintf("dos import %s %s", escapePath(c.HostPath), c.Filename)
 	case CmdDosNewDisk:
 		if c.DiskType != "" {
-			return fmt.Sprintf("dos newdisk %s %s", c.Path, c.DiskType)
+			return fmt.Sprintf("dos newdisk %s %s", escapePath(c.Path), c.DiskType)
 		}
-		return fmt.Sprintf("dos newdisk %s", c.Path)
+		return fmt.Sprintf("dos newdisk %s", escapePath(c.Path))
 	case CmdDosFormat:
 		return "dos format"
+	case CmdDosCheck:
+		return "dos check"
+	case CmdDosProtect:
+		if c.Enabled {
+			return "dos protect on"
+		}
+		return "dos protect off"
 	default:
 		return ""
 	}
@@ -737,3 +1094,47 @@ func (c Command) FormatWithPrefix() string {
 func (c Command) FormatLine() string {
 	return c.FormatWithPrefix() + "\n"
 }
+
+// IsIdempotent reports whether the command only queries emulator state, so
+// sending it twice has the same effect as sending it once. Only idempotent
+// commands are retried by SendWithRetry.
+func (c Command) IsIdempotent() bool {
+	switch c.Type {
+	case CmdPing, CmdVersion, CmdCapabilities, CmdServerLog, CmdStatus, CmdCycles, CmdRead, CmdGraphicsMode,
+		CmdMemoryMap, CmdMemoryChecksum, CmdDisassemble, CmdDrives, CmdBootInfo, CmdBreakpointList:
+		return true
+	case CmdRegisters:
+		// Reading registers is safe; setting them is not.
+		return len(c.Modifications) == 0
+	case CmdVideoSystem:
+		return c.VideoSystem == ""
+	default:
+		return false
+	}
+}
+
+// escapeText escapes text for the protocol line, the reverse of
+// parseEscapes. Spaces are escaped too, so the text stays one argument.
+func escapeText(text string) string {
+	escaped := strings.ReplaceAll(text, "\\", "\\\\")
+	escaped = strings.ReplaceAll(escaped, "\n", "\\n")
+	escaped = strings.ReplaceAll(escaped, "\t", "\\t")
+	escaped = strings.ReplaceAll(escaped, "\r", "\\r")
+	return strings.ReplaceAll(escaped, " ", "\\s")
+}
+
+// escapePath prepares a host path for the wire. The server takes the path
+// literally, spaces and quotes included, except that it removes backslash
+// escapes, so only backslashes are escaped.
+func escapePath(path string) string {
+	return strings.ReplaceAll(path, `\`, `\\`)
+}
+
+// formatHexBytes formats bytes as a comma-separated hex list ("A9,00,60").
+func formatHexBytes(data []byte) string {
+	hexBytes := make([]string, len(data))
+	for i, b := range data {
+		hexBytes[i] = fmt.Sprintf("%02X", b)
+	}
+	return strings.Join(hexBytes, ",")
+}
@@ -16,7 +16,9 @@
 //	Async event:      EVENT:<event-type> <data>\n
 //
 // Multi-line responses use the Record Separator character (0x1E) to delimit
-// lines within a single response.
+// lines within a single response. Use Response.Lines or Response.Text
+// rather than splitting Data, so a separator set with Client.SetSeparator
+// or advertised by the server is honored.
 //
 // # Basic Usage
 //
@@ -24,7 +26,7 @@
 //
 //	client := atticprotocol.NewClient()
 //
-//	// Auto-discover and connect to a running server
+//	// Auto-discover and connect to a running server, retrying while it starts
 //	if err := client.DiscoverAndConnect(); err != nil {
 //	    log.Fatal(err)
 //	}
@@ -39,6 +41,20 @@
 //	    fmt.Println("Emulator paused")
 //	}
 //
+// # Error Responses
+//
+// ERR: responses are delivered as a Response with IsError() true. Use Err
+// to convert them into a typed *ProtocolError for errors.As handling:
+//
+//	resp, err := client.Send(atticprotocol.NewReadCommand(0x0600, 16))
+//	if err == nil {
+//	    err = resp.Err()
+//	}
+//	var perr *atticprotocol.ProtocolError
+//	if errors.As(err, &perr) {
+//	    fmt.Printf("Server rejected command: %s\n", perr.Message)
+//	}
+//
 // # Event Handling
 //
 // To receive async events like breakpoint notifications:
@@ -54,23 +70,29 @@
 //	    }
 //	})
 //
+// Without a handler, events are buffered and can be polled from a loop:
+//
+//	for event, ok := client.TryReceiveEvent(); ok; event, ok = client.TryReceiveEvent() {
+//	    fmt.Printf("Event at $%04X\n", event.Address)
+//	}
+//
 // # Command Types
 //
 // The package provides constructor functions for all supported commands:
 //
-//   - Connection: NewPingCommand, NewVersionCommand, NewQuitCommand, NewShutdownCommand
-//   - Emulator: NewPauseCommand, NewResumeCommand, NewStepCommand, NewResetCommand, NewStatusCommand
-//   - Memory: NewReadCommand, NewWriteCommand, NewRegistersCommand
-//   - Breakpoints: NewBreakpointSetCommand, NewBreakpointClearCommand, NewBreakpointClearAllCommand, NewBreakpointListCommand
+//   - Connection: NewPingCommand, NewVersionCommand, NewCapabilitiesCommand, NewServerLogCommand, NewServerLogClearCommand, NewQuitCommand, NewShutdownCommand
+//   - Emulator: NewPauseCommand, NewResumeCommand, NewStepCommand, NewFrameStepCommand, NewResetCommand, NewStatusCommand, NewAudioCommand, NewCyclesCommand, NewVideoSystemCommand
+//   - Memory: NewReadCommand, NewWriteCommand, NewRegistersCommand, NewMemoryMapCommand, NewMemoryChecksumCommand
+//   - Breakpoints: NewBreakpointSetCommand, NewBreakpointSetHitsCommand, NewBreakpointSetOnceCommand, NewBreakpointSetConditionCommand, NewBreakpointClearCommand, NewBreakpointEnableCommand, NewBreakpointClearAllCommand, NewBreakpointListCommand, NewBreakpointListDetailedCommand
 //   - Assembly: NewAssembleCommand, NewAssembleLineCommand, NewDisassembleCommand
-//   - Monitor: NewStepOverCommand, NewRunUntilCommand, NewMemoryFillCommand
+//   - Monitor: NewStepInstructionCommand, NewStepOverCommand, NewStepOutCommand, NewRunUntilCommand, NewRunUntilReturnCommand, NewMemoryFillCommand, NewMemoryPatternCommand, NewTraceCommand
 //   - Disk: NewMountCommand, NewUnmountCommand, NewDrivesCommand
-//   - Boot: NewBootCommand
+//   - Boot: NewBootCommand, NewBootInfoCommand
 //   - State: NewStateSaveCommand, NewStateLoadCommand
-//   - Display: NewScreenshotCommand
-//   - Injection: NewInjectBasicCommand, NewInjectKeysCommand
+//   - Display: NewScreenshotCommand, NewScreenTextCommand, NewGraphicsModeCommand, NewSetGraphicsModeCommand
+//   - Injection: NewInjectBasicCommand, NewInjectKeysCommand, NewInjectKeyCodesCommand
 //   - BASIC: NewBasicLineCommand, NewBasicNewCommand, NewBasicRunCommand, NewBasicListCommand
-//   - BASIC Editing: NewBasicDeleteCommand, NewBasicStopCommand, NewBasicContCommand, NewBasicVarsCommand, NewBasicVarCommand, NewBasicInfoCommand, NewBasicExportCommand, NewBasicImportCommand, NewBasicDirCommand
+//   - BASIC Editing: NewBasicDeleteCommand, NewBasicStopCommand, NewBasicContCommand, NewBasicVarsCommand, NewBasicVarCommand, NewBasicSetCommand, NewBasicInfoCommand, NewBasicFreeCommand, NewBasicTraceCommand, NewBasicStepCommand, NewBasicRefsCommand, NewBasicFindCommand, NewBasicTokensCommand, NewBasicExportCommand, NewBasicImportCommand, NewBasicDirCommand
 //
 // # Parsing Commands
 //
@@ -82,8 +104,31 @@
 //	    log.Fatal(err)
 //	}
 //
+// AllCommands lists every command keyword with its aliases and a one-line
+// summary, and LookupCommand resolves an alias to its canonical keyword.
+// The parser uses the same table, so the two never disagree.
+//
+// # ATASCII
+//
+// DecodeATASCII renders Atari text for a Unicode terminal, and
+// ATASCIIToUnicode maps a single character, including the graphics
+// characters $00-$1F. UnicodeToATASCII and EncodeATASCII go the other way,
+// so text from a Unicode terminal can be typed into the emulator.
+// FormatHexDump and FormatHexDumpATASCII lay out memory as a classic hex
+// dump with a text gutter.
+//
+// # Tracing
+//
+// Client.SetTraceWriter logs every command, response and event, which
+// helps when debugging a conversation with the server:
+//
+//	client.SetTraceWriter(os.Stderr)
+//
 // # Thread Safety
 //
 // The Client type is safe for concurrent use from multiple goroutines.
 // All public methods use proper synchronization.
+// Requests on one client are serialized over its single connection; use
+// Client.Clone for an independent session on a second connection, if the
+// server accepts several.
 package atticprotocol
@@ -3,6 +3,7 @@ package atticprotocol
 import (
 	"errors"
 	"fmt"
+	"regexp"
 )
 
 // Sentinel errors for the CLI protocol.
@@ -19,10 +20,41 @@ var (
 	// ErrNotConnected indicates an operation was attempted without a connection.
 	ErrNotConnected = errors.New("not connected")
 
-	// ErrAlreadyConnected indicates connect was called while already connected.
+	// ErrDisconnected indicates the connection was closed while a command
+	// was waiting for its response.
+	ErrDisconnected = errors.New("disconnected while waiting for response")
+
+	// ErrAlreadyConnected indicates connect was called for a different server
+	// while already connected.
 	ErrAlreadyConnected = errors.New("already connected")
+
+	// ErrQueuedThenFailed indicates a command waited in the reconnect queue
+	// (see Client.SetReconnectQueue) and was dropped because reconnecting
+	// failed. It was never sent.
+	ErrQueuedThenFailed = errors.New("queued while reconnecting, then dropped")
+
+	// ErrQueueFull indicates the reconnect queue was at its limit, so the
+	// command was not sent.
+	ErrQueueFull = errors.New("reconnect queue full")
 )
 
+// VersionMismatchError indicates the server speaks a protocol version outside
+// the range supported by this client. It is meant to be reported as a warning;
+// many commands may still work.
+type VersionMismatchError struct {
+	ServerVersion string // Version reported by the server ("" if unknown)
+	ClientVersion string // ProtocolVersion of this client
+}
+
+// Error implements the error interface.
+func (e *VersionMismatchError) Error() string {
+	server := e.ServerVersion
+	if server == "" {
+		server = "unknown"
+	}
+	return fmt.Sprintf("server protocol version %s is not compatible with client version %s", server, e.ClientVersion)
+}
+
 // ParseError represents an error that occurred during command or response parsing.
 type ParseError struct {
 	Kind    ParseErrorKind
@@ -58,6 +90,8 @@ const (
 	ErrKindMissingArgument
 	// ErrKindUnexpectedResponse indicates an unexpected response format.
 	ErrKindUnexpectedResponse
+	// ErrKindUnterminatedQuote indicates a quoted argument without a closing quote.
+	ErrKindUnterminatedQuote
 )
 
 // Error implements the error interface.
@@ -87,6 +121,8 @@ func (e *ParseError) Error() string {
 		return e.Message
 	case ErrKindUnexpectedResponse:
 		return fmt.Sprintf("unexpected response: %s", e.Value)
+	case ErrKindUnterminatedQuote:
+		return fmt.Sprintf("unterminated quote in '%s'", e.Value)
 	default:
 		return fmt.Sprintf("parse error: %s", e.Value)
 	}
@@ -142,6 +178,10 @@ func newUnexpectedResponseError(resp string) error {
 	return &ParseError{Kind: ErrKindUnexpectedResponse, Value: resp}
 }
 
+func newUnterminatedQuoteError(args string) error {
+	return &ParseError{Kind: ErrKindUnterminatedQuote, Value: args}
+}
+
 // ConnectionError represents a connection-related error.
 type ConnectionError struct {
 	Message string
@@ -165,3 +205,43 @@ func (e *ConnectionError) Unwrap() error {
 func NewConnectionError(message string, cause error) error {
 	return &ConnectionError{Message: message, Cause: cause}
 }
+
+// ProtocolError represents an ERR: response returned by the server.
+//
+// The server normally sends a plain message (e.g., "Invalid address 'ZZZZ'"),
+// but it may prefix the message with an upper-case error code followed by a
+// colon (e.g., "NOT_FOUND: File not found"). When present, the code is split
+// out into Code so callers can switch on it without string matching.
+//
+// Use errors.As to detect server-side failures:
+//
+//	var perr *atticprotocol.ProtocolError
+//	if errors.As(resp.Err(), &perr) {
+//	    fmt.Println(perr.Code, perr.Message)
+//	}
+type ProtocolError struct {
+	Code    string // Error code prefix, or empty if the server sent none
+	Message string // The error message (without the code prefix)
+}
+
+// protocolErrorCodeRegex matches an optional "CODE:" prefix on an error message.
+// Codes are at least three characters so drive specs like "D1:" are not mistaken
+// for codes.
+var protocolErrorCodeRegex = regexp.MustCompile(`^([A-Z][A-Z0-9_]{2,}):\s*(.*)$`)
+
+// Error implements the error interface.
+func (e *ProtocolError) Error() string {
+	if e.Code != "" {
+		return fmt.Sprintf("server error %s: %s", e.Code, e.Message)
+	}
+	return fmt.Sprintf("server error: %s", e.Message)
+}
+
+// NewProtocolError creates a protocol error from the data of an ERR: response,
+// splitting off an error code prefix if one is present.
+func NewProtocolError(data string) error {
+	if match := protocolErrorCodeRegex.FindStringSubmatch(data); match != nil {
+		return &ProtocolError{Code: match[1], Message: match[2]}
+	}
+	return &ProtocolError{Message: data}
+}
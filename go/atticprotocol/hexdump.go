@@ -0,0 +1,52 @@
+package atticprotocol
+
+import (
+	"fmt"
+	"strings"
+)
+
+// hexDumpWidth is the number of bytes per hex dump row.
+const hexDumpWidth = 16
+
+// FormatHexDump formats data read from address as a classic hex dump, 16
+// bytes per row with an ASCII gutter:
+//
+//	0600: A9 00 8D 00 D4 60 48 45 4C 4C 4F 00 00 00 00 00 |.....`HELLO.....|
+//
+// A short final row is padded so its gutter lines up with the rows above.
+// Bytes outside printable ASCII are shown as "." in the gutter.
+func FormatHexDump(address uint16, data []byte) string {
+	return formatHexDump(address, data, func(b byte) rune {
+		if b < 0x20 || b > 0x7E {
+			return '.'
+		}
+		return rune(b)
+	})
+}
+
+// FormatHexDumpATASCII is like FormatHexDump but renders the gutter as
+// ATASCII, using ATASCIIToUnicode for the graphics characters. Inverse
+// video characters are shown as their normal glyph.
+func FormatHexDumpATASCII(address uint16, data []byte) string {
+	return formatHexDump(address, data, func(b byte) rune {
+		return ATASCIIToUnicode(b, false)
+	})
+}
+
+func formatHexDump(address uint16, data []byte, glyph func(byte) rune) string {
+	var sb strings.Builder
+	for offset := 0; offset < len(data); offset += hexDumpWidth {
+		row := data[offset:min(offset+hexDumpWidth, len(data))]
+		fmt.Fprintf(&sb, "%04X:", uint16(int(address)+offset))
+		for _, b := range row {
+			fmt.Fprintf(&sb, " %02X", b)
+		}
+		sb.WriteString(strings.Repeat("   ", hexDumpWidth-len(row)))
+		sb.WriteString(" |")
+		for _, b := range row {
+			sb.WriteRune(glyph(b))
+		}
+		sb.WriteString("|\n")
+	}
+	return sb.String()
+}
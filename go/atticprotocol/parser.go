@@ -35,18 +35,26 @@ func (p *CommandParser) Parse(line string) (Command, error) {
 	}
 
 	command := strings.ToLower(parts[0])
+	info, ok := LookupCommand(command)
+	if !ok {
+		return Command{}, newInvalidCommandError(command)
+	}
 	argsString := ""
 	if len(parts) > 1 {
 		argsString = parts[1]
 	}
 
-	// Parse based on command word
-	switch command {
+	// Parse based on the canonical command word
+	switch info.Name {
 	// Connection commands
 	case "ping":
 		return NewPingCommand(), nil
 	case "version":
 		return NewVersionCommand(), nil
+	case "capabilities":
+		return NewCapabilitiesCommand(), nil
+	case "log":
+		return p.parseServerLog(argsString)
 	case "quit":
 		return NewQuitCommand(), nil
 	case "shutdown":
@@ -59,10 +67,18 @@ func (p *CommandParser) Parse(line string) (Command, error) {
 		return NewResumeCommand(), nil
 	case "step":
 		return p.parseStep(argsString)
+	case "frame":
+		return p.parseFrameStep(argsString)
 	case "reset":
 		return p.parseReset(argsString)
 	case "status":
 		return NewStatusCommand(), nil
+	case "cycles":
+		return NewCyclesCommand(), nil
+	case "video":
+		return p.parseVideoSystem(argsString)
+	case "audio":
+		return p.parseAudio(argsString)
 
 	// Memory operations
 	case "read":
@@ -71,26 +87,38 @@ func (p *CommandParser) Parse(line string) (Command, error) {
 		return p.parseWrite(argsString)
 	case "registers":
 		return p.parseRegisters(argsString)
+	case "memmap":
+		return NewMemoryMapCommand(), nil
+	case "checksum":
+		return p.parseChecksum(argsString)
 
 	// Breakpoints
 	case "breakpoint":
 		return p.parseBreakpoint(argsString)
 
 	// Disassembly
-	case "disasm", "disassemble", "d":
+	case "disassemble":
 		return p.parseDisassemble(argsString)
 
 	// Assembly
-	case "asm", "assemble", "a":
+	case "assemble":
 		return p.parseAssemble(argsString)
 
 	// Monitor commands
-	case "stepover", "so":
+	case "stepi":
+		return p.parseStepInstruction(argsString)
+	case "stepover":
 		return NewStepOverCommand(), nil
-	case "until", "rununtil":
+	case "stepout":
+		return NewStepOutCommand(), nil
+	case "until":
 		return p.parseRunUntil(argsString)
 	case "fill":
 		return p.parseFill(argsString)
+	case "pattern":
+		return p.parsePattern(argsString)
+	case "trace":
+		return p.parseTrace(argsString)
 
 	// Disk operations
 	case "mount":
@@ -103,6 +131,8 @@ func (p *CommandParser) Parse(line string) (Command, error) {
 	// Boot with file
 	case "boot":
 		return p.parseBoot(argsString)
+	case "bootinfo":
+		return NewBootInfoCommand(), nil
 
 	// State management
 	case "state":
@@ -110,11 +140,16 @@ func (p *CommandParser) Parse(line string) (Command, error) {
 
 	// Display
 	case "screenshot":
-		path := strings.TrimSpace(argsString)
+		path, err := parsePathArg(argsString)
+		if err != nil {
+			return Command{}, err
+		}
 		return NewScreenshotCommand(path), nil
 	case "screen":
 		atascii := strings.ToUpper(argsString) == "ATASCII"
 		return NewScreenTextCommand(atascii), nil
+	case "gfx":
+		return p.parseGraphicsMode(argsString)
 
 	// Injection
 	case "inject":
@@ -145,6 +180,30 @@ func (p *CommandParser) parseStep(args string) (Command, error) {
 	return NewStepCommand(count), nil
 }
 
+func (p *CommandParser) parseFrameStep(args string) (Command, error) {
+	args = strings.TrimSpace(args)
+	if args == "" {
+		return NewFrameStepCommand(1), nil
+	}
+	count, err := strconv.Atoi(args)
+	if err != nil || count <= 0 {
+		return Command{}, newInvalidStepCountError(args)
+	}
+	return NewFrameStepCommand(count), nil
+}
+
+func (p *CommandParser) parseStepInstruction(args string) (Command, error) {
+	args = strings.TrimSpace(args)
+	if args == "" {
+		return NewStepInstructionCommand(1), nil
+	}
+	count, err := strconv.Atoi(args)
+	if err != nil || count <= 0 {
+		return Command{}, newInvalidStepCountError(args)
+	}
+	return NewStepInstructionCommand(count), nil
+}
+
 func (p *CommandParser) parseReset(args string) (Command, error) {
 	switch strings.ToLower(strings.TrimSpace(args)) {
 	case "cold", "":
@@ -156,6 +215,51 @@ func (p *CommandParser) parseReset(args string) (Command, error) {
 	}
 }
 
+func (p *CommandParser) parseServerLog(args string) (Command, error) {
+	switch strings.ToLower(strings.TrimSpace(args)) {
+	case "":
+		return NewServerLogCommand(), nil
+	case "clear":
+		return NewServerLogClearCommand(), nil
+	default:
+		return Command{}, newInvalidCommandError("log " + strings.TrimSpace(args))
+	}
+}
+
+func (p *CommandParser) parseGraphicsMode(args string) (Command, error) {
+	args = strings.TrimSpace(args)
+	if args == "" {
+		return NewGraphicsModeCommand(), nil
+	}
+	mode, err := strconv.Atoi(args)
+	if err != nil || mode < 0 || mode > 15 {
+		return Command{}, newInvalidValueError(args)
+	}
+	return NewSetGraphicsModeCommand(mode), nil
+}
+
+func (p *CommandParser) parseAudio(args string) (Command, error) {
+	switch strings.ToLower(strings.TrimSpace(args)) {
+	case "on":
+		return NewAudioCommand(true), nil
+	case "off":
+		return NewAudioCommand(false), nil
+	case "":
+		return Command{}, newMissingArgumentError("audio requires on or off")
+	default:
+		return Command{}, newInvalidValueError(strings.TrimSpace(args))
+	}
+}
+
+func (p *CommandParser) parseVideoSystem(args string) (Command, error) {
+	switch system := strings.ToLower(strings.TrimSpace(args)); system {
+	case "", "ntsc", "pal":
+		return NewVideoSystemCommand(system), nil
+	default:
+		return Command{}, newInvalidValueError(strings.TrimSpace(args))
+	}
+}
+
 func (p *CommandParser) parseRead(args string) (Command, error) {
 	parts := strings.Fields(args)
 	if len(parts) != 2 {
@@ -186,16 +290,9 @@ func (p *CommandParser) parseWrite(args string) (Command, error) {
 		return Command{}, newInvalidAddressError(parts[0])
 	}
 
-	dataStr := strings.TrimSpace(parts[1])
-	var bytes []byte
-
-	for _, byteStr := range strings.Split(dataStr, ",") {
-		trimmed := strings.TrimSpace(byteStr)
-		b, ok := parseHexByte(trimmed)
-		if !ok {
-			return Command{}, newInvalidByteError(trimmed)
-		}
-		bytes = append(bytes, b)
+	bytes, err := parseHexByteList(parts[1])
+	if err != nil {
+		return Command{}, err
 	}
 
 	if len(bytes) == 0 {
@@ -239,7 +336,7 @@ func (p *CommandParser) parseRegisters(args string) (Command, error) {
 func (p *CommandParser) parseBreakpoint(args string) (Command, error) {
 	parts := strings.SplitN(strings.TrimSpace(args), " ", 2)
 	if len(parts) == 0 || parts[0] == "" {
-		return Command{}, newMissingArgumentError("breakpoint requires subcommand (set, clear, clearall, list)")
+		return Command{}, newMissingArgumentError("breakpoint requires subcommand (set, clear, enable, disable, clearall, list)")
 	}
 
 	subcommand := strings.ToLower(parts[0])
@@ -248,11 +345,55 @@ func (p *CommandParser) parseBreakpoint(args string) (Command, error) {
 		if len(parts) < 2 {
 			return Command{}, newMissingArgumentError("breakpoint set requires address")
 		}
-		address, ok := parseAddress(strings.TrimSpace(parts[1]))
+		setArgs := strings.Fields(parts[1])
+		address, ok := parseAddress(setArgs[0])
 		if !ok {
-			return Command{}, newInvalidAddressError(parts[1])
+			return Command{}, newInvalidAddressError(setArgs[0])
 		}
-		return NewBreakpointSetCommand(address), nil
+		cmd := NewBreakpointSetCommand(address)
+		for i := 1; i < len(setArgs); i++ {
+			switch strings.ToLower(setArgs[i]) {
+			case "once":
+				cmd.Once = true
+			case "if":
+				// The condition runs to the end of the line, except for
+				// trailing "hits N" and "once" attributes in any order.
+				condition := setArgs[i+1:]
+				for n := len(condition); n > 0; n = len(condition) {
+					if strings.EqualFold(condition[n-1], "once") {
+						cmd.Once = true
+						condition = condition[:n-1]
+					} else if n >= 2 && strings.EqualFold(condition[n-2], "hits") {
+						hits, err := strconv.Atoi(condition[n-1])
+						if err != nil || hits < 1 {
+							return Command{}, newInvalidCountError(condition[n-1])
+						}
+						cmd.HitCount = hits
+						condition = condition[:n-2]
+					} else {
+						break
+					}
+				}
+				if len(condition) == 0 {
+					return Command{}, newMissingArgumentError("breakpoint set if requires a condition")
+				}
+				cmd.Condition = strings.Join(condition, " ")
+				i = len(setArgs)
+			case "hits":
+				if i+1 >= len(setArgs) {
+					return Command{}, newMissingArgumentError("breakpoint set hits requires a count")
+				}
+				i++
+				hits, err := strconv.Atoi(setArgs[i])
+				if err != nil || hits < 1 {
+					return Command{}, newInvalidCountError(setArgs[i])
+				}
+				cmd.HitCount = hits
+			default:
+				return Command{}, newInvalidCommandError("breakpoint set ... " + setArgs[i])
+			}
+		}
+		return cmd, nil
 
 	case "clear":
 		if len(parts) < 2 {
@@ -264,11 +405,27 @@ func (p *CommandParser) parseBreakpoint(args string) (Command, error) {
 		}
 		return NewBreakpointClearCommand(address), nil
 
+	case "enable", "disable":
+		if len(parts) < 2 {
+			return Command{}, newMissingArgumentError("breakpoint " + subcommand + " requires address")
+		}
+		address, ok := parseAddress(strings.TrimSpace(parts[1]))
+		if !ok {
+			return Command{}, newInvalidAddressError(parts[1])
+		}
+		return NewBreakpointEnableCommand(address, subcommand == "enable"), nil
+
 	case "clearall":
 		return NewBreakpointClearAllCommand(), nil
 
 	case "list":
-		return NewBreakpointListCommand(), nil
+		if len(parts) < 2 {
+			return NewBreakpointListCommand(), nil
+		}
+		if strings.ToLower(strings.TrimSpace(parts[1])) != "detailed" {
+			return Command{}, newInvalidCommandError("breakpoint list " + parts[1])
+		}
+		return NewBreakpointListDetailedCommand(), nil
 
 	default:
 		return Command{}, newInvalidCommandError("breakpoint " + subcommand)
@@ -344,7 +501,10 @@ func (p *CommandParser) parseAssemble(args string) (Command, error) {
 func (p *CommandParser) parseRunUntil(args string) (Command, error) {
 	args = strings.TrimSpace(args)
 	if args == "" {
-		return Command{}, newMissingArgumentError("until requires address")
+		return Command{}, newMissingArgumentError("until requires address or 'ret'")
+	}
+	if strings.EqualFold(args, "ret") {
+		return NewRunUntilReturnCommand(), nil
 	}
 
 	address, ok := parseAddress(args)
@@ -379,6 +539,73 @@ func (p *CommandParser) parseFill(args string) (Command, error) {
 	return NewMemoryFillCommand(start, end, value), nil
 }
 
+func (p *CommandParser) parseTrace(args string) (Command, error) {
+	parts := strings.SplitN(strings.TrimSpace(args), " ", 2)
+	switch strings.ToLower(parts[0]) {
+	case "on":
+		if len(parts) < 2 || strings.TrimSpace(parts[1]) == "" {
+			return Command{}, newMissingArgumentError("trace on requires path")
+		}
+		path, err := parsePathArg(parts[1])
+		if err != nil {
+			return Command{}, err
+		}
+		return NewTraceCommand(true, path), nil
+	case "off":
+		if len(parts) > 1 {
+			return Command{}, newInvalidValueError(strings.TrimSpace(args))
+		}
+		return NewTraceCommand(false, ""), nil
+	case "":
+		return Command{}, newMissingArgumentError("trace requires on <path> or off")
+	default:
+		return Command{}, newInvalidValueError(parts[0])
+	}
+}
+
+func (p *CommandParser) parsePattern(args string) (Command, error) {
+	parts := strings.Fields(args)
+	if len(parts) < 3 {
+		return Command{}, newMissingArgumentError("pattern requires start, end, and bytes")
+	}
+
+	start, ok := parseAddress(parts[0])
+	if !ok {
+		return Command{}, newInvalidAddressError(parts[0])
+	}
+
+	end, ok := parseAddress(parts[1])
+	if !ok || end < start {
+		return Command{}, newInvalidAddressError(parts[1])
+	}
+
+	pattern, err := parseHexByteList(strings.Join(parts[2:], ""))
+	if err != nil {
+		return Command{}, err
+	}
+
+	return NewMemoryPatternCommand(start, end, pattern), nil
+}
+
+func (p *CommandParser) parseChecksum(args string) (Command, error) {
+	parts := strings.Fields(args)
+	if len(parts) < 2 {
+		return Command{}, newMissingArgumentError("checksum requires start and end")
+	}
+
+	start, ok := parseAddress(parts[0])
+	if !ok {
+		return Command{}, newInvalidAddressError(parts[0])
+	}
+
+	end, ok := parseAddress(parts[1])
+	if !ok || end < start {
+		return Command{}, newInvalidAddressError(parts[1])
+	}
+
+	return NewMemoryChecksumCommand(start, end), nil
+}
+
 func (p *CommandParser) parseMount(args string) (Command, error) {
 	parts := strings.SplitN(strings.TrimSpace(args), " ", 2)
 	if len(parts) != 2 {
@@ -390,7 +617,11 @@ func (p *CommandParser) parseMount(args string) (Command, error) {
 		return Command{}, newInvalidDriveNumberError(parts[0])
 	}
 
-	return NewMountCommand(drive, parts[1]), nil
+	path, err := parsePathArg(parts[1])
+	if err != nil {
+		return Command{}, err
+	}
+	return NewMountCommand(drive, path), nil
 }
 
 func (p *CommandParser) parseUnmount(args string) (Command, error) {
@@ -403,10 +634,13 @@ func (p *CommandParser) parseUnmount(args string) (Command, error) {
 }
 
 func (p *CommandParser) parseBoot(args string) (Command, error) {
-	path := strings.TrimSpace(args)
-	if path == "" {
+	if strings.TrimSpace(args) == "" {
 		return Command{}, newMissingArgumentError("boot requires a file path")
 	}
+	path, err := parsePathArg(args)
+	if err != nil {
+		return Command{}, err
+	}
 	// Note: Tilde expansion should be done by the caller if needed
 	return NewBootCommand(path), nil
 }
@@ -421,7 +655,10 @@ func (p *CommandParser) parseState(args string) (Command, error) {
 		return Command{}, newMissingArgumentError("state " + parts[0] + " requires path")
 	}
 
-	path := strings.TrimSpace(parts[1])
+	path, err := parsePathArg(parts[1])
+	if err != nil {
+		return Command{}, err
+	}
 	switch strings.ToLower(parts[0]) {
 	case "save":
 		return NewStateSaveCommand(path), nil
@@ -435,7 +672,7 @@ func (p *CommandParser) parseState(args string) (Command, error) {
 func (p *CommandParser) parseInject(args string) (Command, error) {
 	parts := strings.SplitN(strings.TrimSpace(args), " ", 2)
 	if len(parts) == 0 || parts[0] == "" {
-		return Command{}, newMissingArgumentError("inject requires subcommand (basic or keys)")
+		return Command{}, newMissingArgumentError("inject requires subcommand (basic, keys or codes)")
 	}
 
 	if len(parts) < 2 {
@@ -448,6 +685,12 @@ func (p *CommandParser) parseInject(args string) (Command, error) {
 		return NewInjectBasicCommand(data), nil
 	case "keys":
 		return NewInjectKeysCommand(parseEscapes(data)), nil
+	case "codes":
+		codes, err := parseHexByteList(data)
+		if err != nil {
+			return Command{}, err
+		}
+		return NewInjectKeyCodesCommand(codes), nil
 	default:
 		return Command{}, newInvalidCommandError("inject " + parts[0])
 	}
@@ -498,20 +741,67 @@ func (p *CommandParser) parseBasic(args string) (Command, error) {
 			return Command{}, newMissingArgumentError("basic var requires a variable name")
 		}
 		return NewBasicVarCommand(rest), nil
+	case "SET":
+		return p.parseBasicSet(rest)
 	case "INFO":
 		return NewBasicInfoCommand(), nil
+	case "FRE":
+		return NewBasicFreeCommand(), nil
+	case "STEP":
+		return NewBasicStepCommand(), nil
+	case "TOKENS":
+		return NewBasicTokensCommand(), nil
+	case "FIND":
+		// Take the text as typed, after the single separating space, so
+		// escaped leading and trailing spaces survive.
+		text := ""
+		if len(parts) > 1 {
+			text = parseEscapes(parts[1])
+		}
+		if strings.TrimSpace(text) == "" {
+			return Command{}, newMissingArgumentError("basic find requires text to search for")
+		}
+		return NewBasicFindCommand(text), nil
+	case "REFS":
+		if rest == "" {
+			return Command{}, newMissingArgumentError("basic refs requires a line number")
+		}
+		line, err := strconv.Atoi(rest)
+		if err != nil || line < 0 || line > 32767 {
+			return Command{}, newInvalidValueError(rest)
+		}
+		return NewBasicRefsCommand(line), nil
+	case "TRACE":
+		switch strings.ToLower(rest) {
+		case "on":
+			return NewBasicTraceCommand(true), nil
+		case "off":
+			return NewBasicTraceCommand(false), nil
+		case "":
+			return Command{}, newMissingArgumentError("basic trace requires on or off")
+		default:
+			return Command{}, newInvalidValueError(rest)
+		}
 	case "EXPORT":
 		if rest == "" {
 			return Command{}, newMissingArgumentError("basic export requires a file path")
 		}
+		path, err := parsePathArg(rest)
+		if err != nil {
+			return Command{}, err
+		}
 		// Note: Tilde expansion should be done by the caller if needed
-		return NewBasicExportCommand(rest), nil
+		return NewBasicExportCommand(path), nil
 	case "IMPORT":
 		if rest == "" {
 			return Command{}, newMissingArgumentError("basic import requires a file path")
 		}
+		path, err := parsePathArg(rest)
+		if err != nil {
+			return Command{}, err
+		}
 		// Note: Tilde expansion should be done by the caller if needed
-		return NewBasicImportCommand(rest), nil
+		return NewBasicImportCommand(path), nil
 	case "DIR":
 		if rest == "" {
 			return NewBasicDirCommand(nil), nil
@@ -527,6 +817,39 @@ func (p *CommandParser) parseBasic(args string) (Command, error) {
 	}
 }
 
+// basicVarNameRegex matches a BASIC variable name: a letter, then letters
+// and digits, with a trailing "$" for string variables.
+var basicVarNameRegex = regexp.MustCompile(`^[A-Za-z][A-Za-z0-9]*\$?$`)
+
+// parseBasicSet parses BASIC SET arguments.
+// Format: SET name value, where a string variable's value is quoted
+// ("HI THERE" or "HI\sTHERE") and any other value is a number.
+func (p *CommandParser) parseBasicSet(args string) (Command, error) {
+	parts := strings.SplitN(strings.TrimSpace(args), " ", 2)
+	name := parts[0]
+	if name == "" {
+		return Command{}, newMissingArgumentError("basic set requires a variable name and value")
+	}
+	if !basicVarNameRegex.MatchString(name) {
+		return Command{}, newInvalidValueError(name)
+	}
+	if len(parts) < 2 || strings.TrimSpace(parts[1]) == "" {
+		return Command{}, newMissingArgumentError("basic set " + name + " requires a value")
+	}
+	value := strings.TrimSpace(parts[1])
+
+	if strings.HasSuffix(name, "$") {
+		if len(value) < 2 || !strings.HasPrefix(value, "\"") || !strings.HasSuffix(value, "\"") {
+			return Command{}, newInvalidValueError(value)
+		}
+		return NewBasicSetCommand(name, parseEscapes(value[1:len(value)-1])), nil
+	}
+	if _, err := strconv.ParseFloat(value, 64); err != nil {
+		return Command{}, newInvalidValueError(value)
+	}
+	return NewBasicSetCommand(name, value), nil
+}
+
 // parseBasicRenum parses BASIC RENUM arguments.
 // Format: RENUM [start [step]]
 func (p *CommandParser) parseBasicRenum(args string) (Command, error) {
@@ -663,6 +986,13 @@ func (p *CommandParser) parseDOS(args string) (Command, error) {
 		}
 		return NewDosCopyCommand(copyParts[0], copyParts[1]), nil
 
+	case "diff":
+		diffParts := strings.Fields(rest)
+		if len(diffParts) != 2 {
+			return Command{}, newMissingArgumentError("dos diff requires two filenames")
+		}
+		return NewDosDiffCommand(diffParts[0], diffParts[1]), nil
+
 	case "rename":
 		renameParts := strings.Fields(rest)
 		if len(renameParts) != 2 {
@@ -689,21 +1019,30 @@ func (p *CommandParser) parseDOS(args string) (Command, error) {
 		return NewDosUnlockCommand(rest), nil
 
 	case "export":
-		exportParts := strings.Fields(rest)
+		exportParts, err := splitArgs(rest)
+		if err != nil {
+			return Command{}, err
+		}
 		if len(exportParts) != 2 {
 			return Command{}, newMissingArgumentError("dos export requires filename and host path")
 		}
 		return NewDosExportCommand(exportParts[0], exportParts[1]), nil
 
 	case "import":
-		importParts := strings.Fields(rest)
+		importParts, err := splitArgs(rest)
+		if err != nil {
+			return Command{}, err
+		}
 		if len(importParts) != 2 {
 			return Command{}, newMissingArgumentError("dos import requires host path and filename")
 		}
 		return NewDosImportCommand(importParts[0], importParts[1]), nil
 
 	case "newdisk":
-		newdiskParts := strings.Fields(rest)
+		newdiskParts, err := splitArgs(rest)
+		if err != nil {
+			return Command{}, err
+		}
 		if len(newdiskParts) == 0 {
 			return Command{}, newMissingArgumentError("dos newdisk requires a path")
 		}
@@ -721,6 +1060,21 @@ func (p *CommandParser) parseDOS(args string) (Command, error) {
 	case "format":
 		return NewDosFormatCommand(), nil
 
+	case "check":
+		return NewDosCheckCommand(), nil
+
+	case "protect":
+		switch strings.ToLower(rest) {
+		case "on":
+			return NewDosProtectCommand(true), nil
+		case "off":
+			return NewDosProtectCommand(false), nil
+		case "":
+			return Command{}, newMissingArgumentError("dos protect requires on or off")
+		default:
+			return Command{}, newInvalidValueError(rest)
+		}
+
 	default:
 		return Command{}, newInvalidCommandError("dos " + subcommand)
 	}
@@ -752,6 +1106,20 @@ func parseAddress(s string) (uint16, bool) {
 	}
 }
 
+// parseHexByteList parses a comma-separated list of hex bytes ("A9,00,$60").
+func parseHexByteList(s string) ([]byte, error) {
+	var bytes []byte
+	for _, byteStr := range strings.Split(strings.TrimSpace(s), ",") {
+		trimmed := strings.TrimSpace(byteStr)
+		b, ok := parseHexByte(trimmed)
+		if !ok {
+			return nil, newInvalidByteError(trimmed)
+		}
+		bytes = append(bytes, b)
+	}
+	return bytes, nil
+}
+
 // parseHexByte parses a hex byte value (with or without $ prefix).
 func parseHexByte(s string) (byte, bool) {
 	s = strings.TrimSpace(s)
@@ -765,6 +1133,87 @@ func parseHexByte(s string) (byte, bool) {
 	return byte(val), true
 }
 
+// splitArgs splits an argument string on whitespace, honoring quotes so
+// that paths containing spaces can be passed as a single argument:
+//
+//	"/My Disks/game.atr"   double quotes; \" and \\ are escapes inside
+//	'/My Disks/game.atr'   single quotes; everything inside is literal
+//	/My\ Disks/game.atr    backslash escapes a space or quote outside quotes
+//
+// Unquoted input without backslashes splits exactly like strings.Fields.
+// An unterminated quote is reported as a *ParseError.
+func splitArgs(s string) ([]string, error) {
+	var args []string
+	var current strings.Builder
+	inArg := false
+	var quote rune // 0 when not inside quotes
+
+	runes := []rune(s)
+	for i := 0; i < len(runes); i++ {
+		r := runes[i]
+		switch {
+		case quote == '\'':
+			if r == '\'' {
+				quote = 0
+			} else {
+				current.WriteRune(r)
+			}
+		case quote == '"':
+			if r == '"' {
+				quote = 0
+			} else if r == '\\' && i+1 < len(runes) && (runes[i+1] == '"' || runes[i+1] == '\\') {
+				i++
+				current.WriteRune(runes[i])
+			} else {
+				current.WriteRune(r)
+			}
+		case r == '"' || r == '\'':
+			quote = r
+			inArg = true
+		case r == '\\' && i+1 < len(runes) && strings.ContainsRune(" \t\"'\\", runes[i+1]):
+			i++
+			current.WriteRune(runes[i])
+			inArg = true
+		case r == ' ' || r == '\t':
+			if inArg {
+				args = append(args, current.String())
+				current.Reset()
+				inArg = false
+			}
+		default:
+			current.WriteRune(r)
+			inArg = true
+		}
+	}
+
+	if quote != 0 {
+		return nil, newUnterminatedQuoteError(s)
+	}
+	if inArg {
+		args = append(args, current.String())
+	}
+	return args, nil
+}
+
+// parsePathArg parses a single file path argument. Quoted paths and paths
+// with escaped spaces are unquoted with splitArgs; anything else is used
+// as-is (trimmed), so existing unquoted paths keep working unchanged.
+func parsePathArg(s string) (string, error) {
+	trimmed := strings.TrimSpace(s)
+	quoted := strings.HasPrefix(trimmed, "\"") || strings.HasPrefix(trimmed, "'")
+	if !quoted && !strings.Contains(trimmed, "\\ ") {
+		return trimmed, nil
+	}
+	args, err := splitArgs(trimmed)
+	if err != nil {
+		return "", err
+	}
+	if len(args) != 1 {
+		return "", newInvalidValueError(trimmed)
+	}
+	return args[0], nil
+}
+
 // parseEscapes processes escape sequences in a string.
 func parseEscapes(s string) string {
 	var result strings.Builder
@@ -797,13 +1246,15 @@ func parseEscapes(s string) string {
 
 // ResponseParser parses responses and events from the CLI protocol.
 type ResponseParser struct {
-	addressRegex *regexp.Regexp
+	addressRegex  *regexp.Regexp
+	registerRegex *regexp.Regexp
 }
 
 // NewResponseParser creates a new response parser.
 func NewResponseParser() *ResponseParser {
 	return &ResponseParser{
-		addressRegex: regexp.MustCompile(`\$([0-9A-Fa-f]{4})`),
+		addressRegex:  regexp.MustCompile(`^\$([0-9A-Fa-f]{4})$`),
+		registerRegex: regexp.MustCompile(`\b([AXYSP])=\$([0-9A-Fa-f]{2})\b`),
 	}
 }
 
@@ -845,40 +1296,41 @@ func (p *ResponseParser) Parse(line string) (ParsedMessage, error) {
 	return ParsedMessage{}, newUnexpectedResponseError(trimmed)
 }
 
+// parseEvent parses the text after "EVENT:". Malformed events, such as a
+// breakpoint without an address, are rejected rather than reported with a
+// zero address.
 func (p *ResponseParser) parseEvent(data string) (Event, error) {
-	parts := strings.SplitN(data, " ", 2)
-	if len(parts) == 0 {
+	fields := strings.Fields(data)
+	if len(fields) == 0 {
 		return Event{}, newUnexpectedResponseError("empty event")
 	}
 
-	eventType := strings.ToLower(parts[0])
+	eventType := strings.ToLower(fields[0])
 	switch eventType {
 	case "breakpoint":
 		// Format: breakpoint $XXXX A=$XX X=$XX Y=$XX S=$XX P=$XX
-		match := p.addressRegex.FindStringSubmatch(data)
-		var address uint16
-		if len(match) >= 2 {
-			if val, err := strconv.ParseUint(match[1], 16, 16); err == nil {
-				address = uint16(val)
-			}
+		address, err := p.parseEventAddress(data, fields)
+		if err != nil {
+			return Event{}, err
+		}
+		regs := make(map[string]uint8)
+		for _, match := range p.registerRegex.FindAllStringSubmatch(data, -1) {
+			val, _ := strconv.ParseUint(match[2], 16, 8) // regex guarantees two hex digits
+			regs[match[1]] = uint8(val)
 		}
-		// TODO: Parse register values from the event data
-		return NewBreakpointEvent(address, 0, 0, 0, 0, 0), nil
+		return NewBreakpointEvent(address, regs["A"], regs["X"], regs["Y"], regs["S"], regs["P"]), nil
 
 	case "stopped":
-		match := p.addressRegex.FindStringSubmatch(data)
-		var address uint16
-		if len(match) >= 2 {
-			if val, err := strconv.ParseUint(match[1], 16, 16); err == nil {
-				address = uint16(val)
-			}
+		address, err := p.parseEventAddress(data, fields)
+		if err != nil {
+			return Event{}, err
 		}
 		return NewStoppedEvent(address), nil
 
 	case "error":
-		message := "Unknown error"
-		if len(parts) > 1 {
-			message = parts[1]
+		message := strings.TrimSpace(strings.TrimPrefix(strings.TrimSpace(data), fields[0]))
+		if message == "" {
+			message = "Unknown error"
 		}
 		return NewErrorEvent(message), nil
 
@@ -886,3 +1338,17 @@ func (p *ResponseParser) parseEvent(data string) (Event, error) {
 		return Event{}, newUnexpectedResponseError("unknown event type '" + eventType + "'")
 	}
 }
+
+// parseEventAddress returns the "$XXXX" address that must follow the event
+// type.
+func (p *ResponseParser) parseEventAddress(data string, fields []string) (uint16, error) {
+	if len(fields) < 2 {
+		return 0, newUnexpectedResponseError(data)
+	}
+	match := p.addressRegex.FindStringSubmatch(fields[1])
+	if match == nil {
+		return 0, newUnexpectedResponseError(data)
+	}
+	val, _ := strconv.ParseUint(match[1], 16, 16) // regex guarantees four hex digits
+	return uint16(val), nil
+}
@@ -49,34 +49,89 @@ const (
 	EventPrefix = "EVENT:"
 
 	// MultiLineSeparator is the character used to separate multiple lines
-	// in a single response (Record Separator character, ASCII 0x1E).
+	// in a single response (Record Separator character, ASCII 0x1E). A
+	// server may advertise another one; see Client.NegotiateSeparator.
 	MultiLineSeparator = "\x1E"
 
-	// SocketPathPrefix is the prefix for server socket paths.
+	// SocketPathPrefix is the prefix for server socket paths in
+	// DefaultSocketDir.
 	SocketPathPrefix = "/tmp/attic-"
 
+	// DefaultSocketDir is where AtticServer creates its socket unless it
+	// is started with --socket-path.
+	DefaultSocketDir = "/tmp"
+
+	// SocketDirEnv is the environment variable that makes discovery search
+	// another directory than DefaultSocketDir, for servers started with a
+	// --socket-path there. AtticServer itself does not read it.
+	SocketDirEnv = "ATTIC_SOCKET_DIR"
+
 	// SocketPathSuffix is the suffix for server socket paths.
 	SocketPathSuffix = ".sock"
 
 	// MaxLineLength is the maximum allowed length for a protocol line in bytes.
 	MaxLineLength = 4096
 
+	// DefaultReadBufferSize is the default size of the client's socket read
+	// buffer. Lines longer than the buffer are still read in full.
+	DefaultReadBufferSize = 64 * 1024
+
 	// CommandTimeout is the default timeout for commands.
 	CommandTimeout = 30 * time.Second
 
+	// LongCommandTimeout is the default timeout for commands that write
+	// files or disk images (screenshot, state save, format, ...).
+	LongCommandTimeout = 2 * time.Minute
+
 	// PingTimeout is the timeout used for ping commands during connection verification.
 	PingTimeout = 1 * time.Second
 
+	// EventBufferSize is how many async events the client keeps for
+	// TryReceiveEvent when no event handler is set. Older events are
+	// dropped once the buffer is full.
+	EventBufferSize = 64
+
 	// ConnectionTimeout is the timeout for establishing connections.
 	ConnectionTimeout = 5 * time.Second
 
+	// DisconnectGracePeriod is how long Disconnect waits for an in-flight
+	// command to receive its response before failing it.
+	DisconnectGracePeriod = 500 * time.Millisecond
+
+	// DefaultDiscoverAttempts is the default number of DiscoverAndConnect attempts.
+	DefaultDiscoverAttempts = 5
+
+	// DefaultDiscoverRetryInterval is the default delay before the first
+	// DiscoverAndConnect retry. The delay doubles after each failed attempt.
+	DefaultDiscoverRetryInterval = 100 * time.Millisecond
+
+	// MaxDiscoverRetryInterval caps the delay between DiscoverAndConnect retries.
+	MaxDiscoverRetryInterval = 2 * time.Second
+
 	// ProtocolVersion is the version string for the CLI protocol.
 	ProtocolVersion = "1.0"
+
+	// MinServerProtocolVersion is the oldest server protocol version this
+	// client works with. Servers with the same major version as
+	// ProtocolVersion and at least this version are compatible.
+	MinServerProtocolVersion = "1.0"
 )
 
-// SocketPath returns the socket path for a given process ID.
+// socketNamePrefix starts the file name of every server socket.
+const socketNamePrefix = "attic-"
+
+// SocketDir returns the directory discovery searches for server sockets:
+// the value of SocketDirEnv if set, otherwise DefaultSocketDir.
+func SocketDir() string {
+	if dir := os.Getenv(SocketDirEnv); dir != "" {
+		return dir
+	}
+	return DefaultSocketDir
+}
+
+// SocketPath returns the socket path for a given process ID in SocketDir.
 func SocketPath(pid int) string {
-	return fmt.Sprintf("%s%d%s", SocketPathPrefix, pid, SocketPathSuffix)
+	return filepath.Join(SocketDir(), fmt.Sprintf("%s%d%s", socketNamePrefix, pid, SocketPathSuffix))
 }
 
 // CurrentSocketPath returns the socket path for the current process.
@@ -84,13 +139,18 @@ func CurrentSocketPath() string {
 	return SocketPath(os.Getpid())
 }
 
-// DiscoverSockets finds all AtticServer sockets in /tmp.
+// DiscoverSockets finds all AtticServer sockets in SocketDir.
 // Only returns sockets whose server process is still running (validates PID).
 // Returns socket paths sorted by modification time (most recent first).
 // Stale sockets (where the server process is no longer running) are automatically
 // cleaned up.
 func DiscoverSockets() ([]string, error) {
-	pattern := filepath.Join("/tmp", "attic-*.sock")
+	return discoverSocketsIn(SocketDir())
+}
+
+// discoverSocketsIn is DiscoverSockets for the sockets in dir.
+func discoverSocketsIn(dir string) ([]string, error) {
+	pattern := filepath.Join(dir, socketNamePrefix+"*"+SocketPathSuffix)
 	matches, err := filepath.Glob(pattern)
 	if err != nil {
 		return nil, fmt.Errorf("failed to glob sockets: %w", err)
@@ -139,8 +199,8 @@ func DiscoverSockets() ([]string, error) {
 // that process is still alive.
 func isServerProcessRunning(socketFilename string) bool {
 	// Extract PID from filename: attic-<PID>.sock
-	name := strings.TrimPrefix(socketFilename, "attic-")
-	name = strings.TrimSuffix(name, ".sock")
+	name := strings.TrimPrefix(socketFilename, socketNamePrefix)
+	name = strings.TrimSuffix(name, SocketPathSuffix)
 
 	pid, err := strconv.Atoi(name)
 	if err != nil {
@@ -169,3 +229,26 @@ func DiscoverSocket() string {
 	}
 	return sockets[0]
 }
+
+// DiscoverSocketErr is DiscoverSocket with diagnostics. It returns
+// ErrSocketNotFound if no server is running, and a descriptive error if
+// the socket directory can't be read, which DiscoverSocket (like a glob)
+// would silently treat as no match.
+func DiscoverSocketErr() (string, error) {
+	return discoverSocketErrIn(SocketDir())
+}
+
+// discoverSocketErrIn is DiscoverSocketErr for the sockets in dir.
+func discoverSocketErrIn(dir string) (string, error) {
+	if _, err := os.ReadDir(dir); err != nil {
+		return "", fmt.Errorf("cannot read socket directory %s: %w", dir, err)
+	}
+	sockets, err := discoverSocketsIn(dir)
+	if err != nil {
+		return "", err
+	}
+	if len(sockets) == 0 {
+		return "", ErrSocketNotFound
+	}
+	return sockets[0], nil
+}
@@ -1,7 +1,21 @@
 package atticprotocol
 
 import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"net"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"sync/atomic"
 	"testing"
+	"time"
 )
 
 // TestProtocolConstants verifies constants match the Swift implementation.
@@ -31,6 +45,7 @@ func TestProtocolConstants(t *testing.T) {
 }
 
 func TestSocketPath(t *testing.T) {
+	t.Setenv(SocketDirEnv, "")
 	path := SocketPath(12345)
 	expected := "/tmp/attic-12345.sock"
 	if path != expected {
@@ -38,6 +53,62 @@ func TestSocketPath(t *testing.T) {
 	}
 }
 
+// TestSocketDirEnv verifies that ATTIC_SOCKET_DIR moves socket paths and
+// discovery to another directory.
+func TestSocketDirEnv(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv(SocketDirEnv, dir)
+
+	if got := SocketDir(); got != dir {
+		t.Errorf("SocketDir() = %q, want %q", got, dir)
+	}
+	path := SocketPath(12345)
+	if want := filepath.Join(dir, "attic-12345.sock"); path != want {
+		t.Errorf("SocketPath() = %q, want %q", path, want)
+	}
+
+	// Discovery only accepts sockets named for a running process.
+	live := CurrentSocketPath()
+	if err := os.WriteFile(live, nil, 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if got := DiscoverSocket(); got != live {
+		t.Errorf("DiscoverSocket() = %q, want %q", got, live)
+	}
+	if got, err := DiscoverSocketErr(); err != nil || got != live {
+		t.Errorf("DiscoverSocketErr() = %q, %v; want %q", got, err, live)
+	}
+}
+
+// TestClientSetSocketDir verifies that a client discovers a server in the
+// directory it was given.
+func TestClientSetSocketDir(t *testing.T) {
+	// Unix socket paths are short, so avoid t.TempDir's long names.
+	dir, err := os.MkdirTemp("", "attic-dir-")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { os.RemoveAll(dir) })
+
+	path := filepath.Join(dir, fmt.Sprintf("attic-%d.sock", os.Getpid()))
+	ln, err := net.Listen("unix", path)
+	if err != nil {
+		t.Fatalf("failed to listen: %v", err)
+	}
+	defer ln.Close()
+	go serveFake(ln, nil)
+
+	client := NewClient()
+	client.SetSocketDir(dir)
+	if err := client.DiscoverAndConnectOnce(); err != nil {
+		t.Fatalf("DiscoverAndConnectOnce() error = %v", err)
+	}
+	defer client.Disconnect()
+	if client.ConnectedPath() != path {
+		t.Errorf("ConnectedPath() = %q, want %q", client.ConnectedPath(), path)
+	}
+}
+
 // TestCommandFormatting verifies command formatting matches the protocol.
 func TestCommandFormatting(t *testing.T) {
 	tests := []struct {
@@ -47,26 +118,59 @@ func TestCommandFormatting(t *testing.T) {
 	}{
 		{"Ping", NewPingCommand(), "ping"},
 		{"Version", NewVersionCommand(), "version"},
+		{"Capabilities", NewCapabilitiesCommand(), "capabilities"},
 		{"Quit", NewQuitCommand(), "quit"},
 		{"Shutdown", NewShutdownCommand(), "shutdown"},
 		{"Pause", NewPauseCommand(), "pause"},
 		{"Resume", NewResumeCommand(), "resume"},
 		{"Step 1", NewStepCommand(1), "step"},
 		{"Step 10", NewStepCommand(10), "step 10"},
+		{"Frame Step", NewFrameStepCommand(0), "frame 1"},
+		{"Frame Step 4", NewFrameStepCommand(4), "frame 4"},
+		{"Step Instruction 1", NewStepInstructionCommand(1), "stepi"},
+		{"Step Instruction 5", NewStepInstructionCommand(5), "stepi 5"},
 		{"Reset Cold", NewResetCommand(true), "reset cold"},
 		{"Reset Warm", NewResetCommand(false), "reset warm"},
+		{"Audio On", NewAudioCommand(true), "audio on"},
+		{"Audio Off", NewAudioCommand(false), "audio off"},
+		{"Video read", NewVideoSystemCommand(""), "video"},
+		{"Video NTSC", NewVideoSystemCommand("ntsc"), "video ntsc"},
+		{"Video PAL", NewVideoSystemCommand("PAL"), "video pal"},
 		{"Status", NewStatusCommand(), "status"},
+		{"Cycles", NewCyclesCommand(), "cycles"},
 		{"Read", NewReadCommand(0x0600, 16), "read $0600 16"},
 		{"Write", NewWriteCommand(0x0600, []byte{0xA9, 0x00}), "write $0600 A9,00"},
 		{"Registers (read)", NewRegistersCommand(nil), "registers"},
+		{"Memory map", NewMemoryMapCommand(), "memmap"},
+		{"Checksum", NewMemoryChecksumCommand(0x0600, 0x7FFF), "checksum $0600 $7FFF"},
+		{"Pattern", NewMemoryPatternCommand(0x0600, 0x06FF, []byte{0xDE, 0xAD, 0xBE, 0xEF}), "pattern $0600 $06FF DE,AD,BE,EF"},
+		{"Trace on", NewTraceCommand(true, "/tmp/trace.log"), "trace on /tmp/trace.log"},
+		{"Trace off", NewTraceCommand(false, ""), "trace off"},
+		{"Trace off ignores path", NewTraceCommand(false, "/tmp/trace.log"), "trace off"},
+		{"Graphics mode", NewGraphicsModeCommand(), "gfx"},
+		{"Set graphics mode", NewSetGraphicsModeCommand(0), "gfx 0"},
+		{"Set graphics mode 8", NewSetGraphicsModeCommand(8), "gfx 8"},
+		{"Server log", NewServerLogCommand(), "log"},
+		{"Server log clear", NewServerLogClearCommand(), "log clear"},
 		{"Registers (modify)", NewRegistersCommand([]RegisterModification{
 			{Name: "A", Value: 0x50},
 			{Name: "X", Value: 0x10},
 		}), "registers A=$0050 X=$0010"},
 		{"Breakpoint Set", NewBreakpointSetCommand(0x0600), "breakpoint set $0600"},
+		{"Breakpoint Set Hits", NewBreakpointSetHitsCommand(0x0600, 5), "breakpoint set $0600 hits 5"},
+		{"Breakpoint Set Once", NewBreakpointSetOnceCommand(0x0600), "breakpoint set $0600 once"},
+		{"Breakpoint Set Condition", NewBreakpointSetConditionCommand(0x0600, "A==$00"), "breakpoint set $0600 if A==$00"},
+		{"Breakpoint Set Condition Once", func() Command {
+			cmd := NewBreakpointSetConditionCommand(0x0600, "A==$00 && X>$10")
+			cmd.Once = true
+			return cmd
+		}(), "breakpoint set $0600 if A==$00 && X>$10 once"},
 		{"Breakpoint Clear", NewBreakpointClearCommand(0x0600), "breakpoint clear $0600"},
+		{"Breakpoint Enable", NewBreakpointEnableCommand(0x0600, true), "breakpoint enable $0600"},
+		{"Breakpoint Disable", NewBreakpointEnableCommand(0x0600, false), "breakpoint disable $0600"},
 		{"Breakpoint ClearAll", NewBreakpointClearAllCommand(), "breakpoint clearall"},
 		{"Breakpoint List", NewBreakpointListCommand(), "breakpoint list"},
+		{"Breakpoint List Detailed", NewBreakpointListDetailedCommand(), "breakpoint list detailed"},
 		{"Disassemble (default)", NewDisassembleCommand(nil, nil), "disassemble"},
 		{"Disassemble (address)", func() Command {
 			addr := uint16(0x0600)
@@ -80,12 +184,16 @@ func TestCommandFormatting(t *testing.T) {
 		{"Assemble", NewAssembleCommand(0x0600), "assemble $0600"},
 		{"AssembleLine", NewAssembleLineCommand(0x0600, "LDA #$00"), "assemble $0600 LDA #$00"},
 		{"StepOver", NewStepOverCommand(), "stepover"},
+		{"StepOut", NewStepOutCommand(), "stepout"},
 		{"RunUntil", NewRunUntilCommand(0x0700), "until $0700"},
+		{"RunUntilReturn", NewRunUntilReturnCommand(), "until ret"},
 		{"MemoryFill", NewMemoryFillCommand(0x0600, 0x06FF, 0x00), "fill $0600 $06FF $00"},
 		{"Mount", NewMountCommand(1, "/path/to/disk.atr"), "mount 1 /path/to/disk.atr"},
+		{"MountSpaces", NewMountCommand(1, "/My Disks/disk.atr"), "mount 1 /My Disks/disk.atr"},
 		{"Unmount", NewUnmountCommand(1), "unmount 1"},
 		{"Drives", NewDrivesCommand(), "drives"},
 		{"Boot", NewBootCommand("/path/to/game.xex"), "boot /path/to/game.xex"},
+		{"Boot info", NewBootInfoCommand(), "bootinfo"},
 		{"StateSave", NewStateSaveCommand("/path/to/state"), "state save /path/to/state"},
 		{"StateLoad", NewStateLoadCommand("/path/to/state"), "state load /path/to/state"},
 		{"Screenshot (no path)", NewScreenshotCommand(""), "screenshot"},
@@ -93,6 +201,8 @@ func TestCommandFormatting(t *testing.T) {
 		{"InjectBasic", NewInjectBasicCommand("SGVsbG8="), "inject basic SGVsbG8="},
 		{"InjectKeys", NewInjectKeysCommand("Hello\n"), "inject keys Hello\\n"},
 		{"InjectKeys with space", NewInjectKeysCommand("Hello World"), "inject keys Hello\\sWorld"},
+		{"InjectKeyCodes", NewInjectKeyCodesCommand([]byte{0x7D, 0x41, 0x42}), "inject codes 7D,41,42"},
+		{"InjectKeyCodes single", NewInjectKeyCodesCommand([]byte{0x9B}), "inject codes 9B"},
 		{"BasicLine", NewBasicLineCommand("10 PRINT HELLO"), "basic 10 PRINT HELLO"},
 		{"BasicNew", NewBasicNewCommand(), "basic NEW"},
 		{"BasicRun", NewBasicRunCommand(), "basic RUN"},
@@ -111,7 +221,19 @@ func TestCommandFormatting(t *testing.T) {
 		{"BasicCont", NewBasicContCommand(), "basic CONT"},
 		{"BasicVars", NewBasicVarsCommand(), "basic VARS"},
 		{"BasicVar", NewBasicVarCommand("X"), "basic VAR X"},
+		{"BasicSet numeric", NewBasicSetCommand("X", "42"), "basic SET X 42"},
+		{"BasicSet string", NewBasicSetCommand("A$", "HI"), `basic SET A$ "HI"`},
+		{"BasicSet string with spaces", NewBasicSetCommand("A$", "HI THERE"), `basic SET A$ "HI\sTHERE"`},
+		{"BasicSet empty string", NewBasicSetCommand("A$", ""), `basic SET A$ ""`},
 		{"BasicInfo", NewBasicInfoCommand(), "basic INFO"},
+		{"BasicFree", NewBasicFreeCommand(), "basic FRE"},
+		{"BasicStep", NewBasicStepCommand(), "basic STEP"},
+		{"BasicRefs", NewBasicRefsCommand(100), "basic REFS 100"},
+		{"BasicFind", NewBasicFindCommand("PRINT"), "basic FIND PRINT"},
+		{"BasicTokens", NewBasicTokensCommand(), "basic TOKENS"},
+		{"BasicFind with spaces", NewBasicFindCommand(`PRINT "HI`), `basic FIND PRINT\s"HI`},
+		{"BasicTraceOn", NewBasicTraceCommand(true), "basic TRACE on"},
+		{"BasicTraceOff", NewBasicTraceCommand(false), "basic TRACE off"},
 		{"BasicExport", NewBasicExportCommand("/path/to/file.bas"), "basic EXPORT /path/to/file.bas"},
 		{"BasicImport", NewBasicImportCommand("/path/to/file.bas"), "basic IMPORT /path/to/file.bas"},
 		{"BasicDir (no drive)", NewBasicDirCommand(nil), "basic DIR"},
@@ -151,18 +273,23 @@ func TestCommandFormatting(t *testing.T) {
 		{"DosType", NewDosTypeCommand("README.TXT"), "dos type README.TXT"},
 		{"DosDump", NewDosDumpCommand("DATA.DAT"), "dos dump DATA.DAT"},
 		{"DosCopy", NewDosCopyCommand("D1:FILE.BAS", "D2:FILE.BAS"), "dos copy D1:FILE.BAS D2:FILE.BAS"},
+		{"DosDiff", NewDosDiffCommand("D1:FILE.BAS", "D2:FILE.BAS"), "dos diff D1:FILE.BAS D2:FILE.BAS"},
 		{"DosRename", NewDosRenameCommand("OLDNAME", "NEWNAME"), "dos rename OLDNAME NEWNAME"},
 		{"DosDelete", NewDosDeleteCommand("JUNK.TMP"), "dos delete JUNK.TMP"},
 		{"DosLock", NewDosLockCommand("PROTECT.BAS"), "dos lock PROTECT.BAS"},
 		{"DosUnlock", NewDosUnlockCommand("PROTECT.BAS"), "dos unlock PROTECT.BAS"},
 		{"DosExport", NewDosExportCommand("PROGRAM.BAS", "/host/path/program.bas"), "dos export PROGRAM.BAS /host/path/program.bas"},
 		{"DosImport", NewDosImportCommand("/host/path/file.bas", "FILE.BAS"), "dos import /host/path/file.bas FILE.BAS"},
+		{"DosImportBackslash", NewDosImportCommand(`/host/back\slash.bas`, "FILE.BAS"), `dos import /host/back\\slash.bas FILE.BAS`},
 		{"DosNewDisk (no type)", NewDosNewDiskCommand("/path/to/disk.atr", nil), "dos newdisk /path/to/disk.atr"},
 		{"DosNewDisk (with type)", func() Command {
 			diskType := "dd"
 			return NewDosNewDiskCommand("/path/to/disk.atr", &diskType)
 		}(), "dos newdisk /path/to/disk.atr dd"},
 		{"DosFormat", NewDosFormatCommand(), "dos format"},
+		{"DosCheck", NewDosCheckCommand(), "dos check"},
+		{"DosProtectOn", NewDosProtectCommand(true), "dos protect on"},
+		{"DosProtectOff", NewDosProtectCommand(false), "dos protect off"},
 	}
 
 	for _, tt := range tests {
@@ -227,6 +354,736 @@ func TestResponseLines(t *testing.T) {
 	}
 }
 
+// TestResponseLinesSeparator verifies that a response received with a
+// non-default separator splits on it.
+func TestResponseLinesSeparator(t *testing.T) {
+	resp := Response{Type: ResponseOK, Data: "line1\x1Fline2\x1Eline3", separator: "\x1F"}
+	if got := resp.Lines(); !slices.Equal(got, []string{"line1", "line2\x1Eline3"}) {
+		t.Errorf("Lines() = %q", got)
+	}
+	if got := resp.Text(); got != "line1\nline2\x1Eline3" {
+		t.Errorf("Text() = %q", got)
+	}
+	if got := NewMultiLineResponse([]string{"a", "b"}).Text(); got != "a\nb" {
+		t.Errorf("default Text() = %q, want %q", got, "a\nb")
+	}
+}
+
+// TestResponseErr verifies Err returns nil for OK and a typed error for ERR responses.
+func TestResponseErr(t *testing.T) {
+	if err := NewOKResponse("pong").Err(); err != nil {
+		t.Errorf("OK response Err() = %v, want nil", err)
+	}
+
+	tests := []struct {
+		name    string
+		data    string
+		code    string
+		message string
+	}{
+		{"Plain message", "Invalid address 'ZZZZ'", "", "Invalid address 'ZZZZ'"},
+		{"With code", "NOT_FOUND: File not found", "NOT_FOUND", "File not found"},
+		{"Lowercase prefix is not a code", "Assembly error: Unknown mnemonic", "", "Assembly error: Unknown mnemonic"},
+		{"Drive spec is not a code", "D1: not mounted", "", "D1: not mounted"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := NewErrorResponse(tt.data).Err()
+			var perr *ProtocolError
+			if !errors.As(err, &perr) {
+				t.Fatalf("Err() = %v, want *ProtocolError", err)
+			}
+			if perr.Code != tt.code {
+				t.Errorf("Code = %q, want %q", perr.Code, tt.code)
+			}
+			if perr.Message != tt.message {
+				t.Errorf("Message = %q, want %q", perr.Message, tt.message)
+			}
+		})
+	}
+}
+
+// TestResponseAsBytes tests parsing of memory read responses.
+func TestResponseAsBytes(t *testing.T) {
+	tests := []struct {
+		name     string
+		resp     Response
+		expected []byte
+		wantErr  bool
+	}{
+		{"bytes", NewOKResponse("data A9,00,8D,00,D4"), []byte{0xA9, 0x00, 0x8D, 0x00, 0xD4}, false},
+		{"single", NewOKResponse("data 60"), []byte{0x60}, false},
+		{"empty", NewOKResponse("data "), []byte{}, false},
+		{"not data", NewOKResponse("written 5"), nil, true},
+		{"bad byte", NewOKResponse("data A9,ZZ"), nil, true},
+		{"error", NewErrorResponse("Invalid address 'GGGG'"), nil, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.resp.AsBytes()
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("AsBytes() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && !bytes.Equal(got, tt.expected) {
+				t.Errorf("AsBytes() = %X, want %X", got, tt.expected)
+			}
+		})
+	}
+}
+
+// TestResponseAsVersion tests extraction of the version from "version"
+// responses.
+func TestResponseAsVersion(t *testing.T) {
+	tests := []struct {
+		name    string
+		resp    Response
+		want    ServerVersionInfo
+		wantErr bool
+	}{
+		{"attic", NewOKResponse("Attic v0.2.0 (Go)"), ServerVersionInfo{Name: "Attic", Version: "0.2.0", Major: 0, Minor: 2, Patch: 0, Build: "Go"}, false},
+		{"version prefix", NewOKResponse("version 1.0"), ServerVersionInfo{Version: "1.0", Major: 1, Minor: 0}, false},
+		{"pre-release", NewOKResponse("AtticServer 1.3.0-beta.2"), ServerVersionInfo{Name: "AtticServer", Version: "1.3.0-beta.2", Major: 1, Minor: 3, PreRelease: "beta.2"}, false},
+		{"pre-release and build", NewOKResponse("1.2.0-beta+42"), ServerVersionInfo{Version: "1.2.0-beta+42", Major: 1, Minor: 2, PreRelease: "beta", Build: "42"}, false},
+		{"pre-release and label", NewOKResponse("Attic v2.10.4-rc1 (Swift)"), ServerVersionInfo{Name: "Attic", Version: "2.10.4-rc1", Major: 2, Minor: 10, Patch: 4, PreRelease: "rc1", Build: "Swift"}, false},
+		{"no version", NewOKResponse("Attic (Go)"), ServerVersionInfo{}, true},
+		{"error", NewErrorResponse("Unknown command 'version'"), ServerVersionInfo{}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.resp.AsVersion()
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("AsVersion() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("AsVersion() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+// TestResponseAsBasicVars tests parsing of "basic vars" listings.
+func TestResponseAsBasicVars(t *testing.T) {
+	resp := NewMultiLineResponse([]string{`X = 42`, `A$ = "HELLO"`, `B(10) = 0`, `COUNT=7`})
+	got, err := resp.AsBasicVars()
+	if err != nil {
+		t.Fatalf("AsBasicVars() error = %v", err)
+	}
+	want := []BasicVar{
+		{Name: "X", Kind: BasicVarNumeric, Value: "42"},
+		{Name: "A$", Kind: BasicVarString, Value: `"HELLO"`},
+		{Name: "B(10)", Kind: BasicVarArray, Value: "0"},
+		{Name: "COUNT", Kind: BasicVarNumeric, Value: "7"},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("AsBasicVars() = %+v, want %+v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("var %d = %+v, want %+v", i, got[i], want[i])
+		}
+	}
+
+	if vars, err := NewOKResponse("").AsBasicVars(); err != nil || len(vars) != 0 {
+		t.Errorf("empty listing = %v, %v; want no vars", vars, err)
+	}
+	if _, err := NewMultiLineResponse([]string{"X = 1", "garbage"}).AsBasicVars(); err == nil {
+		t.Error("malformed line should return an error")
+	}
+	if _, err := NewErrorResponse("No program").AsBasicVars(); err == nil {
+		t.Error("error response should return an error")
+	}
+}
+
+// TestResponseAsStatus tests parsing of status responses.
+func TestResponseAsStatus(t *testing.T) {
+	status, err := NewOKResponse("status running PC=$E477 D1=/path/to/disk.atr D2=(none) BP=$600A,$602F").AsStatus()
+	if err != nil {
+		t.Fatalf("AsStatus() error = %v", err)
+	}
+	if !status.Running || status.PC != 0xE477 {
+		t.Errorf("Running, PC = %v, $%04X; want true, $E477", status.Running, status.PC)
+	}
+	if len(status.Drives) != 1 || status.Drives[1] != "/path/to/disk.atr" {
+		t.Errorf("Drives = %v", status.Drives)
+	}
+	if len(status.Breakpoints) != 2 || status.Breakpoints[0] != 0x600A || status.Breakpoints[1] != 0x602F {
+		t.Errorf("Breakpoints = %X", status.Breakpoints)
+	}
+
+	paused, err := NewOKResponse("status paused PC=$0600 BP=(none)").AsStatus()
+	if err != nil || paused.Running || len(paused.Breakpoints) != 0 {
+		t.Errorf("paused status = %+v, %v", paused, err)
+	}
+
+	for _, bad := range []Response{
+		NewOKResponse("pong"),
+		NewOKResponse("status sleeping"),
+		NewOKResponse("status running PC=$ZZZZ"),
+		NewErrorResponse("not ready"),
+	} {
+		if _, err := bad.AsStatus(); err == nil {
+			t.Errorf("AsStatus(%q) should fail", bad.Format())
+		}
+	}
+}
+
+// TestResponseAsServerLog tests parsing of the server command log.
+func TestResponseAsServerLog(t *testing.T) {
+	got, err := NewMultiLineResponse([]string{"log", "ping", "read $0600 16", "breakpoint set $0600"}).AsServerLog()
+	want := []string{"ping", "read $0600 16", "breakpoint set $0600"}
+	if err != nil || !slices.Equal(got, want) {
+		t.Errorf("AsServerLog() = %q, %v; want %q", got, err, want)
+	}
+
+	got, err = NewOKResponse("log (empty)").AsServerLog()
+	if err != nil || len(got) != 0 {
+		t.Errorf("AsServerLog() on empty log = %q, %v", got, err)
+	}
+
+	for _, bad := range []Response{
+		NewOKResponse("log cleared"),
+		NewOKResponse("ping"),
+		NewErrorResponse("Unknown command"),
+	} {
+		if _, err := bad.AsServerLog(); err == nil {
+			t.Errorf("AsServerLog(%q) should fail", bad.Format())
+		}
+	}
+}
+
+func TestResponseAsGraphicsMode(t *testing.T) {
+	if mode, err := NewOKResponse("gfx 8").AsGraphicsMode(); err != nil || mode != 8 {
+		t.Errorf("AsGraphicsMode() = %d, %v; want 8", mode, err)
+	}
+	for _, bad := range []Response{
+		NewOKResponse("gfx"),
+		NewOKResponse("gfx eight"),
+		NewOKResponse("mode 8"),
+		NewErrorResponse("Unknown command"),
+	} {
+		if _, err := bad.AsGraphicsMode(); err == nil {
+			t.Errorf("AsGraphicsMode(%q) should fail", bad.Format())
+		}
+	}
+}
+
+func TestResponseAsCycles(t *testing.T) {
+	if cycles, err := NewOKResponse("cycles 12345678901").AsCycles(); err != nil || cycles != 12345678901 {
+		t.Errorf("AsCycles() = %d, %v; want 12345678901", cycles, err)
+	}
+	for _, bad := range []Response{
+		NewOKResponse("cycles"),
+		NewOKResponse("cycles -5"),
+		NewOKResponse("cycles $FF"),
+		NewOKResponse("status running"),
+		NewErrorResponse("Unknown command"),
+	} {
+		if _, err := bad.AsCycles(); err == nil {
+			t.Errorf("AsCycles(%q) should fail", bad.Format())
+		}
+	}
+}
+
+func TestResponseAsBasicFree(t *testing.T) {
+	if free, err := NewOKResponse("fre 37902").AsBasicFree(); err != nil || free != 37902 {
+		t.Errorf("AsBasicFree() = %d, %v; want 37902", free, err)
+	}
+	for _, bad := range []Response{
+		NewOKResponse("fre"),
+		NewOKResponse("fre -1"),
+		NewOKResponse("fre lots"),
+		NewOKResponse("program size=1234"),
+		NewErrorResponse("BASIC not active"),
+	} {
+		if _, err := bad.AsBasicFree(); err == nil {
+			t.Errorf("AsBasicFree(%q) should fail", bad.Format())
+		}
+	}
+}
+
+func TestResponseAsFindResults(t *testing.T) {
+	resp := NewMultiLineResponse([]string{`10 PRINT "HI"`, "200 PRINT X;Y"})
+	got, err := resp.AsFindResults()
+	if err != nil {
+		t.Fatalf("AsFindResults() error = %v", err)
+	}
+	want := []BasicLine{{Number: 10, Text: `PRINT "HI"`}, {Number: 200, Text: "PRINT X;Y"}}
+	if !slices.Equal(got, want) {
+		t.Errorf("AsFindResults() = %+v, want %+v", got, want)
+	}
+
+	if lines, err := NewOKResponse("").AsFindResults(); err != nil || len(lines) != 0 {
+		t.Errorf("AsFindResults() for no match = %v, %v; want none", lines, err)
+	}
+	if _, err := NewMultiLineResponse([]string{"10 PRINT", "PRINT"}).AsFindResults(); err == nil {
+		t.Error("AsFindResults() should fail for a line without a number")
+	}
+	if _, err := NewErrorResponse("No program").AsFindResults(); err == nil {
+		t.Error("AsFindResults() should fail for an error response")
+	}
+}
+
+func TestResponseAsDiskCheck(t *testing.T) {
+	t.Run("clean", func(t *testing.T) {
+		got, err := NewOKResponse("clean").AsDiskCheck()
+		if err != nil {
+			t.Fatalf("AsDiskCheck() error = %v", err)
+		}
+		if !got.Clean() || len(got.Problems) != 0 {
+			t.Errorf("AsDiskCheck() = %+v, want clean", got)
+		}
+	})
+
+	t.Run("corrupt", func(t *testing.T) {
+		problems := []string{
+			"sector 45 used by GAME.BAS but free in VTOC",
+			"directory entry 3 links to sector 800, past end of disk",
+		}
+		resp := NewMultiLineResponse(append([]string{"errors 2"}, problems...))
+		got, err := resp.AsDiskCheck()
+		if err != nil {
+			t.Fatalf("AsDiskCheck() error = %v", err)
+		}
+		if got.Clean() || !slices.Equal(got.Problems, problems) {
+			t.Errorf("AsDiskCheck() = %+v, want %q", got, problems)
+		}
+	})
+
+	for _, bad := range []Response{
+		NewOKResponse(""),
+		NewOKResponse("ok"),
+		NewOKResponse("errors 0"),
+		NewOKResponse("errors two"),
+		NewMultiLineResponse([]string{"errors 2", "sector 45 used by GAME.BAS but free in VTOC"}),
+		NewMultiLineResponse([]string{"clean", "sector 45 used by GAME.BAS but free in VTOC"}),
+		NewErrorResponse("No disk mounted"),
+	} {
+		if _, err := bad.AsDiskCheck(); err == nil {
+			t.Errorf("AsDiskCheck(%q) should fail", bad.Format())
+		}
+	}
+}
+
+func TestResponseAsDiffResult(t *testing.T) {
+	tests := []struct {
+		resp       Response
+		wantMatch  bool
+		wantOffset int
+		wantErr    bool
+	}{
+		{NewOKResponse("match"), true, -1, false},
+		{NewOKResponse("differ 1100"), false, 1100, false},
+		{NewOKResponse("differ 0"), false, 0, false},
+		{NewOKResponse("differ"), false, 0, true},
+		{NewOKResponse("differ -3"), false, 0, true},
+		{NewOKResponse("copied"), false, 0, true},
+		{NewErrorResponse("File not found"), false, 0, true},
+	}
+	for _, tt := range tests {
+		match, offset, err := tt.resp.AsDiffResult()
+		if (err != nil) != tt.wantErr {
+			t.Errorf("AsDiffResult(%q) error = %v, wantErr %v", tt.resp.Format(), err, tt.wantErr)
+			continue
+		}
+		if !tt.wantErr && (match != tt.wantMatch || offset != tt.wantOffset) {
+			t.Errorf("AsDiffResult(%q) = %v, %d; want %v, %d", tt.resp.Format(), match, offset, tt.wantMatch, tt.wantOffset)
+		}
+	}
+}
+
+func TestResponseAsLineRefs(t *testing.T) {
+	refs, err := NewOKResponse("refs 20,150,300").AsLineRefs()
+	if err != nil || !slices.Equal(refs, []int{20, 150, 300}) {
+		t.Errorf("AsLineRefs() = %v, %v; want [20 150 300]", refs, err)
+	}
+	for _, none := range []string{"refs", "refs (none)"} {
+		if refs, err := NewOKResponse(none).AsLineRefs(); err != nil || len(refs) != 0 {
+			t.Errorf("AsLineRefs(%q) = %v, %v; want none", none, refs, err)
+		}
+	}
+	for _, bad := range []Response{
+		NewOKResponse("refs 20,x"),
+		NewOKResponse("refs 20 30"),
+		NewOKResponse("fre 37902"),
+		NewErrorResponse("No program"),
+	} {
+		if _, err := bad.AsLineRefs(); err == nil {
+			t.Errorf("AsLineRefs(%q) should fail", bad.Format())
+		}
+	}
+}
+
+func TestResponseAsBasicStep(t *testing.T) {
+	tests := []struct {
+		resp     Response
+		wantLine int
+		wantOK   bool
+	}{
+		{NewOKResponse("step 30"), 30, true},
+		{NewOKResponse("step 0"), 0, true},
+		{NewOKResponse("step end"), 0, false},
+		{NewOKResponse("step 40000"), 0, false},
+		{NewOKResponse("step"), 0, false},
+		{NewOKResponse("fre 37902"), 0, false},
+		{NewErrorResponse("No program running"), 0, false},
+	}
+	for _, tt := range tests {
+		line, ok := tt.resp.AsBasicStep()
+		if line != tt.wantLine || ok != tt.wantOK {
+			t.Errorf("AsBasicStep(%q) = %d, %v; want %d, %v", tt.resp.Format(), line, ok, tt.wantLine, tt.wantOK)
+		}
+	}
+}
+
+func TestParseAssembleInputResponse(t *testing.T) {
+	tests := []struct {
+		name     string
+		data     string
+		wantLine string
+		wantAddr uint16
+		wantOK   bool
+	}{
+		{"Well-formed", "$0600: A9 00     LDA #$00\x1E$0602", "$0600: A9 00     LDA #$00", 0x0602, true},
+		{"Three bytes", "$0602: 8D 00 D4  STA $D400\x1E$0605", "$0602: 8D 00 D4  STA $D400", 0x0605, true},
+		{"Missing address", "$0600: A9 00     LDA #$00", "", 0, false},
+		{"Empty address", "$0600: A9 00     LDA #$00\x1E", "", 0, false},
+		{"Missing line", "\x1E$0602", "", 0, false},
+		{"Malformed address", "$0600: EA        NOP\x1E$06G1", "", 0, false},
+		{"Address without $", "$0600: EA        NOP\x1E0601", "", 0, false},
+		{"Extra line", "$0600: EA        NOP\x1E$0601\x1E$0602", "", 0, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			line, addr, ok := ParseAssembleInputResponse(tt.data)
+			if line != tt.wantLine || addr != tt.wantAddr || ok != tt.wantOK {
+				t.Errorf("ParseAssembleInputResponse(%q) = %q, $%04X, %v; want %q, $%04X, %v",
+					tt.data, line, addr, ok, tt.wantLine, tt.wantAddr, tt.wantOK)
+			}
+		})
+	}
+}
+
+// TestResponseAsAssembleInput verifies that the line and next address
+// are split on the response's own separator.
+func TestResponseAsAssembleInput(t *testing.T) {
+	resp := Response{Type: ResponseOK, Data: "$0600: A9 00     LDA #$00\x1F$0602", separator: "\x1F"}
+	line, addr, ok := resp.AsAssembleInput()
+	if line != "$0600: A9 00     LDA #$00" || addr != 0x0602 || !ok {
+		t.Errorf("AsAssembleInput() = %q, $%04X, %v; want %q, $0602, true", line, addr, ok, "$0600: A9 00     LDA #$00")
+	}
+
+	// The default separator is just data under another one.
+	resp.Data = "$0600: A9 00     LDA #$00\x1E$0602"
+	if _, _, ok := resp.AsAssembleInput(); ok {
+		t.Error("AsAssembleInput() split on \\x1E with separator \\x1F")
+	}
+
+	errResp := Response{Type: ResponseError, Data: "Invalid instruction"}
+	if _, _, ok := errResp.AsAssembleInput(); ok {
+		t.Error("AsAssembleInput() accepted an error response")
+	}
+}
+
+func TestResponseAsBootInfo(t *testing.T) {
+	tests := []struct {
+		data string
+		want BootInfo
+	}{
+		{"bootinfo disk 1 /path/to/game.atr", BootInfo{Source: BootDisk, Drive: 1, Path: "/path/to/game.atr"}},
+		{"bootinfo xex /My Games/demo.xex", BootInfo{Source: BootExecutable, Path: "/My Games/demo.xex"}},
+		{"bootinfo cart /roms/star raiders.car", BootInfo{Source: BootCartridge, Path: "/roms/star raiders.car"}},
+		{"bootinfo none", BootInfo{Source: BootNone}},
+	}
+	for _, tt := range tests {
+		got, err := NewOKResponse(tt.data).AsBootInfo()
+		if err != nil || got != tt.want {
+			t.Errorf("AsBootInfo(%q) = %+v, %v; want %+v", tt.data, got, err, tt.want)
+		}
+	}
+
+	for _, bad := range []Response{
+		NewOKResponse("bootinfo"),
+		NewOKResponse("bootinfo tape /path/game.cas"),
+		NewOKResponse("bootinfo disk 9 /path/game.atr"),
+		NewOKResponse("bootinfo disk 1"),
+		NewOKResponse("bootinfo xex"),
+		NewOKResponse("bootinfo none /path"),
+		NewOKResponse("status running"),
+		NewErrorResponse("Unknown command"),
+	} {
+		if _, err := bad.AsBootInfo(); err == nil {
+			t.Errorf("AsBootInfo(%q) should fail", bad.Format())
+		}
+	}
+}
+
+func TestResponseAsChecksum(t *testing.T) {
+	if sum, err := NewOKResponse("checksum $1A2B3C4D").AsChecksum(); err != nil || sum != 0x1A2B3C4D {
+		t.Errorf("AsChecksum() = $%08X, %v; want $1A2B3C4D", sum, err)
+	}
+	for _, bad := range []Response{
+		NewOKResponse("checksum"),
+		NewOKResponse("checksum 1A2B3C4D"),
+		NewOKResponse("checksum $1A2B3C4D5"),
+		NewOKResponse("crc $1A2B3C4D"),
+		NewErrorResponse("Invalid range"),
+	} {
+		if _, err := bad.AsChecksum(); err == nil {
+			t.Errorf("AsChecksum(%q) should fail", bad.Format())
+		}
+	}
+}
+
+func TestResponseAsMemoryMap(t *testing.T) {
+	resp := NewMultiLineResponse([]string{
+		"$0000-$9FFF RAM Main memory",
+		"$A000-$BFFF rom BASIC",
+		"$D000-$D0FF IO GTIA",
+		"$D800-$FFFF ROM",
+	})
+	want := []MemoryRegion{
+		{0x0000, 0x9FFF, "RAM", "Main memory"},
+		{0xA000, 0xBFFF, "ROM", "BASIC"},
+		{0xD000, 0xD0FF, "IO", "GTIA"},
+		{0xD800, 0xFFFF, "ROM", ""},
+	}
+	got, err := resp.AsMemoryMap()
+	if err != nil {
+		t.Fatalf("AsMemoryMap() error = %v", err)
+	}
+	if !slices.Equal(got, want) {
+		t.Errorf("AsMemoryMap() = %+v, want %+v", got, want)
+	}
+
+	for _, bad := range []Response{
+		NewOKResponse("$0000-$9FFF"),
+		NewOKResponse("$0000 RAM"),
+		NewOKResponse("$9FFF-$0000 RAM"),
+		NewOKResponse("$0000-$GGGG RAM"),
+		NewErrorResponse("Unknown command"),
+	} {
+		if _, err := bad.AsMemoryMap(); err == nil {
+			t.Errorf("AsMemoryMap(%q) should fail", bad.Format())
+		}
+	}
+}
+
+// TestResponseAsRegisters tests parsing of register dumps.
+func TestResponseAsRegisters(t *testing.T) {
+	want := Registers{A: 0x4F, X: 0x03, Y: 0x00, S: 0xFD, P: 0x30, PC: 0xE4A2}
+	for _, data := range []string{
+		"A=$4F X=$03 Y=$00 S=$FD P=$30 PC=$E4A2",
+		"stepped A=$4F X=$03 Y=$00 S=$FD P=$30 PC=$E4A2",
+	} {
+		got, err := NewOKResponse(data).AsRegisters()
+		if err != nil {
+			t.Fatalf("AsRegisters(%q) error = %v", data, err)
+		}
+		if got != want {
+			t.Errorf("AsRegisters(%q) = %+v, want %+v", data, got, want)
+		}
+	}
+
+	for _, bad := range []Response{
+		NewOKResponse("A=$00 X=$00"),
+		NewOKResponse("A=$100 X=$00 Y=$00 S=$FF P=$34 PC=$E477"),
+		NewErrorResponse("not paused"),
+	} {
+		if _, err := bad.AsRegisters(); err == nil {
+			t.Errorf("AsRegisters(%q) should fail", bad.Format())
+		}
+	}
+}
+
+// TestResponseAsDisassembly tests parsing of disassembly listings.
+func TestResponseAsDisassembly(t *testing.T) {
+	resp := NewMultiLineResponse([]string{
+		"$0600  A9 00     LDA #$00",
+		"$0602  8D 00 D4  STA DMACTL",
+		"$060A  60        RTS",
+	})
+	got, err := resp.AsDisassembly()
+	if err != nil {
+		t.Fatalf("AsDisassembly() error = %v", err)
+	}
+	want := []DisassemblyLine{
+		{0x0600, []byte{0xA9, 0x00}, "LDA #$00"},
+		{0x0602, []byte{0x8D, 0x00, 0xD4}, "STA DMACTL"},
+		{0x060A, []byte{0x60}, "RTS"},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("AsDisassembly() = %+v, want %+v", got, want)
+	}
+	for i := range want {
+		if got[i].Address != want[i].Address || !bytes.Equal(got[i].Bytes, want[i].Bytes) || got[i].Instruction != want[i].Instruction {
+			t.Errorf("line %d = %+v, want %+v", i, got[i], want[i])
+		}
+	}
+
+	for _, bad := range []Response{
+		NewOKResponse("0600 A9 00 LDA #$00"),
+		NewOKResponse("$0600  A9 00"),
+		NewErrorResponse("Invalid address"),
+	} {
+		if _, err := bad.AsDisassembly(); err == nil {
+			t.Errorf("AsDisassembly(%q) should fail", bad.Format())
+		}
+	}
+}
+
+// TestResponseAsDosFileInfo tests parsing of "dos info" responses.
+func TestResponseAsDosFileInfo(t *testing.T) {
+	tests := []struct {
+		data string
+		want DosFileInfo
+	}{
+		{"GAME.BAS 2048 bytes 8 sectors locked=0", DosFileInfo{"GAME.BAS", 2048, 8, false}},
+		{"READONLY.BAS 125 bytes 1 sectors locked=1", DosFileInfo{"READONLY.BAS", 125, 1, true}},
+		{"AUTORUN.SYS 3 sectors 300 bytes locked", DosFileInfo{"AUTORUN.SYS", 300, 3, true}},
+		{"locked=true sectors=2 name=DATA.DAT bytes=250", DosFileInfo{"DATA.DAT", 250, 2, true}},
+		{"Name:    GAME.BAS\x1eSize:    2048 bytes (8 sectors)\x1eLocked:  no", DosFileInfo{"GAME.BAS", 2048, 8, false}},
+		{"Name:    DOS.SYS\x1eSize:    4875 bytes (39 sectors)\x1eLocked:  yes\x1eWARNING: File appears corrupted", DosFileInfo{"DOS.SYS", 4875, 39, true}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.data, func(t *testing.T) {
+			resp := NewMultiLineResponse(strings.Split(tt.data, MultiLineSeparator))
+			got, err := resp.AsDosFileInfo()
+			if err != nil {
+				t.Fatalf("AsDosFileInfo() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("AsDosFileInfo() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+
+	for _, bad := range []Response{
+		NewOKResponse(""),
+		NewOKResponse("GAME.BAS 2048 bytes"),
+		NewOKResponse("GAME.BAS lots bytes 8 sectors"),
+		NewOKResponse("GAME.BAS 2048 bytes 8 sectors locked=maybe"),
+		NewErrorResponse("File not found"),
+	} {
+		if _, err := bad.AsDosFileInfo(); err == nil {
+			t.Errorf("AsDosFileInfo(%q) should fail", bad.Format())
+		}
+	}
+}
+
+// TestResponseAsDirectory tests parsing of dos dir and basic dir listings.
+func TestResponseAsDirectory(t *testing.T) {
+	tests := []struct {
+		name  string
+		lines []string
+		want  []DirEntry
+	}{
+		{
+			"server listing",
+			[]string{"* DOS.SYS       39 sectors", "  GAME.BAS      12 sectors", "  README         1 sectors"},
+			[]DirEntry{{"DOS.SYS", 39, true}, {"GAME.BAS", 12, false}, {"README", 1, false}},
+		},
+		{
+			"atari dir with footer",
+			[]string{"*DOS      SYS 039", " AUTORUN  SYS 004", "GAME.BAS  012", "652 FREE SECTORS"},
+			[]DirEntry{{"DOS.SYS", 39, true}, {"AUTORUN.SYS", 4, false}, {"GAME.BAS", 12, false}},
+		},
+		{"empty disk", []string{"(empty disk)"}, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := NewMultiLineResponse(tt.lines).AsDirectory()
+			if err != nil {
+				t.Fatalf("AsDirectory() error = %v", err)
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("AsDirectory() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+
+	for _, bad := range []Response{
+		NewOKResponse("GAME.BAS lots"),
+		NewOKResponse("12"),
+		NewErrorResponse("No disk in drive"),
+	} {
+		if _, err := bad.AsDirectory(); err == nil {
+			t.Errorf("AsDirectory(%q) should fail", bad.Format())
+		}
+	}
+}
+
+func TestResponseAsBreakpoints(t *testing.T) {
+	got, err := NewOKResponse("breakpoints $600A,$602F,$E477").AsBreakpoints()
+	if err != nil || !slices.Equal(got, []uint16{0x600A, 0x602F, 0xE477}) {
+		t.Errorf("AsBreakpoints() = %X, %v", got, err)
+	}
+	got, err = NewOKResponse("breakpoints (none)").AsBreakpoints()
+	if err != nil || len(got) != 0 {
+		t.Errorf("AsBreakpoints() on empty list = %X, %v", got, err)
+	}
+	got, err = NewMultiLineResponse([]string{"breakpoints", "$0600 hits 2", "$0700 disabled"}).AsBreakpoints()
+	if err != nil || !slices.Equal(got, []uint16{0x0600, 0x0700}) {
+		t.Errorf("AsBreakpoints() on detailed list = %X, %v", got, err)
+	}
+
+	for _, bad := range []Response{
+		NewOKResponse("breakpoints $60ZZ"),
+		NewOKResponse("status paused"),
+		NewErrorResponse("Not connected"),
+	} {
+		if _, err := bad.AsBreakpoints(); err == nil {
+			t.Errorf("AsBreakpoints(%q) should fail", bad.Format())
+		}
+	}
+}
+
+func TestResponseAsBreakpointsDetailed(t *testing.T) {
+	resp := NewMultiLineResponse([]string{
+		"breakpoints",
+		"$600A",
+		"$602F hits 5 once",
+		"$E477 disabled if A==$00 && X>$10",
+		"$E480 once if PEEK($D01F)==6",
+	})
+	want := []Breakpoint{
+		{Address: 0x600A, Enabled: true},
+		{Address: 0x602F, HitCount: 5, Once: true, Enabled: true},
+		{Address: 0xE477, Condition: "A==$00 && X>$10"},
+		{Address: 0xE480, Condition: "PEEK($D01F)==6", Once: true, Enabled: true},
+	}
+	got, err := resp.AsBreakpointsDetailed()
+	if err != nil {
+		t.Fatalf("AsBreakpointsDetailed() error = %v", err)
+	}
+	if !slices.Equal(got, want) {
+		t.Errorf("AsBreakpointsDetailed() = %+v, want %+v", got, want)
+	}
+
+	got, err = NewOKResponse("breakpoints (none)").AsBreakpointsDetailed()
+	if err != nil || len(got) != 0 {
+		t.Errorf("AsBreakpointsDetailed() on empty list = %+v, %v", got, err)
+	}
+
+	for _, bad := range []Response{
+		NewMultiLineResponse([]string{"breakpoints", "0600"}),
+		NewMultiLineResponse([]string{"breakpoints", "$0600 hits"}),
+		NewMultiLineResponse([]string{"breakpoints", "$0600 hits 0"}),
+		NewMultiLineResponse([]string{"breakpoints", "$0600 if"}),
+		NewMultiLineResponse([]string{"breakpoints", "$0600 sometimes"}),
+		NewMultiLineResponse([]string{"$0600"}),
+		NewErrorResponse("Not connected"),
+	} {
+		if _, err := bad.AsBreakpointsDetailed(); err == nil {
+			t.Errorf("AsBreakpointsDetailed(%q) should fail", bad.Format())
+		}
+	}
+}
+
 // TestEventFormatting verifies event formatting matches the protocol.
 func TestEventFormatting(t *testing.T) {
 	tests := []struct {
@@ -250,6 +1107,39 @@ func TestEventFormatting(t *testing.T) {
 	}
 }
 
+// TestResponseAsCapabilities tests parsing of capabilities responses.
+func TestResponseAsCapabilities(t *testing.T) {
+	tests := []struct {
+		name    string
+		resp    Response
+		want    []string
+		wantErr bool
+	}{
+		{"comma list", NewOKResponse("capabilities watchpoints,trace"), []string{"watchpoints", "trace"}, false},
+		{"space list", NewOKResponse("capabilities Watchpoints trace"), []string{"watchpoints", "trace"}, false},
+		{"none", NewOKResponse("capabilities"), nil, false},
+		{"unexpected", NewOKResponse("pong"), nil, true},
+		{"error", NewErrorResponse("Unknown command 'capabilities'"), nil, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.resp.AsCapabilities()
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("AsCapabilities() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if len(got) != len(tt.want) {
+				t.Errorf("AsCapabilities() = %v, want %v", got, tt.want)
+			}
+			for _, feature := range tt.want {
+				if !got[feature] {
+					t.Errorf("AsCapabilities() missing %q", feature)
+				}
+			}
+		})
+	}
+}
+
 // TestCommandParsing verifies command parsing works correctly.
 func TestCommandParsing(t *testing.T) {
 	parser := NewCommandParser()
@@ -262,13 +1152,80 @@ func TestCommandParsing(t *testing.T) {
 		{"Ping", "ping", NewPingCommand()},
 		{"Ping with prefix", "CMD:ping", NewPingCommand()},
 		{"Version", "version", NewVersionCommand()},
+		{"Capabilities", "capabilities", NewCapabilitiesCommand()},
 		{"Step", "step", NewStepCommand(1)},
 		{"Step 5", "step 5", NewStepCommand(5)},
+		{"Frame step", "frame", NewFrameStepCommand(1)},
+		{"Frame step count", "frame 3", NewFrameStepCommand(3)},
+		{"Frame step alias", "fr 2", NewFrameStepCommand(2)},
+		{"Step instruction", "stepi", NewStepInstructionCommand(1)},
+		{"Step instruction 5", "stepi 5", NewStepInstructionCommand(5)},
+		{"Step instruction alias", "si 3", NewStepInstructionCommand(3)},
+		{"Audio on", "audio on", NewAudioCommand(true)},
+		{"Audio OFF", "audio OFF", NewAudioCommand(false)},
+		{"Video read", "video", NewVideoSystemCommand("")},
+		{"Video PAL", "video PAL", NewVideoSystemCommand("pal")},
+		{"Video NTSC", "VIDEO ntsc", NewVideoSystemCommand("ntsc")},
+		{"Step out", "stepout", NewStepOutCommand()},
+		{"Step out alias", "sr", NewStepOutCommand()},
+		{"Until address", "until $0700", NewRunUntilCommand(0x0700)},
+		{"Until return", "until ret", NewRunUntilReturnCommand()},
+		{"Inject codes", "inject codes 7D,41,42", NewInjectKeyCodesCommand([]byte{0x7D, 0x41, 0x42})},
+		{"Inject single code", "inject codes $9B", NewInjectKeyCodesCommand([]byte{0x9B})},
+		{"Until return uppercase", "until RET", NewRunUntilReturnCommand()},
 		{"Read hex", "read $0600 16", NewReadCommand(0x0600, 16)},
 		{"Read 0x", "read 0x0600 16", NewReadCommand(0x0600, 16)},
 		{"Read decimal", "read 1536 16", NewReadCommand(1536, 16)},
+		{"Memory map", "memmap", NewMemoryMapCommand()},
+		{"Cycles", "CYCLES", NewCyclesCommand()},
+		{"Checksum", "checksum $0600 $7FFF", NewMemoryChecksumCommand(0x0600, 0x7FFF)},
+		{"Pattern", "pattern $0600 $06FF DE,AD,BE,EF", NewMemoryPatternCommand(0x0600, 0x06FF, []byte{0xDE, 0xAD, 0xBE, 0xEF})},
+		{"Pattern spaced", "pattern 0x0600 1791 $DE, AD", NewMemoryPatternCommand(0x0600, 0x06FF, []byte{0xDE, 0xAD})},
+		{"Pattern single byte", "pattern $0600 $0600 EA", NewMemoryPatternCommand(0x0600, 0x0600, []byte{0xEA})},
+		{"Trace on", "trace on /tmp/trace.log", NewTraceCommand(true, "/tmp/trace.log")},
+		{"Trace on quoted", `trace ON "/tmp/my trace.log"`, NewTraceCommand(true, "/tmp/my trace.log")},
+		{"Trace off", "trace off", NewTraceCommand(false, "")},
+		{"Checksum single byte", "crc 0x0600 1536", NewMemoryChecksumCommand(0x0600, 0x0600)},
+		{"Graphics mode", "gfx", NewGraphicsModeCommand()},
+		{"Set graphics mode", "gfx 0", NewSetGraphicsModeCommand(0)},
+		{"Set graphics mode 15", "gfx 15", NewSetGraphicsModeCommand(15)},
+		{"Server log", "log", NewServerLogCommand()},
+		{"Server log clear", "log CLEAR", NewServerLogClearCommand()},
 		{"Write", "write $0600 A9,00,8D", NewWriteCommand(0x0600, []byte{0xA9, 0x00, 0x8D})},
 		{"Breakpoint set", "breakpoint set $0600", NewBreakpointSetCommand(0x0600)},
+		{"Breakpoint set hits", "breakpoint set $0600 hits 5", NewBreakpointSetHitsCommand(0x0600, 5)},
+		{"Breakpoint set once", "breakpoint set $0600 once", NewBreakpointSetOnceCommand(0x0600)},
+		{"Breakpoint enable", "breakpoint enable $0600", NewBreakpointEnableCommand(0x0600, true)},
+		{"Breakpoint disable", "breakpoint DISABLE 0x0600", NewBreakpointEnableCommand(0x0600, false)},
+		{"Breakpoint list", "breakpoint list", NewBreakpointListCommand()},
+		{"Breakpoint list detailed", "breakpoint list detailed", NewBreakpointListDetailedCommand()},
+		{"Breakpoint set hits once", "breakpoint set $0600 hits 3 once", func() Command {
+			cmd := NewBreakpointSetHitsCommand(0x0600, 3)
+			cmd.Once = true
+			return cmd
+		}()},
+		{"Breakpoint set condition", "breakpoint set $0600 if A==$00", NewBreakpointSetConditionCommand(0x0600, "A==$00")},
+		{"Breakpoint set condition once", "breakpoint set $0600 if A=$00 once", func() Command {
+			cmd := NewBreakpointSetConditionCommand(0x0600, "A=$00")
+			cmd.Once = true
+			return cmd
+		}()},
+		{"Breakpoint set condition hits", "breakpoint set $0600 if A==$00 hits 5", func() Command {
+			cmd := NewBreakpointSetConditionCommand(0x0600, "A==$00")
+			cmd.HitCount = 5
+			return cmd
+		}()},
+		{"Breakpoint set condition once hits", "breakpoint set $0600 if A==$00 && X>$10 once hits 2", func() Command {
+			cmd := NewBreakpointSetConditionCommand(0x0600, "A==$00 && X>$10")
+			cmd.HitCount = 2
+			cmd.Once = true
+			return cmd
+		}()},
+		{"Breakpoint set once condition", "breakpoint set $0600 ONCE if PEEK($D01F)==6", func() Command {
+			cmd := NewBreakpointSetConditionCommand(0x0600, "PEEK($D01F)==6")
+			cmd.Once = true
+			return cmd
+		}()},
 		{"Disassemble", "d", NewDisassembleCommand(nil, nil)},
 		{"Disassemble address", "disasm $0600", func() Command {
 			addr := uint16(0x0600)
@@ -280,13 +1237,33 @@ func TestCommandParsing(t *testing.T) {
 		{"Basic line", "basic 10 PRINT HELLO", NewBasicLineCommand("10 PRINT HELLO")},
 		// New commands
 		{"Boot", "boot /path/to/game.xex", NewBootCommand("/path/to/game.xex")},
+		{"Boot info", "bootinfo", NewBootInfoCommand()},
 		{"Basic DEL", "basic DEL 10", NewBasicDeleteCommand("10")},
 		{"Basic DEL range", "basic DEL 10-50", NewBasicDeleteCommand("10-50")},
 		{"Basic STOP", "basic STOP", NewBasicStopCommand()},
 		{"Basic CONT", "basic CONT", NewBasicContCommand()},
 		{"Basic VARS", "basic VARS", NewBasicVarsCommand()},
 		{"Basic VAR", "basic VAR X", NewBasicVarCommand("X")},
+		{"Basic SET numeric", "basic SET X 42", NewBasicSetCommand("X", "42")},
+		{"Basic SET negative", "basic set COUNT -1.5", NewBasicSetCommand("COUNT", "-1.5")},
+		{"Basic SET string", `basic SET A$ "HI"`, NewBasicSetCommand("A$", "HI")},
+		{"Basic SET string escaped spaces", `basic SET A$ "HI\sTHERE"`, NewBasicSetCommand("A$", "HI THERE")},
+		{"Basic SET string spaces", `basic SET A$ "HI THERE"`, NewBasicSetCommand("A$", "HI THERE")},
 		{"Basic INFO", "basic INFO", NewBasicInfoCommand()},
+		{"Basic FRE", "basic FRE", NewBasicFreeCommand()},
+		{"Basic fre lowercase", "basic fre", NewBasicFreeCommand()},
+		{"Basic STEP", "basic STEP", NewBasicStepCommand()},
+		{"Basic REFS", "basic REFS 100", NewBasicRefsCommand(100)},
+		{"Basic FIND", "basic FIND PRINT", NewBasicFindCommand("PRINT")},
+		{"Basic TOKENS", "basic TOKENS", NewBasicTokensCommand()},
+		{"Basic tokens lowercase", "basic tokens", NewBasicTokensCommand()},
+		{"Basic FIND escaped spaces", `basic find GOTO\s100`, NewBasicFindCommand("GOTO 100")},
+		{"Basic FIND spaces", "basic FIND GOTO 100", NewBasicFindCommand("GOTO 100")},
+		{"Basic FIND trailing escaped space", `basic FIND A\s`, NewBasicFindCommand("A ")},
+		{"Basic refs lowercase", "basic refs 0", NewBasicRefsCommand(0)},
+		{"Basic step lowercase", "basic step", NewBasicStepCommand()},
+		{"Basic TRACE on", "basic TRACE on", NewBasicTraceCommand(true)},
+		{"Basic trace OFF", "basic trace OFF", NewBasicTraceCommand(false)},
 		{"Basic EXPORT", "basic EXPORT /path/to/file.bas", NewBasicExportCommand("/path/to/file.bas")},
 		{"Basic IMPORT", "basic IMPORT /path/to/file.bas", NewBasicImportCommand("/path/to/file.bas")},
 		{"Basic DIR", "basic DIR", NewBasicDirCommand(nil)},
@@ -341,6 +1318,7 @@ func TestCommandParsing(t *testing.T) {
 		{"DOS type", "dos type README.TXT", NewDosTypeCommand("README.TXT")},
 		{"DOS dump", "dos dump DATA.DAT", NewDosDumpCommand("DATA.DAT")},
 		{"DOS copy", "dos copy SOURCE DEST", NewDosCopyCommand("SOURCE", "DEST")},
+		{"DOS diff", "dos diff D1:GAME.BAS D2:GAME.BAS", NewDosDiffCommand("D1:GAME.BAS", "D2:GAME.BAS")},
 		{"DOS rename", "dos rename OLD NEW", NewDosRenameCommand("OLD", "NEW")},
 		{"DOS delete", "dos delete FILE.TMP", NewDosDeleteCommand("FILE.TMP")},
 		{"DOS del alias", "dos del FILE.TMP", NewDosDeleteCommand("FILE.TMP")},
@@ -354,6 +1332,9 @@ func TestCommandParsing(t *testing.T) {
 			return NewDosNewDiskCommand("/path/disk.atr", &dt)
 		}()},
 		{"DOS format", "dos format", NewDosFormatCommand()},
+		{"DOS check", "dos check", NewDosCheckCommand()},
+		{"DOS protect on", "dos protect on", NewDosProtectCommand(true)},
+		{"DOS protect OFF", "dos protect OFF", NewDosProtectCommand(false)},
 	}
 
 	for _, tt := range tests {
@@ -381,7 +1362,41 @@ func TestCommandParsingErrors(t *testing.T) {
 		{"Invalid address", "read invalid 16"},
 		{"Missing args", "read"},
 		{"Invalid step count", "step abc"},
+		{"Invalid stepi count", "stepi 0"},
+		{"Until missing target", "until"},
+		{"Inject codes missing", "inject codes"},
+		{"Inject codes invalid", "inject codes 7D,XYZ"},
+		{"Breakpoint hits zero", "breakpoint set $0600 hits 0"},
+		{"Breakpoint condition hits zero", "breakpoint set $0600 if A==$00 hits 0"},
+		{"Breakpoint hits invalid", "breakpoint set $0600 hits abc"},
+		{"Breakpoint hits missing count", "breakpoint set $0600 hits"},
+		{"Breakpoint set unknown option", "breakpoint set $0600 twice"},
+		{"Breakpoint set empty condition", "breakpoint set $0600 if"},
+		{"Breakpoint set condition only once", "breakpoint set $0600 if once"},
+		{"Breakpoint list unknown option", "breakpoint list everything"},
+		{"Log unknown subcommand", "log rotate"},
+		{"Frame step zero", "frame 0"},
+		{"Graphics mode too large", "gfx 16"},
+		{"Checksum missing end", "checksum $0600"},
+		{"Pattern missing bytes", "pattern $0600 $06FF"},
+		{"Pattern end before start", "pattern $06FF $0600 DE,AD"},
+		{"Pattern empty byte", "pattern $0600 $06FF DE,,AD"},
+		{"Pattern invalid byte", "pattern $0600 $06FF DE,XY"},
+		{"Trace missing state", "trace"},
+		{"Trace on missing path", "trace on"},
+		{"Trace off with path", "trace off /tmp/trace.log"},
+		{"Trace invalid state", "trace maybe"},
+		{"Checksum end before start", "checksum $7FFF $0600"},
+		{"Graphics mode negative", "gfx -1"},
+		{"Graphics mode invalid", "gfx text"},
+		{"Frame step invalid", "frame many"},
+		{"Breakpoint enable missing address", "breakpoint enable"},
+		{"Breakpoint disable bad address", "breakpoint disable $ZZZZ"},
 		{"Invalid reset type", "reset invalid"},
+		{"Audio missing state", "audio"},
+		{"Audio invalid state", "audio loud"},
+		{"Video invalid system", "video secam"},
+		{"Video extra argument", "video pal ntsc"},
 		{"Invalid drive", "mount 99 /path"},
 		{"Empty command", ""},
 		// DOS command errors
@@ -392,12 +1407,41 @@ func TestCommandParsingErrors(t *testing.T) {
 		{"DOS info no filename", "dos info"},
 		{"DOS copy no args", "dos copy"},
 		{"DOS copy one arg", "dos copy source"},
+		{"DOS diff no args", "dos diff"},
+		{"DOS diff one arg", "dos diff GAME.BAS"},
+		{"DOS diff three args", "dos diff A B C"},
+		{"DOS protect empty", "dos protect"},
+		{"DOS protect invalid", "dos protect maybe"},
 		{"DOS newdisk invalid type", "dos newdisk /path/disk.atr invalid"},
 		// BASIC save/load errors
 		{"Basic SAVE empty", "basic SAVE"},
 		{"Basic LOAD empty", "basic LOAD"},
+
+		// BASIC set errors
+		{"Basic SET empty", "basic SET"},
+		{"Basic SET missing value", "basic SET X"},
+		{"Basic SET bad name", "basic SET 9X 1"},
+		{"Basic SET numeric not a number", "basic SET X HI"},
+		{"Basic SET string unquoted", "basic SET A$ HI"},
+		{"Basic SET string unterminated", `basic SET A$ "HI`},
+
+		// BASIC find errors
+		{"Basic FIND empty", "basic FIND"},
+		{"Basic FIND blank", `basic FIND \s`},
+
+		// BASIC refs errors
+		{"Basic REFS empty", "basic REFS"},
+		{"Basic REFS not a number", "basic REFS ten"},
+		{"Basic REFS out of range", "basic REFS 40000"},
+
+		// BASIC trace errors
+		{"Basic TRACE empty", "basic TRACE"},
+		{"Basic TRACE invalid", "basic TRACE maybe"},
 		// Asm input error
 		{"Asm input no instruction", "asm input"},
+		// Quoting errors
+		{"Mount unterminated quote", "mount 1 \"/My Disks/game.atr"},
+		{"DOS export unterminated quote", "dos export GAME.BAS '/tmp/my game.bas"},
 	}
 
 	for _, tt := range tests {
@@ -410,6 +1454,119 @@ func TestCommandParsingErrors(t *testing.T) {
 	}
 }
 
+// TestQuotedPathParsing verifies quote-aware parsing of path arguments.
+func TestQuotedPathParsing(t *testing.T) {
+	parser := NewCommandParser()
+
+	tests := []struct {
+		name     string
+		input    string
+		expected Command
+	}{
+		{"Mount double-quoted", `mount 1 "/My Disks/game.atr"`, NewMountCommand(1, "/My Disks/game.atr")},
+		{"Mount single-quoted", `mount 2 '/My Disks/game.atr'`, NewMountCommand(2, "/My Disks/game.atr")},
+		{"Mount unquoted", "mount 1 /tmp/game.atr", NewMountCommand(1, "/tmp/game.atr")},
+		{"Boot escaped space", `boot /My\ Disks/game.atr`, NewBootCommand("/My Disks/game.atr")},
+		{"State save quoted", `state save "/tmp/my state.attic"`, NewStateSaveCommand("/tmp/my state.attic")},
+		{"State load quoted", `state load '/tmp/my state.attic'`, NewStateLoadCommand("/tmp/my state.attic")},
+		{"Screenshot quoted", `screenshot "/tmp/my shot.png"`, NewScreenshotCommand("/tmp/my shot.png")},
+		{"DOS export quoted", `dos export GAME.BAS "/tmp/my game.bas"`, NewDosExportCommand("GAME.BAS", "/tmp/my game.bas")},
+		{"DOS import quoted", `dos import '/tmp/my game.bas' GAME.BAS`, NewDosImportCommand("/tmp/my game.bas", "GAME.BAS")},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parser.Parse(tt.input)
+			if err != nil {
+				t.Fatalf("Parse(%q) error = %v", tt.input, err)
+			}
+			if got.Format() != tt.expected.Format() {
+				t.Errorf("Parse(%q) = %q, want %q", tt.input, got.Format(), tt.expected.Format())
+			}
+		})
+	}
+}
+
+// TestPathWireFormat verifies the exact line sent for host paths parsed
+// from quoted or escaped input. The server takes a path literally apart
+// from removing backslash escapes, so quotes are not sent and only
+// backslashes are escaped.
+func TestPathWireFormat(t *testing.T) {
+	parser := NewCommandParser()
+
+	tests := []struct {
+		input string
+		wire  string
+	}{
+		{`mount 1 "/My Disks/game.atr"`, `mount 1 /My Disks/game.atr`},
+		{`mount 1 /tmp/game.atr`, `mount 1 /tmp/game.atr`},
+		{`boot /My\ Disks/game.atr`, `boot /My Disks/game.atr`},
+		{`state save "/tmp/my state.attic"`, `state save /tmp/my state.attic`},
+		{`state load "/tmp/it's.attic"`, `state load /tmp/it's.attic`},
+		{`screenshot "/tmp/say \"hi\".png"`, `screenshot /tmp/say "hi".png`},
+		{`trace on "/tmp/my trace.log"`, `trace on /tmp/my trace.log`},
+		{`basic EXPORT "/tmp/my prog.bas"`, `basic EXPORT /tmp/my prog.bas`},
+		{`basic IMPORT '/tmp/back\slash.bas'`, `basic IMPORT /tmp/back\\slash.bas`},
+		{`dos export GAME.BAS "/tmp/my game.bas"`, `dos export GAME.BAS /tmp/my game.bas`},
+		{`dos newdisk "/tmp/new disk.atr" ed`, `dos newdisk /tmp/new disk.atr ed`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			cmd, err := parser.Parse(tt.input)
+			if err != nil {
+				t.Fatalf("Parse(%q) error = %v", tt.input, err)
+			}
+			if got := cmd.Format(); got != tt.wire {
+				t.Errorf("Format() = %q, want %q", got, tt.wire)
+			}
+		})
+	}
+}
+
+// TestSplitArgs verifies quote-aware argument splitting.
+func TestSplitArgs(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected []string
+		wantErr  bool
+	}{
+		{"a b  c", []string{"a", "b", "c"}, false},
+		{`"a b" c`, []string{"a b", "c"}, false},
+		{`'a "b"' c`, []string{`a "b"`, "c"}, false},
+		{`a\ b c`, []string{"a b", "c"}, false},
+		{`"say \"hi\""`, []string{`say "hi"`}, false},
+		{`""`, []string{""}, false},
+		{"", nil, false},
+		{`"unterminated`, nil, true},
+		{`'unterminated`, nil, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			got, err := splitArgs(tt.input)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("splitArgs(%q) error = %v, wantErr %v", tt.input, err, tt.wantErr)
+			}
+			if tt.wantErr {
+				var perr *ParseError
+				if !errors.As(err, &perr) || perr.Kind != ErrKindUnterminatedQuote {
+					t.Errorf("splitArgs(%q) error = %v, want unterminated quote", tt.input, err)
+				}
+				return
+			}
+			if len(got) != len(tt.expected) {
+				t.Fatalf("splitArgs(%q) = %q, want %q", tt.input, got, tt.expected)
+			}
+			for i := range got {
+				if got[i] != tt.expected[i] {
+					t.Errorf("splitArgs(%q) = %q, want %q", tt.input, got, tt.expected)
+				}
+			}
+		})
+	}
+}
+
 // TestResponseParsing verifies response parsing works correctly.
 func TestResponseParsing(t *testing.T) {
 	parser := NewResponseParser()
@@ -426,7 +1583,10 @@ func TestResponseParsing(t *testing.T) {
 		{"Error response", "ERR:command failed", false,
 			func(r Response) bool { return r.IsError() && r.Data == "command failed" }, nil},
 		{"Breakpoint event", "EVENT:breakpoint $0600 A=$A9 X=$10 Y=$20 S=$FF P=$30", true,
-			nil, func(e Event) bool { return e.Type == EventBreakpoint && e.Address == 0x0600 }},
+			nil, func(e Event) bool {
+				return e.Type == EventBreakpoint && e.Address == 0x0600 &&
+					e.A == 0xA9 && e.X == 0x10 && e.Y == 0x20 && e.S == 0xFF && e.P == 0x30
+			}},
 		{"Stopped event", "EVENT:stopped $0600", true,
 			nil, func(e Event) bool { return e.Type == EventStopped && e.Address == 0x0600 }},
 		{"Error event", "EVENT:error something went wrong", true,
@@ -465,6 +1625,11 @@ func TestResponseParsingErrors(t *testing.T) {
 	}{
 		{"No prefix", "invalid line"},
 		{"Unknown event", "EVENT:unknown data"},
+		{"Empty event", "EVENT:"},
+		{"Breakpoint without address", "EVENT:breakpoint"},
+		{"Breakpoint with bad address", "EVENT:breakpoint A=$A9"},
+		{"Stopped without address", "EVENT:stopped"},
+		{"Stopped with short address", "EVENT:stopped $60"},
 	}
 
 	for _, tt := range tests {
@@ -477,6 +1642,39 @@ func TestResponseParsingErrors(t *testing.T) {
 	}
 }
 
+// FuzzResponseParse feeds arbitrary lines to the response parser, which
+// must either parse them or return an error, never panic.
+func FuzzResponseParse(f *testing.F) {
+	seeds := []string{
+		"OK:pong",
+		"OK:",
+		"ERR:command failed",
+		"EVENT:breakpoint $0600 A=$A9 X=$10 Y=$20 S=$FF P=$30",
+		"EVENT:breakpoint $0600",
+		"EVENT:breakpoint",
+		"EVENT:stopped $0600",
+		"EVENT:stopped",
+		"EVENT:error something went wrong",
+		"EVENT:error",
+		"EVENT:",
+		"",
+	}
+	for _, seed := range seeds {
+		f.Add(seed)
+	}
+
+	parser := NewResponseParser()
+	f.Fuzz(func(t *testing.T, line string) {
+		parsed, err := parser.Parse(line)
+		if err != nil {
+			return
+		}
+		if parsed.IsEvent && parsed.Event.Type == EventBreakpoint && !strings.Contains(line, "$") {
+			t.Errorf("breakpoint event parsed without an address: %q", line)
+		}
+	})
+}
+
 // TestEscapeSequences verifies escape sequence handling.
 func TestEscapeSequences(t *testing.T) {
 	// Test that InjectKeys properly escapes special characters including space
@@ -511,3 +1709,1418 @@ func TestParseEscapes(t *testing.T) {
 		})
 	}
 }
+
+// fakeDropConnection is returned by a serveFake handler to close the
+// connection without replying, simulating a transient transport failure.
+const fakeDropConnection = "<drop>"
+
+// serveFake accepts connections on ln and answers each command line (with
+// the CMD: prefix removed) using handler, which returns the full response
+// line. Pings are always answered with OK:pong. It returns when ln is closed.
+func serveFake(ln net.Listener, handler func(cmd string) string) {
+	for {
+		conn, err := ln.Accept()
+		if err != nil {
+			return
+		}
+		go func(conn net.Conn) {
+			defer conn.Close()
+			scanner := bufio.NewScanner(conn)
+			for scanner.Scan() {
+				cmd := strings.TrimPrefix(scanner.Text(), CommandPrefix)
+				switch {
+				case cmd == "ping":
+					conn.Write([]byte("OK:pong\n"))
+				case handler != nil:
+					reply := handler(cmd)
+					if reply == fakeDropConnection {
+						return
+					}
+					conn.Write([]byte(reply + "\n"))
+				}
+			}
+		}(conn)
+	}
+}
+
+// TestClientClone verifies that clones have independent connections: they
+// ping concurrently, keep their own events, and survive each other's
+// disconnect.
+func TestClientClone(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "clone.sock")
+	ln, err := net.Listen("unix", path)
+	if err != nil {
+		t.Fatalf("failed to listen: %v", err)
+	}
+	defer ln.Close()
+	go serveFake(ln, func(cmd string) string {
+		return "EVENT:stopped $0600\nOK:resumed"
+	})
+
+	client := NewClient()
+	client.SetCommandTimeout(CmdPing, 3*time.Second)
+	if err := client.Connect(path); err != nil {
+		t.Fatalf("Connect() error = %v", err)
+	}
+	defer client.Disconnect()
+
+	first, err := client.Clone()
+	if err != nil {
+		t.Fatalf("Clone() error = %v", err)
+	}
+	defer first.Disconnect()
+	second, err := client.Clone()
+	if err != nil {
+		t.Fatalf("Clone() error = %v", err)
+	}
+	defer second.Disconnect()
+	for i, clone := range []*Client{first, second} {
+		if !clone.IsConnected() || clone.ConnectedPath() != path {
+			t.Fatalf("clone %d not connected to %s", i, path)
+		}
+		if got := clone.CommandTimeoutFor(CmdPing); got != 3*time.Second {
+			t.Errorf("clone %d ping timeout = %v, want 3s", i, got)
+		}
+	}
+
+	var wg sync.WaitGroup
+	errs := make(chan error, 20)
+	for _, clone := range []*Client{first, second} {
+		wg.Add(1)
+		go func(clone *Client) {
+			defer wg.Done()
+			for i := 0; i < 10; i++ {
+				if _, err := clone.Send(NewPingCommand()); err != nil {
+					errs <- err
+				}
+			}
+		}(clone)
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		t.Errorf("concurrent ping error = %v", err)
+	}
+
+	// An event on one connection is not seen by the other.
+	if _, err := first.Send(NewResumeCommand()); err != nil {
+		t.Fatalf("Send() error = %v", err)
+	}
+	if _, ok := first.TryReceiveEvent(); !ok {
+		t.Error("first clone should have buffered the stopped event")
+	}
+	if event, ok := second.TryReceiveEvent(); ok {
+		t.Errorf("second clone received %+v from the first clone's connection", event)
+	}
+
+	first.Disconnect()
+	if _, err := second.Send(NewPingCommand()); err != nil {
+		t.Errorf("second clone ping after first disconnected: %v", err)
+	}
+	if _, err := client.Send(NewPingCommand()); err != nil {
+		t.Errorf("original ping after clone disconnected: %v", err)
+	}
+}
+
+// TestClientCloneUnconnected verifies that cloning an unconnected client
+// gives an unconnected client.
+func TestClientCloneUnconnected(t *testing.T) {
+	clone, err := NewClient().Clone()
+	if err != nil {
+		t.Fatalf("Clone() error = %v", err)
+	}
+	if clone.IsConnected() {
+		t.Error("clone of an unconnected client should not be connected")
+	}
+}
+
+// TestClientCloneConnectError verifies that Clone reports a failed second
+// connection instead of returning an unconnected client.
+func TestClientCloneConnectError(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "clone.sock")
+	ln, err := net.Listen("unix", path)
+	if err != nil {
+		t.Fatalf("failed to listen: %v", err)
+	}
+	go serveFake(ln, func(cmd string) string { return "OK:pong" })
+
+	client := NewClient()
+	if err := client.Connect(path); err != nil {
+		t.Fatalf("Connect() error = %v", err)
+	}
+	defer client.Disconnect()
+
+	ln.Close()
+	clone, err := client.Clone()
+	if err == nil {
+		clone.Disconnect()
+		t.Fatal("Clone() should fail once the server stops accepting connections")
+	}
+	if clone != nil {
+		t.Errorf("Clone() = %v, want nil on error", clone)
+	}
+}
+
+// TestDiscoverAndConnectRetry verifies that DiscoverAndConnect keeps trying
+// until a server socket appears.
+func TestDiscoverAndConnectRetry(t *testing.T) {
+	// Discovery only accepts sockets whose PID is a running process, so the
+	// fake server uses this test process's PID.
+	path := CurrentSocketPath()
+	os.Remove(path)
+	t.Cleanup(func() { os.Remove(path) })
+
+	// Start the fake server after a short delay, as if it were booting.
+	listenerReady := make(chan net.Listener, 1)
+	go func() {
+		time.Sleep(150 * time.Millisecond)
+		ln, err := net.Listen("unix", path)
+		if err != nil {
+			listenerReady <- nil
+			return
+		}
+		listenerReady <- ln
+		serveFake(ln, nil)
+	}()
+
+	client := NewClient()
+	client.SetDiscoverRetry(10, 50*time.Millisecond)
+	err := client.DiscoverAndConnect()
+
+	ln := <-listenerReady
+	if ln == nil {
+		t.Fatal("failed to start fake server")
+	}
+	defer ln.Close()
+
+	if err != nil {
+		t.Fatalf("DiscoverAndConnect() error = %v, want nil after retry", err)
+	}
+	defer client.Disconnect()
+	if client.ConnectedPath() != path {
+		t.Errorf("ConnectedPath() = %q, want %q", client.ConnectedPath(), path)
+	}
+}
+
+// TestDiscoverSocketErr verifies that an unreadable socket directory is
+// reported, unlike an empty one.
+func TestDiscoverSocketErr(t *testing.T) {
+	missing := filepath.Join(t.TempDir(), "missing")
+	if _, err := discoverSocketErrIn(missing); err == nil || errors.Is(err, ErrSocketNotFound) {
+		t.Errorf("discoverSocketErrIn(missing dir) error = %v, want a directory error", err)
+	} else if !errors.Is(err, fs.ErrNotExist) || !strings.Contains(err.Error(), missing) {
+		t.Errorf("error %q should wrap fs.ErrNotExist and name the directory", err)
+	}
+
+	notDir := filepath.Join(t.TempDir(), "file")
+	if err := os.WriteFile(notDir, nil, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := discoverSocketErrIn(notDir); err == nil || errors.Is(err, ErrSocketNotFound) {
+		t.Errorf("discoverSocketErrIn(file) error = %v, want a directory error", err)
+	}
+
+	if _, err := discoverSocketErrIn(t.TempDir()); !errors.Is(err, ErrSocketNotFound) {
+		t.Errorf("discoverSocketErrIn(empty dir) error = %v, want ErrSocketNotFound", err)
+	}
+
+	// A socket named for a live process (this one) is found.
+	dir := t.TempDir()
+	path := filepath.Join(dir, fmt.Sprintf("attic-%d.sock", os.Getpid()))
+	if err := os.WriteFile(path, nil, 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if got, err := discoverSocketErrIn(dir); err != nil || got != path {
+		t.Errorf("discoverSocketErrIn(dir) = %q, %v; want %q", got, err, path)
+	}
+}
+
+// TestDiscoverAndConnectOnceNoServer verifies that a single attempt fails
+// immediately when no server socket exists.
+func TestDiscoverAndConnectOnceNoServer(t *testing.T) {
+	if DiscoverSocket() != "" {
+		t.Skip("an AtticServer socket is present")
+	}
+
+	start := time.Now()
+	err := NewClient().DiscoverAndConnectOnce()
+	if !errors.Is(err, ErrSocketNotFound) {
+		t.Errorf("DiscoverAndConnectOnce() error = %v, want ErrSocketNotFound", err)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("DiscoverAndConnectOnce() took %v, expected no retry delay", elapsed)
+	}
+}
+
+// TestConnectTCP verifies connecting to a server over TCP.
+func TestConnectTCP(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Skipf("TCP listen not available: %v", err)
+	}
+	defer ln.Close()
+
+	go serveFake(ln, nil)
+
+	client := NewClient()
+	if err := client.ConnectTCP(ln.Addr().String()); err != nil {
+		t.Fatalf("ConnectTCP() error = %v", err)
+	}
+	defer client.Disconnect()
+
+	if !client.IsConnected() {
+		t.Error("IsConnected() = false after ConnectTCP")
+	}
+	if client.ConnectedPath() != ln.Addr().String() {
+		t.Errorf("ConnectedPath() = %q, want %q", client.ConnectedPath(), ln.Addr().String())
+	}
+}
+
+// TestServerVersionInfoIsCompatible tests the server version compatibility range.
+func TestServerVersionInfoIsCompatible(t *testing.T) {
+	tests := []struct {
+		version string
+		want    bool
+	}{
+		{"1.0", true},
+		{"version 1.0", true},
+		{"1.7", true},
+		{"1", true},
+		{"Attic v1.2.0-beta+42 (Go)", true},
+		{"0.9", false},
+		{"2.0", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.version, func(t *testing.T) {
+			info, err := ParseVersion(tt.version)
+			if err != nil {
+				t.Fatalf("ParseVersion(%q) error = %v", tt.version, err)
+			}
+			if got := info.IsCompatible(); got != tt.want {
+				t.Errorf("ParseVersion(%q).IsCompatible() = %v, want %v", tt.version, got, tt.want)
+			}
+		})
+	}
+}
+
+// TestServerVersionMismatch verifies that an incompatible server version is
+// reported as a *VersionMismatchError and cached by ServerVersion.
+func TestServerVersionMismatch(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "attic-test.sock")
+	ln, err := net.Listen("unix", path)
+	if err != nil {
+		t.Fatalf("failed to listen: %v", err)
+	}
+	defer ln.Close()
+
+	var versionQueries atomic.Int32
+	go serveFake(ln, func(cmd string) string {
+		if cmd == "version" {
+			versionQueries.Add(1)
+			return "OK:version 2.3"
+		}
+		return "OK:"
+	})
+
+	client := NewClient()
+	if err := client.Connect(path); err != nil {
+		t.Fatalf("Connect() error = %v", err)
+	}
+	defer client.Disconnect()
+
+	err = client.CheckServerVersion()
+	var mismatch *VersionMismatchError
+	if !errors.As(err, &mismatch) {
+		t.Fatalf("CheckServerVersion() error = %v, want *VersionMismatchError", err)
+	}
+	if mismatch.ServerVersion != "2.3" || mismatch.ClientVersion != ProtocolVersion {
+		t.Errorf("mismatch = %+v", mismatch)
+	}
+
+	if v := client.ServerVersion(); v != "2.3" {
+		t.Errorf("ServerVersion() = %q, want %q", v, "2.3")
+	}
+	if n := versionQueries.Load(); n != 1 {
+		t.Errorf("version queried %d times, want 1 (cached)", n)
+	}
+}
+
+// TestAllCommands verifies that core commands are listed with their aliases.
+func TestAllCommands(t *testing.T) {
+	want := map[string][]string{
+		"ping":        nil,
+		"read":        nil,
+		"breakpoint":  nil,
+		"basic":       nil,
+		"dos":         nil,
+		"disassemble": {"disasm", "d"},
+		"stepover":    {"so"},
+	}
+
+	found := make(map[string]CommandInfo)
+	for _, info := range AllCommands() {
+		if info.Summary == "" {
+			t.Errorf("command %q has no summary", info.Name)
+		}
+		found[info.Name] = info
+	}
+
+	for name, aliases := range want {
+		info, ok := found[name]
+		if !ok {
+			t.Errorf("AllCommands() missing %q", name)
+			continue
+		}
+		if strings.Join(info.Aliases, ",") != strings.Join(aliases, ",") {
+			t.Errorf("%s aliases = %v, want %v", name, info.Aliases, aliases)
+		}
+	}
+}
+
+// TestLookupCommand tests keyword and alias lookup.
+func TestLookupCommand(t *testing.T) {
+	tests := []struct {
+		keyword string
+		want    string
+		ok      bool
+	}{
+		{"ping", "ping", true},
+		{"D", "disassemble", true},
+		{"sr", "stepout", true},
+		{"rununtil", "until", true},
+		{"bogus", "", false},
+	}
+
+	for _, tt := range tests {
+		info, ok := LookupCommand(tt.keyword)
+		if ok != tt.ok || info.Name != tt.want {
+			t.Errorf("LookupCommand(%q) = %q, %v; want %q, %v", tt.keyword, info.Name, ok, tt.want, tt.ok)
+		}
+	}
+}
+
+// TestParserHandlesAllCommands verifies that every catalog keyword and
+// alias is recognized by the parser (it may still reject missing args).
+func TestParserHandlesAllCommands(t *testing.T) {
+	parser := NewCommandParser()
+	for _, info := range AllCommands() {
+		for _, keyword := range append([]string{info.Name}, info.Aliases...) {
+			_, err := parser.Parse(keyword)
+			var perr *ParseError
+			if errors.As(err, &perr) && perr.Kind == ErrKindInvalidCommand && perr.Value == keyword {
+				t.Errorf("parser does not recognize %q", keyword)
+			}
+		}
+	}
+}
+
+// TestSendLines verifies stop-on-error and continue-on-error sequencing.
+func TestSendLines(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "attic-test.sock")
+	ln, err := net.Listen("unix", path)
+	if err != nil {
+		t.Fatalf("failed to listen: %v", err)
+	}
+	defer ln.Close()
+
+	var mu sync.Mutex
+	var received []string
+	go serveFake(ln, func(cmd string) string {
+		mu.Lock()
+		received = append(received, cmd)
+		mu.Unlock()
+		if cmd == "bad" {
+			return "ERR:Unknown command 'bad'"
+		}
+		return "OK:" + cmd
+	})
+
+	client := NewClient()
+	if err := client.Connect(path); err != nil {
+		t.Fatalf("Connect() error = %v", err)
+	}
+	defer client.Disconnect()
+
+	lines := []string{"one", "bad", "three"}
+
+	responses, err := client.SendLines(lines, false)
+	var perr *ProtocolError
+	if !errors.As(err, &perr) {
+		t.Fatalf("SendLines() error = %v, want *ProtocolError", err)
+	}
+	if len(responses) != 2 || !responses[1].IsError() {
+		t.Errorf("stop-on-error responses = %+v, want 2 ending in an error", responses)
+	}
+
+	responses, err = client.SendLines(lines, true)
+	if err != nil {
+		t.Fatalf("SendLines() with continue error = %v", err)
+	}
+	if len(responses) != 3 || responses[2].Data != "three" {
+		t.Errorf("continue-on-error responses = %+v, want all 3", responses)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if got := strings.Join(received, ","); got != "one,bad,one,bad,three" {
+		t.Errorf("server received %q", got)
+	}
+}
+
+// TestDisconnectDuringSend verifies that Disconnect lets an in-flight Send
+// finish within the grace period, and otherwise fails it with
+// ErrDisconnected instead of leaving it hanging.
+func TestDisconnectDuringSend(t *testing.T) {
+	release := make(chan struct{})
+	defer close(release)
+
+	path := filepath.Join(t.TempDir(), "attic-test.sock")
+	ln, err := net.Listen("unix", path)
+	if err != nil {
+		t.Fatalf("failed to listen: %v", err)
+	}
+	defer ln.Close()
+	go serveFake(ln, func(cmd string) string {
+		switch cmd {
+		case "slow":
+			time.Sleep(DisconnectGracePeriod / 4)
+			return "OK:done"
+		case "hang":
+			<-release
+		}
+		return "OK:"
+	})
+
+	tests := []struct {
+		cmd      string
+		wantErr  error
+		wantData string
+	}{
+		{"slow", nil, "done"},
+		{"hang", ErrDisconnected, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.cmd, func(t *testing.T) {
+			client := NewClient()
+			if err := client.Connect(path); err != nil {
+				t.Fatalf("Connect() error = %v", err)
+			}
+
+			type result struct {
+				resp Response
+				err  error
+			}
+			results := make(chan result, 1)
+			go func() {
+				resp, err := client.SendRaw(tt.cmd)
+				results <- result{resp, err}
+			}()
+
+			time.Sleep(20 * time.Millisecond) // let the command go out
+			client.Disconnect()
+
+			select {
+			case r := <-results:
+				if !errors.Is(r.err, tt.wantErr) {
+					t.Errorf("SendRaw() error = %v, want %v", r.err, tt.wantErr)
+				}
+				if r.resp.Data != tt.wantData {
+					t.Errorf("SendRaw() data = %q, want %q", r.resp.Data, tt.wantData)
+				}
+			case <-time.After(2 * time.Second):
+				t.Fatal("SendRaw() still blocked after Disconnect")
+			}
+		})
+	}
+}
+
+// TestClientSeparator verifies that responses split on a configured or
+// negotiated separator, and that a negotiated one ends with the connection.
+func TestClientSeparator(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "attic-test.sock")
+	ln, err := net.Listen("unix", path)
+	if err != nil {
+		t.Fatalf("failed to listen: %v", err)
+	}
+	defer ln.Close()
+	var advertise atomic.Bool
+	go serveFake(ln, func(cmd string) string {
+		switch cmd {
+		case "capabilities":
+			if advertise.Load() {
+				return "OK:capabilities trace,separator=1F"
+			}
+			return "OK:capabilities trace"
+		case "disassemble":
+			return "OK:$0600  A9 00     LDA #$00\x1F$0602  60        RTS"
+		}
+		return "ERR:unexpected"
+	})
+
+	client := NewClient()
+	if err := client.Connect(path); err != nil {
+		t.Fatalf("Connect() error = %v", err)
+	}
+	defer client.Disconnect()
+
+	lines := func() []string {
+		t.Helper()
+		resp, err := client.SendRaw("disassemble")
+		if err != nil {
+			t.Fatalf("SendRaw() error = %v", err)
+		}
+		return resp.Lines()
+	}
+
+	if got := lines(); len(got) != 1 {
+		t.Errorf("default separator: Lines() = %q, want one line", got)
+	}
+
+	client.SetSeparator("\x1F")
+	if got := lines(); len(got) != 2 || got[1] != "$0602  60        RTS" {
+		t.Errorf("configured separator: Lines() = %q, want two lines", got)
+	}
+	client.SetSeparator("")
+	if got := client.NegotiateSeparator(); got != MultiLineSeparator {
+		t.Errorf("NegotiateSeparator() without a setting = %q, want default", got)
+	}
+
+	// Reconnect so the capabilities are queried again.
+	advertise.Store(true)
+	client.Disconnect()
+	if err := client.Connect(path); err != nil {
+		t.Fatalf("Connect() error = %v", err)
+	}
+	if got := client.NegotiateSeparator(); got != "\x1F" {
+		t.Errorf("NegotiateSeparator() = %q, want \\x1F", got)
+	}
+	if !client.Supports("trace") {
+		t.Error("Supports(trace) = false after negotiating")
+	}
+	if got := lines(); len(got) != 2 {
+		t.Errorf("negotiated separator: Lines() = %q, want two lines", got)
+	}
+
+	client.Disconnect()
+	if got := client.Separator(); got != MultiLineSeparator {
+		t.Errorf("Separator() after Disconnect = %q, want default", got)
+	}
+}
+
+// TestClientSupports verifies capability lookup against new and old servers.
+func TestClientSupports(t *testing.T) {
+	tests := []struct {
+		name     string
+		response string
+		want     map[string]bool
+	}{
+		{"new server", "OK:capabilities watchpoints,trace", map[string]bool{"watchpoints": true, "TRACE": true, "audio": false}},
+		{"old server", "ERR:Unknown command 'capabilities'", map[string]bool{"watchpoints": false}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "attic-test.sock")
+			ln, err := net.Listen("unix", path)
+			if err != nil {
+				t.Fatalf("failed to listen: %v", err)
+			}
+			defer ln.Close()
+
+			var queries atomic.Int32
+			go serveFake(ln, func(cmd string) string {
+				if cmd == "capabilities" {
+					queries.Add(1)
+					return tt.response
+				}
+				return "OK:"
+			})
+
+			client := NewClient()
+			if err := client.Connect(path); err != nil {
+				t.Fatalf("Connect() error = %v", err)
+			}
+			defer client.Disconnect()
+
+			for feature, want := range tt.want {
+				if got := client.Supports(feature); got != want {
+					t.Errorf("Supports(%q) = %v, want %v", feature, got, want)
+				}
+			}
+			if n := queries.Load(); n != 1 {
+				t.Errorf("capabilities queried %d times, want 1 (cached)", n)
+			}
+		})
+	}
+}
+
+// TestSendWithRetry verifies that an idempotent read is retried over a new
+// connection after a transient failure, while a write is sent only once.
+func TestSendWithRetry(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "attic-test.sock")
+	ln, err := net.Listen("unix", path)
+	if err != nil {
+		t.Fatalf("failed to listen: %v", err)
+	}
+	defer ln.Close()
+
+	var mu sync.Mutex
+	seen := map[string]int{}
+	go serveFake(ln, func(cmd string) string {
+		mu.Lock()
+		defer mu.Unlock()
+		seen[cmd]++
+		if seen[cmd] == 1 {
+			return fakeDropConnection
+		}
+		return "OK:data 00"
+	})
+
+	client := NewClient()
+	if err := client.Connect(path); err != nil {
+		t.Fatalf("Connect() error = %v", err)
+	}
+	defer client.Disconnect()
+
+	read := NewReadCommand(0x0600, 1)
+	resp, err := client.SendWithRetry(read, 3)
+	if err != nil {
+		t.Fatalf("SendWithRetry(read) error = %v", err)
+	}
+	if resp.Data != "data 00" {
+		t.Errorf("SendWithRetry(read) data = %q, want %q", resp.Data, "data 00")
+	}
+	if !client.IsConnected() {
+		t.Error("client not reconnected after retry")
+	}
+
+	write := NewWriteCommand(0x0600, []byte{0xA9})
+	if _, err := client.SendWithRetry(write, 3); err == nil {
+		t.Error("SendWithRetry(write) succeeded, want the transport error")
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if got := seen[read.Format()]; got != 2 {
+		t.Errorf("server saw read %d times, want 2", got)
+	}
+	if got := seen[write.Format()]; got != 1 {
+		t.Errorf("server saw write %d times, want 1", got)
+	}
+}
+
+// TestSendWithRetryAfterDrop verifies that a retry after the server drops
+// the connection waits for the old reader, so a later drop of the new
+// connection is handled normally rather than closing a channel twice.
+func TestSendWithRetryAfterDrop(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "attic-test.sock")
+	ln, err := net.Listen("unix", path)
+	if err != nil {
+		t.Fatalf("failed to listen: %v", err)
+	}
+	defer ln.Close()
+
+	var mu sync.Mutex
+	seen := map[string]int{}
+	go serveFake(ln, func(cmd string) string {
+		mu.Lock()
+		defer mu.Unlock()
+		seen[cmd]++
+		if cmd == "status" || seen[cmd] == 1 {
+			return fakeDropConnection
+		}
+		return "OK:data 00"
+	})
+
+	client := NewClient()
+	var drops atomic.Int32
+	client.SetDisconnectHandler(func(err error) {
+		// A slow handler keeps the old reader running while the retry
+		// reconnects.
+		time.Sleep(200 * time.Millisecond)
+		drops.Add(1)
+	})
+	if err := client.Connect(path); err != nil {
+		t.Fatalf("Connect() error = %v", err)
+	}
+	defer client.Disconnect()
+
+	if _, err := client.SendWithRetry(NewReadCommand(0x0600, 1), 3); err != nil {
+		t.Fatalf("SendWithRetry(read) error = %v", err)
+	}
+	if n := drops.Load(); n != 1 {
+		t.Errorf("disconnect handler ran %d times before the retry finished, want 1", n)
+	}
+
+	if _, err := client.Send(NewStatusCommand()); err == nil {
+		t.Fatal("Send(status) succeeded, want the dropped connection's error")
+	}
+	deadline := time.Now().Add(2 * time.Second)
+	for drops.Load() < 2 && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+	if n := drops.Load(); n != 2 {
+		t.Errorf("disconnect handler ran %d times, want 2", n)
+	}
+}
+
+// TestSendTimed verifies that SendTimed returns the response and a
+// nonzero round-trip time.
+func TestSendTimed(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "attic-test.sock")
+	ln, err := net.Listen("unix", path)
+	if err != nil {
+		t.Fatalf("failed to listen: %v", err)
+	}
+	defer ln.Close()
+	go serveFake(ln, nil)
+
+	client := NewClient()
+	if err := client.Connect(path); err != nil {
+		t.Fatalf("Connect() error = %v", err)
+	}
+	defer client.Disconnect()
+
+	resp, elapsed, err := client.SendTimed(NewPingCommand())
+	if err != nil {
+		t.Fatalf("SendTimed() error = %v", err)
+	}
+	if resp.Data != "pong" {
+		t.Errorf("SendTimed() data = %q, want %q", resp.Data, "pong")
+	}
+	if elapsed <= 0 {
+		t.Errorf("SendTimed() elapsed = %v, want > 0", elapsed)
+	}
+}
+
+// shortWriteConn accepts at most max bytes per Write, as a connection
+// under pressure may.
+type shortWriteConn struct {
+	net.Conn
+	max    int
+	writes int
+}
+
+func (c *shortWriteConn) Write(b []byte) (int, error) {
+	c.writes++
+	if len(b) > c.max {
+		b = b[:c.max]
+	}
+	return c.Conn.Write(b)
+}
+
+// TestSendPartialWrites verifies that a command line written in pieces
+// reaches the server whole.
+func TestSendPartialWrites(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "attic-test.sock")
+	ln, err := net.Listen("unix", path)
+	if err != nil {
+		t.Fatalf("failed to listen: %v", err)
+	}
+	defer ln.Close()
+	received := make(chan string, 1)
+	go serveFake(ln, func(cmd string) string {
+		received <- cmd
+		return "OK:written"
+	})
+
+	client := NewClient()
+	if err := client.Connect(path); err != nil {
+		t.Fatalf("Connect() error = %v", err)
+	}
+	defer client.Disconnect()
+
+	client.mu.Lock()
+	conn := &shortWriteConn{Conn: client.conn, max: 3}
+	client.conn = conn
+	client.mu.Unlock()
+
+	cmd := NewWriteCommand(0x0600, []byte{0xA9, 0x00, 0x8D, 0xC6, 0x02})
+	resp, err := client.Send(cmd)
+	if err != nil {
+		t.Fatalf("Send() error = %v", err)
+	}
+	if resp.Data != "written" {
+		t.Errorf("Send() data = %q, want %q", resp.Data, "written")
+	}
+	if got := <-received; got != cmd.Format() {
+		t.Errorf("server received %q, want %q", got, cmd.Format())
+	}
+	if conn.writes < 2 {
+		t.Errorf("line written in %d calls, want several", conn.writes)
+	}
+}
+
+// TestWriteFullNoProgress verifies that a writer that accepts nothing
+// fails instead of looping.
+func TestWriteFullNoProgress(t *testing.T) {
+	stuck := writerFunc(func(b []byte) (int, error) { return 0, nil })
+	if err := writeFull(stuck, []byte("CMD:ping\n")); !errors.Is(err, io.ErrShortWrite) {
+		t.Errorf("writeFull() error = %v, want io.ErrShortWrite", err)
+	}
+}
+
+// writerFunc adapts a function to io.Writer.
+type writerFunc func([]byte) (int, error)
+
+func (f writerFunc) Write(b []byte) (int, error) { return f(b) }
+
+// waitFor polls cond until it holds, failing the test after a few seconds.
+func waitFor(t *testing.T, cond func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(3 * time.Second)
+	for !cond() {
+		if time.Now().After(deadline) {
+			t.Fatal("timed out waiting for condition")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+// TestReconnectQueue verifies that a command sent while SendWithRetry is
+// reconnecting waits and is delivered on the new connection.
+func TestReconnectQueue(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "attic-test.sock")
+	ln, err := net.Listen("unix", path)
+	if err != nil {
+		t.Fatalf("failed to listen: %v", err)
+	}
+	defer ln.Close()
+
+	// The second connection holds its ping reply until release is closed,
+	// keeping the client in the middle of reconnecting.
+	pinged := make(chan struct{})
+	release := make(chan struct{})
+	var mu sync.Mutex
+	var received []string
+	go func() {
+		for n := 1; ; n++ {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			go func(n int, conn net.Conn) {
+				defer conn.Close()
+				scanner := bufio.NewScanner(conn)
+				for scanner.Scan() {
+					cmd := strings.TrimPrefix(scanner.Text(), CommandPrefix)
+					if cmd == "ping" {
+						if n == 2 {
+							close(pinged)
+							<-release
+						}
+						conn.Write([]byte("OK:pong\n"))
+						continue
+					}
+					if n == 1 {
+						return
+					}
+					mu.Lock()
+					received = append(received, cmd)
+					mu.Unlock()
+					conn.Write([]byte("OK:" + cmd + "\n"))
+				}
+			}(n, conn)
+		}
+	}()
+
+	client := NewClient()
+	client.SetReconnectQueue(4)
+	if err := client.Connect(path); err != nil {
+		t.Fatalf("Connect() error = %v", err)
+	}
+	defer client.Disconnect()
+
+	retryDone := make(chan error, 1)
+	go func() {
+		_, err := client.SendWithRetry(NewStatusCommand(), 2)
+		retryDone <- err
+	}()
+	<-pinged
+
+	queuedDone := make(chan error, 1)
+	go func() {
+		resp, err := client.Send(NewResumeCommand())
+		if err == nil && resp.Data != "resume" {
+			err = fmt.Errorf("response data %q, want %q", resp.Data, "resume")
+		}
+		queuedDone <- err
+	}()
+	waitFor(t, func() bool {
+		client.mu.Lock()
+		defer client.mu.Unlock()
+		return len(client.queue) == 1
+	})
+	close(release)
+
+	if err := <-queuedDone; err != nil {
+		t.Errorf("queued Send() error = %v", err)
+	}
+	if err := <-retryDone; err != nil {
+		t.Errorf("SendWithRetry() error = %v", err)
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	if want := []string{"resume", "status"}; strings.Join(received, ",") != strings.Join(want, ",") {
+		t.Errorf("server received %q after reconnecting, want %q", received, want)
+	}
+}
+
+// TestReconnectQueueDropped verifies the queue limit, that NoQueue commands
+// fail at once, and that queued commands fail with ErrQueuedThenFailed when
+// reconnecting fails.
+func TestReconnectQueueDropped(t *testing.T) {
+	client := NewClient()
+	client.SetReconnectQueue(1)
+	client.mu.Lock()
+	client.reconnecting = true
+	client.mu.Unlock()
+
+	done := make(chan error, 1)
+	go func() {
+		_, err := client.Send(NewStatusCommand())
+		done <- err
+	}()
+	waitFor(t, func() bool {
+		client.mu.Lock()
+		defer client.mu.Unlock()
+		return len(client.queue) == 1
+	})
+	if _, err := client.Send(NewStatusCommand()); !errors.Is(err, ErrQueueFull) {
+		t.Errorf("Send() past the limit error = %v, want ErrQueueFull", err)
+	}
+	noQueue := NewPauseCommand()
+	noQueue.NoQueue = true
+	if _, err := client.Send(noQueue); !errors.Is(err, ErrNotConnected) {
+		t.Errorf("Send(NoQueue) error = %v, want ErrNotConnected", err)
+	}
+
+	client.flushQueue(ErrSocketNotFound)
+	if err := <-done; !errors.Is(err, ErrQueuedThenFailed) {
+		t.Errorf("queued Send() error = %v, want ErrQueuedThenFailed", err)
+	}
+	if client.reconnecting {
+		t.Error("client still reconnecting after the queue was flushed")
+	}
+}
+
+// TestCommandIsIdempotent verifies which commands are safe to retry.
+func TestCommandIsIdempotent(t *testing.T) {
+	tests := []struct {
+		name string
+		cmd  Command
+		want bool
+	}{
+		{"ping", NewPingCommand(), true},
+		{"status", NewStatusCommand(), true},
+		{"read", NewReadCommand(0x0600, 16), true},
+		{"registers", NewRegistersCommand(nil), true},
+		{"breakpoint list", NewBreakpointListCommand(), true},
+		{"disassemble", NewDisassembleCommand(nil, nil), true},
+		{"log", NewServerLogCommand(), true},
+		{"checksum", NewMemoryChecksumCommand(0x0600, 0x06FF), true},
+		{"video read", NewVideoSystemCommand(""), true},
+		{"log clear", NewServerLogClearCommand(), false},
+		{"video set", NewVideoSystemCommand("pal"), false},
+		{"write", NewWriteCommand(0x0600, []byte{0x00}), false},
+		{"set registers", NewRegistersCommand([]RegisterModification{{Name: "A", Value: 1}}), false},
+		{"breakpoint set", NewBreakpointSetCommand(0x0600), false},
+		{"resume", NewResumeCommand(), false},
+		{"reset", NewResetCommand(true), false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.cmd.IsIdempotent(); got != tt.want {
+				t.Errorf("IsIdempotent() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+// TestCommandTimeouts verifies per-type timeouts: a command slower than its
+// type's timeout fails with ErrTimeout while a fast one succeeds.
+func TestCommandTimeouts(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "attic-test.sock")
+	ln, err := net.Listen("unix", path)
+	if err != nil {
+		t.Fatalf("failed to listen: %v", err)
+	}
+	defer ln.Close()
+	go serveFake(ln, func(cmd string) string {
+		if strings.HasPrefix(cmd, "screenshot") {
+			time.Sleep(200 * time.Millisecond)
+		}
+		return "OK:" + cmd
+	})
+
+	client := NewClient()
+	if err := client.Connect(path); err != nil {
+		t.Fatalf("Connect() error = %v", err)
+	}
+	defer client.Disconnect()
+
+	if got := client.CommandTimeoutFor(CmdScreenshot); got != LongCommandTimeout {
+		t.Errorf("default screenshot timeout = %v, want %v", got, LongCommandTimeout)
+	}
+	if got := client.CommandTimeoutFor(CmdRead); got != CommandTimeout {
+		t.Errorf("default read timeout = %v, want %v", got, CommandTimeout)
+	}
+
+	client.SetCommandTimeout(CmdStatus, 50*time.Millisecond)
+	client.SetCommandTimeout(CmdScreenshot, 50*time.Millisecond)
+
+	if _, err := client.Send(NewStatusCommand()); err != nil {
+		t.Errorf("Send(status) error = %v", err)
+	}
+	if _, err := client.Send(NewScreenshotCommand("/tmp/shot.png")); !errors.Is(err, ErrTimeout) {
+		t.Errorf("Send(screenshot) error = %v, want ErrTimeout", err)
+	}
+	if _, err := client.SendLines([]string{"status", "screenshot /tmp/shot.png"}, false); !errors.Is(err, ErrTimeout) {
+		t.Errorf("SendLines(screenshot) error = %v, want ErrTimeout", err)
+	}
+
+	client.SetCommandTimeout(CmdScreenshot, 0)
+	if got := client.CommandTimeoutFor(CmdScreenshot); got != LongCommandTimeout {
+		t.Errorf("screenshot timeout after reset = %v, want %v", got, LongCommandTimeout)
+	}
+}
+
+// TestConnectTwice verifies that connecting again to the same server is a
+// no-op while connecting to a different one is refused.
+func TestConnectTwice(t *testing.T) {
+	dir := t.TempDir()
+	var paths []string
+	for _, name := range []string{"first.sock", "second.sock"} {
+		path := filepath.Join(dir, name)
+		ln, err := net.Listen("unix", path)
+		if err != nil {
+			t.Fatalf("failed to listen: %v", err)
+		}
+		defer ln.Close()
+		go serveFake(ln, nil)
+		paths = append(paths, path)
+	}
+
+	client := NewClient()
+	if err := client.Connect(paths[0]); err != nil {
+		t.Fatalf("Connect() error = %v", err)
+	}
+	defer client.Disconnect()
+
+	if err := client.Connect(paths[0]); err != nil {
+		t.Errorf("Connect() to the same socket error = %v, want nil", err)
+	}
+	if _, err := client.Send(NewPingCommand()); err != nil {
+		t.Errorf("Send() after second Connect error = %v", err)
+	}
+
+	err := client.Connect(paths[1])
+	if !errors.Is(err, ErrAlreadyConnected) {
+		t.Fatalf("Connect() to another socket error = %v, want ErrAlreadyConnected", err)
+	}
+	if !strings.Contains(err.Error(), "Disconnect") {
+		t.Errorf("error %q should suggest Disconnect", err)
+	}
+	if got := client.ConnectedPath(); got != paths[0] {
+		t.Errorf("ConnectedPath() = %q, want %q", got, paths[0])
+	}
+
+	client.Disconnect()
+	if err := client.Connect(paths[1]); err != nil {
+		t.Errorf("Connect() after Disconnect error = %v", err)
+	}
+}
+
+// TestTryReceiveEvent verifies that events are buffered for polling when no
+// handler is set and that an empty buffer returns immediately.
+func TestTryReceiveEvent(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "events.sock")
+	ln, err := net.Listen("unix", path)
+	if err != nil {
+		t.Fatalf("failed to listen: %v", err)
+	}
+	defer ln.Close()
+	go serveFake(ln, func(cmd string) string {
+		return "EVENT:stopped $0600\nEVENT:breakpoint $0700 A=$01 X=$02 Y=$03 S=$FF P=$30\nOK:resumed"
+	})
+
+	client := NewClient()
+	if err := client.Connect(path); err != nil {
+		t.Fatalf("Connect() error = %v", err)
+	}
+	defer client.Disconnect()
+
+	if event, ok := client.TryReceiveEvent(); ok {
+		t.Fatalf("TryReceiveEvent() on empty buffer = %+v, true", event)
+	}
+
+	if _, err := client.Send(NewResumeCommand()); err != nil {
+		t.Fatalf("Send() error = %v", err)
+	}
+	first, ok := client.TryReceiveEvent()
+	if !ok || first.Type != EventStopped || first.Address != 0x0600 {
+		t.Errorf("first TryReceiveEvent() = %+v, %v", first, ok)
+	}
+	second, ok := client.TryReceiveEvent()
+	if !ok || second.Type != EventBreakpoint || second.Address != 0x0700 || second.X != 0x02 {
+		t.Errorf("second TryReceiveEvent() = %+v, %v", second, ok)
+	}
+	if _, ok := client.TryReceiveEvent(); ok {
+		t.Error("buffer should be empty after two events")
+	}
+
+	// With a handler set, events go to the handler instead of the buffer.
+	handled := make(chan Event, 2)
+	client.SetEventHandler(func(e Event) { handled <- e })
+	if _, err := client.Send(NewResumeCommand()); err != nil {
+		t.Fatalf("Send() error = %v", err)
+	}
+	if len(handled) != 2 {
+		t.Errorf("handler received %d events, want 2", len(handled))
+	}
+	if _, ok := client.TryReceiveEvent(); ok {
+		t.Error("events delivered to a handler should not be buffered")
+	}
+}
+
+// TestTryReceiveEventOverflow verifies that the oldest events are dropped
+// once the buffer is full.
+func TestTryReceiveEventOverflow(t *testing.T) {
+	client := NewClient()
+	for i := 0; i < EventBufferSize+3; i++ {
+		client.processLine(fmt.Sprintf("EVENT:stopped $%04X\n", i))
+	}
+	first, ok := client.TryReceiveEvent()
+	if !ok || first.Address != 3 {
+		t.Errorf("oldest buffered event = %+v, %v; want address $0003", first, ok)
+	}
+}
+
+// TestTraceWriter verifies that the trace shows requests, responses and the
+// events in between, in the order they crossed the socket.
+func TestTraceWriter(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "trace.sock")
+	ln, err := net.Listen("unix", path)
+	if err != nil {
+		t.Fatalf("failed to listen: %v", err)
+	}
+	defer ln.Close()
+	go serveFake(ln, func(cmd string) string {
+		return "EVENT:breakpoint $0600 A=$01 X=$02 Y=$03 S=$FF P=$30\nOK:resumed"
+	})
+
+	client := NewClient()
+	if err := client.Connect(path); err != nil {
+		t.Fatalf("Connect() error = %v", err)
+	}
+	defer client.Disconnect()
+
+	// The response arrives after the events before it have been traced, so
+	// the buffer is complete once Send returns.
+	var trace bytes.Buffer
+	client.SetTraceWriter(&trace)
+	if _, err := client.Send(NewResumeCommand()); err != nil {
+		t.Fatalf("Send() error = %v", err)
+	}
+	want := "> CMD:resume\n" +
+		"!! EVENT:breakpoint $0600 A=$01 X=$02 Y=$03 S=$FF P=$30\n" +
+		"< OK:resumed\n"
+	if got := trace.String(); got != want {
+		t.Errorf("trace = %q, want %q", got, want)
+	}
+
+	client.SetTraceWriter(nil)
+	if _, err := client.Send(NewPingCommand()); err != nil {
+		t.Fatalf("Send() error = %v", err)
+	}
+	if got := trace.String(); got != want {
+		t.Errorf("trace after SetTraceWriter(nil) = %q, want no new lines", got)
+	}
+}
+
+func TestATASCIIToUnicode(t *testing.T) {
+	tests := []struct {
+		b       byte
+		inverse bool
+		want    rune
+	}{
+		{0x00, false, '♥'},
+		{0x11, false, '┌'},
+		{0x12, false, '─'},
+		{0x15, false, '▄'},
+		{0x1C, false, '↑'},
+		{0x1F, false, '→'},
+		{0x41, false, 'A'},
+		{0x61, false, 'a'},
+		{0x60, false, '♦'},
+		{0x7B, false, '♠'},
+		{0x7F, false, '▶'},
+		{0x80, false, '♥'}, // bit 7 is ignored
+		{0xC1, false, 'A'},
+		{0x20, true, '█'},
+		{0x15, true, '▀'},
+		{0x19, true, '▐'},
+		{0x09, true, '▛'},
+		{0x08, true, '◤'},
+		{0x41, true, 'A'}, // no inverse glyph
+	}
+	for _, tt := range tests {
+		if got := ATASCIIToUnicode(tt.b, tt.inverse); got != tt.want {
+			t.Errorf("ATASCIIToUnicode($%02X, %v) = %q, want %q", tt.b, tt.inverse, got, tt.want)
+		}
+	}
+}
+
+func TestDecodeATASCII(t *testing.T) {
+	tests := []struct {
+		name string
+		data []byte
+		want string
+	}{
+		{"plain text", []byte("HELLO"), "HELLO"},
+		{"graphics", []byte{0x11, 0x12, 0x05}, "┌─┐"},
+		{"eol", []byte{'A', ATASCIIEOL, 'B'}, "A\nB"},
+		{"inverse", []byte{'G', 0xC1, 0x94}, "G\x1b[7mA\x1b[27m\x1b[7m•\x1b[27m"},
+		{"empty", nil, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := DecodeATASCII(tt.data); got != tt.want {
+				t.Errorf("DecodeATASCII(% X) = %q, want %q", tt.data, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestUnicodeToATASCII(t *testing.T) {
+	// Every character round-trips through ATASCIIToUnicode, in both normal
+	// and inverse video where an inverse glyph exists.
+	for b := 0; b < 0x80; b++ {
+		r := ATASCIIToUnicode(byte(b), false)
+		if got, ok := UnicodeToATASCII(r); !ok || got != byte(b) {
+			t.Errorf("UnicodeToATASCII(%q) = $%02X, %v; want $%02X", r, got, ok, b)
+		}
+		if inv := ATASCIIToUnicode(byte(b), true); inv != r {
+			if got, ok := UnicodeToATASCII(inv); !ok || got != byte(b)|0x80 {
+				t.Errorf("UnicodeToATASCII(%q) = $%02X, %v; want $%02X", inv, got, ok, b|0x80)
+			}
+		}
+	}
+
+	tests := []struct {
+		r    rune
+		want byte
+		ok   bool
+	}{
+		{'A', 0x41, true},
+		{'♥', 0x00, true},
+		{'─', 0x12, true},
+		{'█', 0xA0, true},
+		{'\n', ATASCIIEOL, true},
+		{'{', 0x7B, true},
+		{'\t', 0, false},
+		{'é', 0, false},
+		{'😀', 0, false},
+	}
+	for _, tt := range tests {
+		got, ok := UnicodeToATASCII(tt.r)
+		if got != tt.want || ok != tt.ok {
+			t.Errorf("UnicodeToATASCII(%q) = $%02X, %v; want $%02X, %v", tt.r, got, ok, tt.want, tt.ok)
+		}
+	}
+}
+
+func TestEncodeATASCII(t *testing.T) {
+	data := []byte{'1', '0', ' ', '?', '"', 0x11, 0x12, 0x05, 0xC8, 0x89, '"', ATASCIIEOL}
+	got, ok := EncodeATASCII(DecodeATASCII(data))
+	if !ok || !bytes.Equal(got, data) {
+		t.Errorf("EncodeATASCII(DecodeATASCII(% X)) = % X, %v", data, got, ok)
+	}
+
+	if _, ok := EncodeATASCII("café"); ok {
+		t.Error("EncodeATASCII should reject runes with no ATASCII equivalent")
+	}
+}
+
+// TestLargeResponseInChunks verifies that a multi-line response written in
+// several chunks, with pauses longer than the reader's poll interval and a
+// read buffer smaller than the line, arrives as one intact Response.
+func TestLargeResponseInChunks(t *testing.T) {
+	var lines []string
+	for i := 0; i < 2000; i++ {
+		lines = append(lines, fmt.Sprintf("$%04X  A9 00     LDA #$00", 0x0600+i*2))
+	}
+	payload := OKPrefix + strings.Join(lines, MultiLineSeparator) + "\n"
+
+	path := filepath.Join(t.TempDir(), "chunks.sock")
+	ln, err := net.Listen("unix", path)
+	if err != nil {
+		t.Fatalf("failed to listen: %v", err)
+	}
+	defer ln.Close()
+	go func() {
+		conn, err := ln.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		scanner := bufio.NewScanner(conn)
+		for scanner.Scan() {
+			if scanner.Text() == CommandPrefix+"ping" {
+				conn.Write([]byte("OK:pong\n"))
+				continue
+			}
+			const chunks = 4
+			size := len(payload)/chunks + 1
+			for start := 0; start < len(payload); start += size {
+				conn.Write([]byte(payload[start:min(start+size, len(payload))]))
+				time.Sleep(150 * time.Millisecond)
+			}
+		}
+	}()
+
+	client := NewClient()
+	client.SetReadBufferSize(4096)
+	if err := client.Connect(path); err != nil {
+		t.Fatalf("Connect() error = %v", err)
+	}
+	defer client.Disconnect()
+
+	resp, err := client.Send(NewDisassembleCommand(nil, nil))
+	if err != nil {
+		t.Fatalf("Send() error = %v", err)
+	}
+	got := resp.Lines()
+	if len(got) != len(lines) {
+		t.Fatalf("got %d lines, want %d", len(got), len(lines))
+	}
+	if got[0] != lines[0] || got[len(got)-1] != lines[len(lines)-1] {
+		t.Errorf("lines corrupted: first %q, last %q", got[0], got[len(got)-1])
+	}
+}
+
+func TestFormatHexDump(t *testing.T) {
+	data := []byte("HELLO, ATARI 800\x00\x9B\x7F~")
+	want := "0600: 48 45 4C 4C 4F 2C 20 41 54 41 52 49 20 38 30 30 |HELLO, ATARI 800|\n" +
+		"0610: 00 9B 7F 7E                                     |...~|\n"
+	if got := FormatHexDump(0x0600, data); got != want {
+		t.Errorf("FormatHexDump() =\n%s\nwant\n%s", got, want)
+	}
+
+	if got := FormatHexDump(0xFFF8, make([]byte, 20)); !strings.Contains(got, "\n0008: 00 00 ") {
+		t.Errorf("address should wrap past $FFFF, got:\n%s", got)
+	}
+	if got := FormatHexDump(0x0600, nil); got != "" {
+		t.Errorf("FormatHexDump(nil) = %q, want empty", got)
+	}
+}
+
+func TestFormatHexDumpATASCII(t *testing.T) {
+	want := "0600: 11 12 05 C1 60                                  |┌─┐A♦|\n"
+	if got := FormatHexDumpATASCII(0x0600, []byte{0x11, 0x12, 0x05, 0xC1, 0x60}); got != want {
+		t.Errorf("FormatHexDumpATASCII() = %q, want %q", got, want)
+	}
+}
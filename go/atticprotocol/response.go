@@ -2,6 +2,8 @@ package atticprotocol
 
 import (
 	"fmt"
+	"regexp"
+	"strconv"
 	"strings"
 )
 
@@ -19,6 +21,8 @@ const (
 type Response struct {
 	Type ResponseType
 	Data string // The response data (for OK) or error message (for Error)
+
+	separator string // Line separator in Data, "" for MultiLineSeparator
 }
 
 // NewOKResponse creates a successful response with the given data.
@@ -50,6 +54,15 @@ func (r Response) IsError() bool {
 	return r.Type == ResponseError
 }
 
+// Err returns a *ProtocolError for error responses, or nil for successful ones.
+// This lets callers treat server-side failures like any other Go error.
+func (r Response) Err() error {
+	if r.Type != ResponseError {
+		return nil
+	}
+	return NewProtocolError(r.Data)
+}
+
 // Format returns the response formatted for transmission over the protocol.
 func (r Response) Format() string {
 	switch r.Type {
@@ -62,13 +75,1010 @@ func (r Response) Format() string {
 	}
 }
 
+// Separator returns the string separating lines in a multi-line response:
+// MultiLineSeparator unless the client that received the response uses
+// another one (see Client.SetSeparator).
+func (r Response) Separator() string {
+	if r.separator == "" {
+		return MultiLineSeparator
+	}
+	return r.separator
+}
+
 // Lines returns the response data split by the multi-line separator.
 // Useful for processing multi-line responses like disassembly output.
 func (r Response) Lines() []string {
 	if r.Data == "" {
 		return nil
 	}
-	return strings.Split(r.Data, MultiLineSeparator)
+	return strings.Split(r.Data, r.Separator())
+}
+
+// Text returns the response data with lines separated by newlines, for
+// display.
+func (r Response) Text() string {
+	return strings.Join(r.Lines(), "\n")
+}
+
+// AsBytes parses the data of a memory read response ("data A9,00,60")
+// into a byte slice. Error responses are returned as a *ProtocolError.
+func (r Response) AsBytes() ([]byte, error) {
+	if err := r.Err(); err != nil {
+		return nil, err
+	}
+	if !strings.HasPrefix(r.Data, "data") {
+		return nil, newUnexpectedResponseError(r.Data)
+	}
+	hex := strings.TrimSpace(strings.TrimPrefix(r.Data, "data"))
+	if hex == "" {
+		return []byte{}, nil
+	}
+
+	parts := strings.Split(hex, ",")
+	data := make([]byte, 0, len(parts))
+	for _, part := range parts {
+		b, ok := parseHexByte(part)
+		if !ok {
+			return nil, newInvalidByteError(strings.TrimSpace(part))
+		}
+		data = append(data, b)
+	}
+	return data, nil
+}
+
+// AsCapabilities parses a "capabilities" response such as
+// "capabilities watchpoints,trace" into a set of feature names. Names are
+// lowercased and may be separated by commas or spaces. A setting such as
+// "separator=1f" is kept whole as a name.
+func (r Response) AsCapabilities() (map[string]bool, error) {
+	if err := r.Err(); err != nil {
+		return nil, err
+	}
+	if !strings.HasPrefix(r.Data, "capabilities") {
+		return nil, newUnexpectedResponseError(r.Data)
+	}
+
+	features := make(map[string]bool)
+	list := strings.TrimPrefix(r.Data, "capabilities")
+	for _, name := range strings.FieldsFunc(list, func(r rune) bool { return r == ',' || r == ' ' }) {
+		features[strings.ToLower(name)] = true
+	}
+	return features, nil
+}
+
+// ServerVersionInfo is the parsed form of a "version" response.
+type ServerVersionInfo struct {
+	Name                string // Text before the version, e.g. "Attic"
+	Version             string // The version as written, e.g. "1.3.0-beta.2"
+	Major, Minor, Patch int
+	PreRelease          string // Semver pre-release after "-", e.g. "beta.2"
+	Build               string // Semver build after "+" and/or a trailing label, e.g. "Go"
+}
+
+// versionPattern matches "1", "1.2", "v1.2.3" and "1.2.3-beta.1+42" inside
+// a longer string.
+var versionPattern = regexp.MustCompile(`\bv?((\d+)(?:\.(\d+)(?:\.(\d+))?)?(?:-([0-9A-Za-z.-]+))?(?:\+([0-9A-Za-z.-]+))?)`)
+
+// AsVersion parses a "version" response such as "Attic v0.2.0 (Go)",
+// "version 1.0" or "AtticServer 1.3.0-beta.2". See ParseVersion.
+func (r Response) AsVersion() (ServerVersionInfo, error) {
+	if err := r.Err(); err != nil {
+		return ServerVersionInfo{}, err
+	}
+	return ParseVersion(r.Data)
+}
+
+// ParseVersion parses a version string leniently. The first number is the
+// version; text before it, minus a leading "version" keyword, is the name.
+// A "-" suffix is the pre-release, and a "+" suffix and a parenthesized
+// label after the version make up the build. Missing minor and patch
+// numbers are 0.
+func ParseVersion(s string) (ServerVersionInfo, error) {
+	loc := versionPattern.FindStringSubmatchIndex(s)
+	if loc == nil {
+		return ServerVersionInfo{}, newUnexpectedResponseError(s)
+	}
+	group := func(i int) string {
+		if loc[2*i] < 0 {
+			return ""
+		}
+		return s[loc[2*i]:loc[2*i+1]]
+	}
+
+	info := ServerVersionInfo{Version: group(1), PreRelease: group(5), Build: group(6)}
+	var err error
+	if info.Major, err = strconv.Atoi(group(2)); err != nil {
+		return ServerVersionInfo{}, newInvalidValueError(group(2))
+	}
+	if minor := group(3); minor != "" {
+		if info.Minor, err = strconv.Atoi(minor); err != nil {
+			return ServerVersionInfo{}, newInvalidValueError(minor)
+		}
+	}
+	if patch := group(4); patch != "" {
+		if info.Patch, err = strconv.Atoi(patch); err != nil {
+			return ServerVersionInfo{}, newInvalidValueError(patch)
+		}
+	}
+
+	name := strings.TrimSpace(s[:loc[0]])
+	if strings.EqualFold(name, "version") {
+		name = ""
+	}
+	info.Name = name
+
+	if label := strings.TrimSpace(s[loc[1]:]); label != "" {
+		label = strings.TrimSuffix(strings.TrimPrefix(label, "("), ")")
+		if info.Build != "" {
+			info.Build += " "
+		}
+		info.Build += label
+	}
+	return info, nil
+}
+
+// IsCompatible reports whether the version is within the protocol range
+// this client supports: the same major version as ProtocolVersion and not
+// older than MinServerProtocolVersion.
+func (v ServerVersionInfo) IsCompatible() bool {
+	client, _ := ParseVersion(ProtocolVersion)
+	minimum, _ := ParseVersion(MinServerProtocolVersion)
+	if v.Major != client.Major {
+		return false
+	}
+	return v.Major > minimum.Major || (v.Major == minimum.Major && v.Minor >= minimum.Minor)
+}
+
+// AsServerLog parses a "log" response into the logged command lines,
+// oldest first. The response is a "log" header line followed by one
+// command per line, or "log (empty)".
+func (r Response) AsServerLog() ([]string, error) {
+	if err := r.Err(); err != nil {
+		return nil, err
+	}
+	lines := r.Lines()
+	if len(lines) == 0 {
+		return nil, newUnexpectedResponseError(r.Data)
+	}
+	if header := strings.TrimSpace(lines[0]); header != "log" && header != "log (empty)" {
+		return nil, newUnexpectedResponseError(r.Data)
+	}
+
+	var commands []string
+	for _, line := range lines[1:] {
+		if line = strings.TrimSpace(line); line != "" {
+			commands = append(commands, line)
+		}
+	}
+	return commands, nil
+}
+
+// AsGraphicsMode parses a "gfx" response such as "gfx 8" into the GRAPHICS
+// mode number.
+func (r Response) AsGraphicsMode() (int, error) {
+	if err := r.Err(); err != nil {
+		return 0, err
+	}
+	fields := strings.Fields(r.Data)
+	if len(fields) != 2 || fields[0] != "gfx" {
+		return 0, newUnexpectedResponseError(r.Data)
+	}
+	mode, err := strconv.Atoi(fields[1])
+	if err != nil || mode < 0 {
+		return 0, newInvalidValueError(fields[1])
+	}
+	return mode, nil
+}
+
+// AsCycles parses a "cycles" response such as "cycles 1234567" into the
+// number of CPU cycles since the last cold start.
+func (r Response) AsCycles() (uint64, error) {
+	if err := r.Err(); err != nil {
+		return 0, err
+	}
+	fields := strings.Fields(r.Data)
+	if len(fields) != 2 || fields[0] != "cycles" {
+		return 0, newUnexpectedResponseError(r.Data)
+	}
+	cycles, err := strconv.ParseUint(fields[1], 10, 64)
+	if err != nil {
+		return 0, newInvalidValueError(fields[1])
+	}
+	return cycles, nil
+}
+
+// AsBasicFree parses a "basic FRE" response such as "fre 37902" into the
+// number of bytes free for the BASIC program.
+func (r Response) AsBasicFree() (int, error) {
+	if err := r.Err(); err != nil {
+		return 0, err
+	}
+	fields := strings.Fields(r.Data)
+	if len(fields) != 2 || fields[0] != "fre" {
+		return 0, newUnexpectedResponseError(r.Data)
+	}
+	free, err := strconv.Atoi(fields[1])
+	if err != nil || free < 0 {
+		return 0, newInvalidValueError(fields[1])
+	}
+	return free, nil
+}
+
+// AsBasicStep parses a "basic STEP" response such as "step 30" into the
+// number of the line that runs next. ok is false for "step end", when the
+// program has finished, and for an error or unexpected response.
+func (r Response) AsBasicStep() (nextLine int, ok bool) {
+	if !r.IsOK() {
+		return 0, false
+	}
+	fields := strings.Fields(r.Data)
+	if len(fields) != 2 || fields[0] != "step" {
+		return 0, false
+	}
+	line, err := strconv.Atoi(fields[1])
+	if err != nil || line < 0 || line > 32767 {
+		return 0, false
+	}
+	return line, true
+}
+
+// AsLineRefs parses a "basic REFS" response such as "refs 20,150,300" or
+// "refs (none)" into the numbers of the referring lines.
+func (r Response) AsLineRefs() ([]int, error) {
+	if err := r.Err(); err != nil {
+		return nil, err
+	}
+	fields := strings.Fields(r.Data)
+	if len(fields) == 0 || fields[0] != "refs" || len(fields) > 2 {
+		return nil, newUnexpectedResponseError(r.Data)
+	}
+	if len(fields) == 1 || fields[1] == "(none)" {
+		return nil, nil
+	}
+	var lines []int
+	for _, s := range strings.Split(fields[1], ",") {
+		line, err := strconv.Atoi(s)
+		if err != nil || line < 0 || line > 32767 {
+			return nil, newInvalidValueError(s)
+		}
+		lines = append(lines, line)
+	}
+	return lines, nil
+}
+
+// AsChecksum parses a "checksum" response such as "checksum $1A2B3C4D"
+// into the CRC-32 (IEEE) of the range, as hash/crc32 computes it.
+func (r Response) AsChecksum() (uint32, error) {
+	if err := r.Err(); err != nil {
+		return 0, err
+	}
+	fields := strings.Fields(r.Data)
+	if len(fields) != 2 || fields[0] != "checksum" || !strings.HasPrefix(fields[1], "$") {
+		return 0, newUnexpectedResponseError(r.Data)
+	}
+	sum, err := strconv.ParseUint(fields[1][1:], 16, 32)
+	if err != nil {
+		return 0, newInvalidValueError(fields[1])
+	}
+	return uint32(sum), nil
+}
+
+// BootSource is the kind of media the emulator booted from.
+type BootSource int
+
+const (
+	// BootNone means nothing was booted: the built-in BASIC or self test.
+	BootNone BootSource = iota
+	// BootDisk is a disk image in drive 1.
+	BootDisk
+	// BootExecutable is a binary load file such as a XEX.
+	BootExecutable
+	// BootCartridge is a cartridge image.
+	BootCartridge
+)
+
+// bootSourceNames maps BootSource values to their protocol names.
+var bootSourceNames = map[string]BootSource{
+	"none": BootNone,
+	"disk": BootDisk,
+	"xex":  BootExecutable,
+	"cart": BootCartridge,
+}
+
+// BootInfo is the parsed form of a "bootinfo" response.
+type BootInfo struct {
+	Source BootSource
+	Drive  int    // Drive the disk booted from (BootDisk only)
+	Path   string // Host path of the media; empty for BootNone
+}
+
+// AsBootInfo parses a "bootinfo" response: "bootinfo disk 1 /path/d.atr",
+// "bootinfo xex /path/game.xex", "bootinfo cart /path/game.car" or
+// "bootinfo none". The path is the rest of the line and may hold spaces.
+func (r Response) AsBootInfo() (BootInfo, error) {
+	if err := r.Err(); err != nil {
+		return BootInfo{}, err
+	}
+	fields := strings.Fields(r.Data)
+	if len(fields) < 2 || fields[0] != "bootinfo" {
+		return BootInfo{}, newUnexpectedResponseError(r.Data)
+	}
+	source, ok := bootSourceNames[fields[1]]
+	if !ok {
+		return BootInfo{}, newUnexpectedResponseError(r.Data)
+	}
+	info := BootInfo{Source: source}
+	if source == BootNone {
+		if len(fields) != 2 {
+			return BootInfo{}, newUnexpectedResponseError(r.Data)
+		}
+		return info, nil
+	}
+
+	// Skip "bootinfo <source>" (and the drive) to keep spaces in the path.
+	skip := 2
+	if source == BootDisk {
+		if len(fields) < 3 {
+			return BootInfo{}, newUnexpectedResponseError(r.Data)
+		}
+		drive, err := strconv.Atoi(fields[2])
+		if err != nil || drive < 1 || drive > 8 {
+			return BootInfo{}, newInvalidValueError(fields[2])
+		}
+		info.Drive = drive
+		skip = 3
+	}
+	rest := strings.TrimSpace(r.Data)
+	for i := 0; i < skip; i++ {
+		rest = strings.TrimLeft(rest[len(fields[i]):], " \t")
+	}
+	if rest == "" {
+		return BootInfo{}, newUnexpectedResponseError(r.Data)
+	}
+	info.Path = rest
+	return info, nil
+}
+
+// BasicVarKind classifies a BASIC variable.
+type BasicVarKind int
+
+const (
+	// BasicVarNumeric is a numeric variable such as X.
+	BasicVarNumeric BasicVarKind = iota
+	// BasicVarString is a string variable such as A$.
+	BasicVarString
+	// BasicVarArray is a dimensioned array such as B(10).
+	BasicVarArray
+)
+
+// BasicVar is one entry of a "basic vars" listing.
+type BasicVar struct {
+	Name  string // Variable name as listed, e.g. "X", "A$", "B(10)"
+	Kind  BasicVarKind
+	Value string // Value text as listed, e.g. "42" or "\"HELLO\""
+}
+
+// AsBasicVars parses a "basic vars" response, one "NAME=VALUE" entry per
+// line, into BasicVar values. The kind is taken from the name: a trailing
+// "$" marks a string and parentheses mark an array.
+func (r Response) AsBasicVars() ([]BasicVar, error) {
+	if err := r.Err(); err != nil {
+		return nil, err
+	}
+
+	var vars []BasicVar
+	for _, line := range r.Lines() {
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+		name, value, ok := strings.Cut(line, "=")
+		name = strings.TrimSpace(name)
+		if !ok || name == "" {
+			return nil, newUnexpectedResponseError(line)
+		}
+
+		v := BasicVar{Name: name, Kind: BasicVarNumeric, Value: strings.TrimSpace(value)}
+		switch {
+		case strings.Contains(name, "("):
+			v.Kind = BasicVarArray
+		case strings.HasSuffix(name, "$"):
+			v.Kind = BasicVarString
+		}
+		vars = append(vars, v)
+	}
+	return vars, nil
+}
+
+// BasicLine is a numbered line of a BASIC program.
+type BasicLine struct {
+	Number int
+	Text   string // The line as listed, without its number
+}
+
+// AsFindResults parses a "basic FIND" response, one listed line per line
+// such as "10 PRINT \"HI\"", into BasicLine values. No match is an empty
+// response.
+func (r Response) AsFindResults() ([]BasicLine, error) {
+	if err := r.Err(); err != nil {
+		return nil, err
+	}
+
+	var lines []BasicLine
+	for _, line := range r.Lines() {
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+		number, text, _ := strings.Cut(strings.TrimSpace(line), " ")
+		n, err := strconv.Atoi(number)
+		if err != nil || n < 0 || n > 32767 {
+			return nil, newUnexpectedResponseError(line)
+		}
+		lines = append(lines, BasicLine{Number: n, Text: text})
+	}
+	return lines, nil
+}
+
+// Status is the parsed form of a "status" response.
+type Status struct {
+	Running     bool           // true if running, false if paused
+	PC          uint16         // Program counter
+	Drives      map[int]string // Mounted disk path per drive (1-8); empty drives are omitted
+	Breakpoints []uint16       // Breakpoint addresses
+}
+
+// AsStatus parses a "status" response such as
+// "status running PC=$E477 D1=/path/disk.atr D2=(none) BP=$600A,$602F".
+// Unknown fields are ignored so newer servers can add to the line.
+func (r Response) AsStatus() (Status, error) {
+	if err := r.Err(); err != nil {
+		return Status{}, err
+	}
+	fields := strings.Fields(r.Data)
+	if len(fields) < 2 || fields[0] != "status" {
+		return Status{}, newUnexpectedResponseError(r.Data)
+	}
+
+	status := Status{Drives: make(map[int]string)}
+	switch fields[1] {
+	case "running":
+		status.Running = true
+	case "paused":
+	default:
+		return Status{}, newUnexpectedResponseError(r.Data)
+	}
+
+	for _, field := range fields[2:] {
+		key, value, ok := strings.Cut(field, "=")
+		if !ok {
+			continue
+		}
+		switch {
+		case key == "PC":
+			pc, ok := parseAddress(value)
+			if !ok {
+				return Status{}, newInvalidAddressError(value)
+			}
+			status.PC = pc
+		case key == "BP":
+			if value == "(none)" {
+				continue
+			}
+			for _, bp := range strings.Split(value, ",") {
+				addr, ok := parseAddress(bp)
+				if !ok {
+					return Status{}, newInvalidAddressError(bp)
+				}
+				status.Breakpoints = append(status.Breakpoints, addr)
+			}
+		case len(key) == 2 && key[0] == 'D' && key[1] >= '1' && key[1] <= '8':
+			if value != "(none)" {
+				status.Drives[int(key[1]-'0')] = value
+			}
+		}
+	}
+	return status, nil
+}
+
+// Registers holds the 6502 register values from a "registers" response.
+type Registers struct {
+	A, X, Y uint8
+	S, P    uint8 // Stack pointer and processor status
+	PC      uint16
+}
+
+// AsRegisters parses a "registers" response such as
+// "A=$00 X=$00 Y=$00 S=$FF P=$34 PC=$E477". Other words, like the
+// "stepped" prefix of a step response, are ignored, but all six registers
+// must be present.
+func (r Response) AsRegisters() (Registers, error) {
+	if err := r.Err(); err != nil {
+		return Registers{}, err
+	}
+
+	var regs Registers
+	seen := 0
+	for _, field := range strings.Fields(r.Data) {
+		name, value, ok := strings.Cut(field, "=")
+		if !ok {
+			continue
+		}
+		bits := 8
+		if strings.ToUpper(name) == "PC" {
+			bits = 16
+		}
+		n, err := strconv.ParseUint(strings.TrimPrefix(value, "$"), 16, bits)
+		if err != nil {
+			return Registers{}, newInvalidValueError(value)
+		}
+		switch strings.ToUpper(name) {
+		case "A":
+			regs.A = uint8(n)
+		case "X":
+			regs.X = uint8(n)
+		case "Y":
+			regs.Y = uint8(n)
+		case "S":
+			regs.S = uint8(n)
+		case "P":
+			regs.P = uint8(n)
+		case "PC":
+			regs.PC = uint16(n)
+		default:
+			continue
+		}
+		seen++
+	}
+	if seen != 6 {
+		return Registers{}, newUnexpectedResponseError(r.Data)
+	}
+	return regs, nil
+}
+
+// MemoryRegion is one entry of a "memmap" response.
+type MemoryRegion struct {
+	Start, End uint16 // Inclusive address range
+	Kind       string // "RAM", "ROM" or "IO"
+	Name       string // e.g. "BASIC" or "GTIA", may be empty
+}
+
+// AsMemoryMap parses a "memmap" response, one region per line in the form
+// "$A000-$BFFF ROM BASIC". The name is optional and may contain spaces.
+func (r Response) AsMemoryMap() ([]MemoryRegion, error) {
+	if err := r.Err(); err != nil {
+		return nil, err
+	}
+
+	var regions []MemoryRegion
+	for _, line := range r.Lines() {
+		fields := strings.Fields(line)
+		if len(fields) == 0 {
+			continue
+		}
+		if len(fields) < 2 {
+			return nil, newUnexpectedResponseError(line)
+		}
+		startText, endText, ok := strings.Cut(fields[0], "-")
+		if !ok {
+			return nil, newUnexpectedResponseError(line)
+		}
+		start, ok := parseAddress(startText)
+		if !ok {
+			return nil, newInvalidAddressError(startText)
+		}
+		end, ok := parseAddress(endText)
+		if !ok || end < start {
+			return nil, newInvalidAddressError(endText)
+		}
+		regions = append(regions, MemoryRegion{
+			Start: start,
+			End:   end,
+			Kind:  strings.ToUpper(fields[1]),
+			Name:  strings.Join(fields[2:], " "),
+		})
+	}
+	return regions, nil
+}
+
+// DisassemblyLine is one instruction of a "disassemble" listing.
+type DisassemblyLine struct {
+	Address     uint16
+	Bytes       []byte // Instruction bytes, 1 to 3
+	Instruction string // Mnemonic and operand, e.g. "LDA #$00" or "STA DMACTL"
+}
+
+// AsDisassembly parses a "disassemble" response, one instruction per line
+// in the form "$0600  A9 00     LDA #$00".
+func (r Response) AsDisassembly() ([]DisassemblyLine, error) {
+	if err := r.Err(); err != nil {
+		return nil, err
+	}
+
+	var lines []DisassemblyLine
+	for _, text := range r.Lines() {
+		fields := strings.Fields(text)
+		if len(fields) == 0 {
+			continue
+		}
+		address, ok := parseAddress(fields[0])
+		if !ok || !strings.HasPrefix(fields[0], "$") {
+			return nil, newUnexpectedResponseError(text)
+		}
+
+		line := DisassemblyLine{Address: address}
+		rest := fields[1:]
+		for len(rest) > 0 && len(rest[0]) == 2 && len(line.Bytes) < 3 {
+			b, ok := parseHexByte(rest[0])
+			if !ok {
+				break
+			}
+			line.Bytes = append(line.Bytes, b)
+			rest = rest[1:]
+		}
+		if len(line.Bytes) == 0 || len(rest) == 0 {
+			return nil, newUnexpectedResponseError(text)
+		}
+		line.Instruction = strings.Join(rest, " ")
+		lines = append(lines, line)
+	}
+	return lines, nil
+}
+
+// AsAssembleInput splits the data of an "assemble input" response, such
+// as "$0600: A9 00     LDA #$00\x1E$0602", into the formatted line and the
+// address the next instruction goes to. The two are separated by
+// Separator. ok is false if the address is missing or malformed, and for
+// an error response.
+func (r Response) AsAssembleInput() (line string, nextAddr uint16, ok bool) {
+	if !r.IsOK() {
+		return "", 0, false
+	}
+	line, next, found := strings.Cut(r.Data, r.Separator())
+	next = strings.TrimSpace(next)
+	if !found || strings.TrimSpace(line) == "" || !strings.HasPrefix(next, "$") {
+		return "", 0, false
+	}
+	nextAddr, ok = parseAddress(next)
+	if !ok {
+		return "", 0, false
+	}
+	return line, nextAddr, true
+}
+
+// ParseAssembleInputResponse is AsAssembleInput for response data on its
+// own. The data must use the default MultiLineSeparator (\x1E); use
+// Response.AsAssembleInput for a client with another separator.
+func ParseAssembleInputResponse(data string) (line string, nextAddr uint16, ok bool) {
+	return Response{Type: ResponseOK, Data: data}.AsAssembleInput()
+}
+
+// AsBreakpoints parses a "breakpoint list" response such as
+// "breakpoints $600A,$602F" or "breakpoints (none)" into addresses. A
+// detailed listing is accepted too, in which case only the addresses are
+// returned.
+func (r Response) AsBreakpoints() ([]uint16, error) {
+	if err := r.Err(); err != nil {
+		return nil, err
+	}
+	if strings.Contains(r.Data, r.Separator()) {
+		detailed, err := r.AsBreakpointsDetailed()
+		if err != nil {
+			return nil, err
+		}
+		addresses := make([]uint16, len(detailed))
+		for i, bp := range detailed {
+			addresses[i] = bp.Address
+		}
+		return addresses, nil
+	}
+
+	fields := strings.Fields(r.Data)
+	if len(fields) == 0 || fields[0] != "breakpoints" || len(fields) > 2 {
+		return nil, newUnexpectedResponseError(r.Data)
+	}
+	if len(fields) == 1 || fields[1] == "(none)" {
+		return nil, nil
+	}
+	var addresses []uint16
+	for _, bp := range strings.Split(fields[1], ",") {
+		addr, ok := parseAddress(bp)
+		if !ok {
+			return nil, newInvalidAddressError(bp)
+		}
+		addresses = append(addresses, addr)
+	}
+	return addresses, nil
+}
+
+// Breakpoint is one entry of a "breakpoint list detailed" response.
+type Breakpoint struct {
+	Address   uint16
+	Condition string // Expression that must hold to stop, empty if unconditional
+	HitCount  int    // Stop on the Nth hit (0 = every hit)
+	Once      bool   // Removed after the first stop
+	Enabled   bool
+}
+
+// AsBreakpointsDetailed parses a "breakpoint list detailed" response. The
+// first line is "breakpoints", followed by one breakpoint per line:
+//
+//	$600A
+//	$602F hits 5 once
+//	$E477 disabled if A==$00
+//
+// The "hits", "once" and "disabled" attributes may appear in any order;
+// "if" must come last and takes the rest of the line as the condition. An
+// empty list is "breakpoints (none)".
+func (r Response) AsBreakpointsDetailed() ([]Breakpoint, error) {
+	if err := r.Err(); err != nil {
+		return nil, err
+	}
+	lines := r.Lines()
+	if len(lines) == 0 {
+		return nil, newUnexpectedResponseError(r.Data)
+	}
+	if header := strings.TrimSpace(lines[0]); header != "breakpoints" && header != "breakpoints (none)" {
+		return nil, newUnexpectedResponseError(r.Data)
+	}
+
+	var breakpoints []Breakpoint
+	for _, line := range lines[1:] {
+		fields := strings.Fields(line)
+		if len(fields) == 0 {
+			continue
+		}
+		address, ok := parseAddress(fields[0])
+		if !ok || !strings.HasPrefix(fields[0], "$") {
+			return nil, newInvalidAddressError(fields[0])
+		}
+
+		bp := Breakpoint{Address: address, Enabled: true}
+	attributes:
+		for i := 1; i < len(fields); i++ {
+			switch strings.ToLower(fields[i]) {
+			case "hits":
+				if i+1 >= len(fields) {
+					return nil, newUnexpectedResponseError(line)
+				}
+				i++
+				hits, err := strconv.Atoi(fields[i])
+				if err != nil || hits < 1 {
+					return nil, newInvalidCountError(fields[i])
+				}
+				bp.HitCount = hits
+			case "once":
+				bp.Once = true
+			case "disabled":
+				bp.Enabled = false
+			case "enabled":
+				bp.Enabled = true
+			case "if":
+				bp.Condition = strings.Join(fields[i+1:], " ")
+				if bp.Condition == "" {
+					return nil, newUnexpectedResponseError(line)
+				}
+				break attributes
+			default:
+				return nil, newUnexpectedResponseError(line)
+			}
+		}
+		breakpoints = append(breakpoints, bp)
+	}
+	return breakpoints, nil
+}
+
+// DosFileInfo is the parsed form of a "dos info" response.
+type DosFileInfo struct {
+	Name    string
+	Bytes   int
+	Sectors int
+	Locked  bool
+}
+
+// AsDosFileInfo parses a "dos info" response. Both the single-line form
+// "GAME.BAS 2048 bytes 8 sectors locked=0" and the labelled multi-line form
+// ("Name: GAME.BAS", "Size: 2048 bytes (8 sectors)", "Locked: no") are
+// accepted. Fields may appear in any order: sizes as "2048 bytes" or
+// "bytes=2048", the lock state as "locked=<bool>", "Locked: yes/no" or a
+// bare "locked"/"unlocked". The filename is the first field unless it is
+// labelled. Unknown words are ignored.
+func (r Response) AsDosFileInfo() (DosFileInfo, error) {
+	if err := r.Err(); err != nil {
+		return DosFileInfo{}, err
+	}
+
+	var info DosFileInfo
+	haveBytes, haveSectors := false, false
+	fields := strings.Fields(strings.NewReplacer("(", " ", ")", " ").Replace(strings.Join(r.Lines(), " ")))
+	for i := 0; i < len(fields); i++ {
+		field := fields[i]
+		key, value, isPair := strings.Cut(field, "=")
+		switch {
+		case isPair:
+		case strings.HasSuffix(field, ":"):
+			// "Name: GAME.BAS", "Locked: yes"; other labels such as
+			// "Size:" just introduce the fields that follow.
+			key = strings.TrimSuffix(field, ":")
+			if k := strings.ToLower(key); (k != "name" && k != "locked") || i+1 == len(fields) {
+				continue
+			}
+			value = fields[i+1]
+			i++
+		case isDecimal(field) && i+1 < len(fields):
+			// "2048 bytes", "8 sectors"
+			key, value = fields[i+1], field
+			i++
+		default:
+			switch strings.ToLower(field) {
+			case "locked":
+				info.Locked = true
+			case "unlocked":
+				info.Locked = false
+			default:
+				if i == 0 {
+					info.Name = field
+				}
+			}
+			continue
+		}
+
+		switch strings.ToLower(key) {
+		case "name":
+			info.Name = value
+		case "bytes":
+			n, err := strconv.Atoi(value)
+			if err != nil || n < 0 {
+				return DosFileInfo{}, newUnexpectedResponseError(r.Data)
+			}
+			info.Bytes, haveBytes = n, true
+		case "sectors":
+			n, err := strconv.Atoi(value)
+			if err != nil || n < 0 {
+				return DosFileInfo{}, newUnexpectedResponseError(r.Data)
+			}
+			info.Sectors, haveSectors = n, true
+		case "locked":
+			switch strings.ToLower(value) {
+			case "1", "true", "yes":
+				info.Locked = true
+			case "0", "false", "no":
+				info.Locked = false
+			default:
+				return DosFileInfo{}, newUnexpectedResponseError(r.Data)
+			}
+		}
+	}
+
+	if info.Name == "" || !haveBytes || !haveSectors {
+		return DosFileInfo{}, newUnexpectedResponseError(r.Data)
+	}
+	return info, nil
+}
+
+// isDecimal reports whether s is a non-empty string of decimal digits.
+func isDecimal(s string) bool {
+	if s == "" {
+		return false
+	}
+	for _, c := range s {
+		if c < '0' || c > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+// DirEntry is one file in a "dos dir" or "basic dir" listing.
+type DirEntry struct {
+	Name    string // Filename with extension, e.g. "GAME.BAS"
+	Sectors int
+	Locked  bool
+}
+
+// DiskCheckResult is the outcome of a "dos check" of the current disk.
+type DiskCheckResult struct {
+	// Problems describes each inconsistency found, e.g. "sector 45 used
+	// by GAME.BAS but free in VTOC". It is empty for a clean disk.
+	Problems []string
+}
+
+// Clean reports whether the check found no problems.
+func (d DiskCheckResult) Clean() bool {
+	return len(d.Problems) == 0
+}
+
+// AsDiskCheck parses a "dos check" response: "clean", or "errors N"
+// followed by one line per problem.
+func (r Response) AsDiskCheck() (DiskCheckResult, error) {
+	if err := r.Err(); err != nil {
+		return DiskCheckResult{}, err
+	}
+	lines := r.Lines()
+	if len(lines) == 0 {
+		return DiskCheckResult{}, newUnexpectedResponseError(r.Data)
+	}
+	fields := strings.Fields(lines[0])
+	switch {
+	case len(fields) == 1 && fields[0] == "clean" && len(lines) == 1:
+		return DiskCheckResult{}, nil
+	case len(fields) == 2 && fields[0] == "errors":
+		count, err := strconv.Atoi(fields[1])
+		if err != nil || count < 1 {
+			return DiskCheckResult{}, newInvalidValueError(fields[1])
+		}
+		problems := lines[1:]
+		if len(problems) != count {
+			return DiskCheckResult{}, newUnexpectedResponseError(r.Data)
+		}
+		return DiskCheckResult{Problems: problems}, nil
+	default:
+		return DiskCheckResult{}, newUnexpectedResponseError(r.Data)
+	}
+}
+
+// AsDiffResult parses a "dos diff" response: "match" if the files are
+// identical, or "differ 1100" with the offset of the first differing byte.
+// Files of different lengths differ at the end of the shorter one. offset
+// is -1 when they match.
+func (r Response) AsDiffResult() (match bool, offset int, err error) {
+	if err := r.Err(); err != nil {
+		return false, 0, err
+	}
+	fields := strings.Fields(r.Data)
+	switch {
+	case len(fields) == 1 && fields[0] == "match":
+		return true, -1, nil
+	case len(fields) == 2 && fields[0] == "differ":
+		offset, err := strconv.Atoi(fields[1])
+		if err != nil || offset < 0 {
+			return false, 0, newInvalidValueError(fields[1])
+		}
+		return false, offset, nil
+	default:
+		return false, 0, newUnexpectedResponseError(r.Data)
+	}
+}
+
+// AsDirectory parses a "dos dir" or "basic dir" listing, one file per line,
+// such as "* GAME.BAS      12 sectors" or the classic Atari DIR layout
+// "*GAME     BAS 012". A leading "*" marks a locked file. The
+// "nnn FREE SECTORS" footer and the "(empty disk)" placeholder are skipped.
+func (r Response) AsDirectory() ([]DirEntry, error) {
+	if err := r.Err(); err != nil {
+		return nil, err
+	}
+
+	var entries []DirEntry
+	for _, line := range r.Lines() {
+		line = strings.TrimSpace(line)
+		lower := strings.ToLower(line)
+		if line == "" || line == "(empty disk)" || strings.HasSuffix(lower, "free sectors") {
+			continue
+		}
+
+		var entry DirEntry
+		if strings.HasPrefix(line, "*") {
+			entry.Locked = true
+			line = line[1:]
+		}
+		fields := strings.Fields(line)
+		if n := len(fields); n > 0 && strings.EqualFold(strings.TrimSuffix(fields[n-1], "s"), "sector") {
+			fields = fields[:n-1]
+		}
+		if len(fields) < 2 || len(fields) > 3 || !isDecimal(fields[len(fields)-1]) {
+			return nil, newUnexpectedResponseError(line)
+		}
+		entry.Sectors, _ = strconv.Atoi(fields[len(fields)-1])
+		entry.Name = fields[0]
+		if len(fields) == 3 {
+			// Name and extension in separate columns
+			if strings.Contains(fields[0], ".") {
+				return nil, newUnexpectedResponseError(line)
+			}
+			entry.Name += "." + fields[1]
+		}
+		entries = append(entries, entry)
+	}
+	return entries, nil
 }
 
 // EventType represents the type of async event from the server.